@@ -11,9 +11,9 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/twin2ai/studio/internal/assets"
 	"github.com/twin2ai/studio/internal/config"
-	"github.com/twin2ai/studio/internal/gemini"
 	"github.com/twin2ai/studio/internal/github"
 	"github.com/twin2ai/studio/internal/prompts"
+	"github.com/twin2ai/studio/internal/provider"
 )
 
 func main() {
@@ -57,13 +57,19 @@ func main() {
 		if cfg.GitHub.Token == "" {
 			logger.Fatalf("GitHub token required for PR creation. Please set GITHUB_TOKEN in your .env file or environment.")
 		}
-		if cfg.AI.Gemini.APIKey == "" {
-			logger.Fatalf("Gemini API key required for prompt generation. Please set GOOGLE_API_KEY in your .env file or environment.")
+		if !cfg.AI.HasSynthesizerKey() {
+			logger.Fatalf("An API key for a synthesis provider is required for prompt generation. Please set the key for one of SYNTHESIS_PROVIDERS in your .env file or environment.")
 		}
 	}
 
-	// Create Gemini client
-	geminiClient := gemini.NewClient(cfg.AI.Gemini.APIKey, cfg.AI.Gemini.Model, logger)
+	// Pace, guard and record provider calls like the studio binary
+	provider.Setup(cfg, logger, false)
+
+	// Create the synthesis providers used to write prompts
+	synthesis, err := provider.NewSynthesisChain(cfg.AI.Synthesizers, logger)
+	if err != nil {
+		logger.Fatalf("Failed to create synthesis chain: %v", err)
+	}
 
 	// Create GitHub client if PR creation is enabled
 	var githubService *prompts.GitHubService
@@ -80,12 +86,12 @@ func main() {
 			logger,
 		)
 
-		githubService = prompts.NewGitHubService(geminiClient, githubClient, logger, *baseDir)
+		githubService = prompts.NewGitHubService(synthesis, githubClient, logger, *baseDir)
 		monitor = assets.NewMonitor(*baseDir, logger)
 		githubService.RegisterCallbacks(monitor)
 	} else {
 		// Use regular prompt service without GitHub integration
-		promptService := prompts.NewService(geminiClient, logger, *baseDir)
+		promptService := prompts.NewService(synthesis, logger, *baseDir)
 		monitor = assets.NewMonitor(*baseDir, logger)
 		promptService.RegisterCallbacks(monitor)
 	}
@@ -10,8 +10,8 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/twin2ai/studio/internal/assets"
 	"github.com/twin2ai/studio/internal/config"
-	"github.com/twin2ai/studio/internal/gemini"
 	"github.com/twin2ai/studio/internal/prompts"
+	"github.com/twin2ai/studio/internal/provider"
 )
 
 func main() {
@@ -42,11 +42,17 @@ func main() {
 		logger.Fatalf("Failed to load config: %v", err)
 	}
 
-	// Create Gemini client
-	geminiClient := gemini.NewClient(cfg.AI.Gemini.APIKey, cfg.AI.Gemini.Model, logger)
+	// Pace, guard and record provider calls like the studio binary
+	provider.Setup(cfg, logger, false)
+
+	// Create the synthesis providers used to write prompts
+	synthesis, err := provider.NewSynthesisChain(cfg.AI.Synthesizers, logger)
+	if err != nil {
+		logger.Fatalf("Failed to create synthesis chain: %v", err)
+	}
 
 	// Create prompt service
-	promptService := prompts.NewService(geminiClient, logger, *baseDir)
+	promptService := prompts.NewService(synthesis, logger, *baseDir)
 
 	// Create asset monitor
 	monitor := assets.NewMonitor(*baseDir, logger)
@@ -2,25 +2,34 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"syscall"
+	"text/tabwriter"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/sirupsen/logrus"
 
-	"github.com/twin2ai/studio/internal/claude"
 	"github.com/twin2ai/studio/internal/config"
-	"github.com/twin2ai/studio/internal/gemini"
+	"github.com/twin2ai/studio/internal/dryrun"
 	githubclient "github.com/twin2ai/studio/internal/github"
-	"github.com/twin2ai/studio/internal/gpt"
-	"github.com/twin2ai/studio/internal/grok"
+	"github.com/twin2ai/studio/internal/llm"
 	"github.com/twin2ai/studio/internal/multiprovider"
 	"github.com/twin2ai/studio/internal/pipeline"
+	"github.com/twin2ai/studio/internal/provider"
 	"github.com/twin2ai/studio/internal/synthesizer"
+	"github.com/twin2ai/studio/internal/usage"
+	"github.com/twin2ai/studio/internal/validation"
+	"github.com/twin2ai/studio/pkg/export"
+	"github.com/twin2ai/studio/pkg/models"
 )
 
 func main() {
@@ -33,9 +42,15 @@ func main() {
 	logger := setupLogger()
 
 	// Check for subcommands
-	if len(os.Args) < 2 {
+	if len(os.Args) < 2 || isRunFlag(os.Args[1]) {
 		// No subcommand provided, run the default pipeline
-		runPipeline(logger)
+		runCmd := flag.NewFlagSet("studio", flag.ExitOnError)
+		dryRun := runCmd.Bool("dry-run", false, "Run once with fake AI providers and record GitHub writes instead of sending them")
+		if err := runCmd.Parse(os.Args[1:]); err != nil {
+			logger.Fatalf("Failed to parse flags: %v", err)
+		}
+
+		runPipeline(logger, *dryRun)
 		return
 	}
 
@@ -44,6 +59,7 @@ func main() {
 	case "synthesize":
 		// Handle synthesize subcommand
 		synthesizeCmd := flag.NewFlagSet("synthesize", flag.ExitOnError)
+		dryRun := synthesizeCmd.Bool("dry-run", false, "Use fake AI providers and record GitHub writes instead of sending them")
 		synthesizeCmd.Usage = func() {
 			fmt.Fprintf(os.Stderr, "Usage: studio synthesize [persona-name]\n")
 			fmt.Fprintf(os.Stderr, "\nRegenerates synthesized.md from existing raw AI outputs.\n")
@@ -61,12 +77,13 @@ func main() {
 			personaName = synthesizeCmd.Arg(0)
 		}
 
-		runSynthesize(logger, personaName)
+		runSynthesize(logger, personaName, *dryRun)
 
 	case "batch":
 		// Handle batch subcommand
 		batchCmd := flag.NewFlagSet("batch", flag.ExitOnError)
 		force := batchCmd.Bool("force", false, "Force generation even if persona already exists")
+		dryRun := batchCmd.Bool("dry-run", false, "Use fake AI providers and record GitHub writes instead of sending them")
 		batchCmd.Usage = func() {
 			fmt.Fprintf(os.Stderr, "Usage: studio batch [options] <file.txt>\n")
 			fmt.Fprintf(os.Stderr, "\nGenerates personas from a list of names in a text file.\n")
@@ -86,7 +103,151 @@ func main() {
 		}
 
 		filePath := batchCmd.Arg(0)
-		runBatch(logger, filePath, *force)
+		runBatch(logger, filePath, *force, *dryRun)
+
+	case "usage":
+		// Handle usage subcommand
+		usageCmd := flag.NewFlagSet("usage", flag.ExitOnError)
+		since := usageCmd.String("since", "", "Only include calls on or after this date (YYYY-MM-DD)")
+		by := usageCmd.String("by", "persona,provider,day", "Comma-separated groupings: persona, provider, day, stage")
+		usageCmd.Usage = func() {
+			fmt.Fprintf(os.Stderr, "Usage: studio usage [options]\n")
+			fmt.Fprintf(os.Stderr, "\nReports token usage and estimated cost from the usage ledger.\n\n")
+			usageCmd.PrintDefaults()
+		}
+
+		if err := usageCmd.Parse(os.Args[2:]); err != nil {
+			logger.Fatalf("Failed to parse usage command: %v", err)
+		}
+
+		runUsage(logger, *since, *by)
+
+	case "providers":
+		// Handle providers subcommand
+		providersCmd := flag.NewFlagSet("providers", flag.ExitOnError)
+		models := providersCmd.Bool("models", false, "Also list the models available to each provider")
+		dryRun := providersCmd.Bool("dry-run", false, "Check the fake AI providers instead of the real APIs")
+		providersCmd.Usage = func() {
+			fmt.Fprintf(os.Stderr, "Usage: studio providers status [options]\n")
+			fmt.Fprintf(os.Stderr, "\nTests the connection of every generation and synthesis provider\n")
+			fmt.Fprintf(os.Stderr, "and shows its circuit breaker state.\n\n")
+			providersCmd.PrintDefaults()
+		}
+
+		if len(os.Args) < 3 || os.Args[2] != "status" {
+			providersCmd.Usage()
+			os.Exit(1)
+		}
+		if err := providersCmd.Parse(os.Args[3:]); err != nil {
+			logger.Fatalf("Failed to parse providers command: %v", err)
+		}
+
+		runProvidersStatus(logger, *models, *dryRun)
+
+	case "validate":
+		// Handle validate subcommand
+		validateCmd := flag.NewFlagSet("validate", flag.ExitOnError)
+		jsonOutput := validateCmd.Bool("json", false, "Print the reports as JSON")
+		minWords := validateCmd.Int("min-words", 0, "Words below which a section is short (default VALIDATION_MIN_WORDS)")
+		dryRun := validateCmd.Bool("dry-run", false, "Validate the sample personas instead of the personas repository")
+		validateCmd.Usage = func() {
+			fmt.Fprintf(os.Stderr, "Usage: studio validate [options] [persona-name | file.md]\n")
+			fmt.Fprintf(os.Stderr, "\nChecks synthesized.md against templates/persona_template.md: required\n")
+			fmt.Fprintf(os.Stderr, "sections, section length, leftover placeholders, unbalanced markdown and\n")
+			fmt.Fprintf(os.Stderr, "chat preambles. If no persona is given, validates all personas.\n\n")
+			validateCmd.PrintDefaults()
+		}
+
+		if err := validateCmd.Parse(os.Args[2:]); err != nil {
+			logger.Fatalf("Failed to parse validate command: %v", err)
+		}
+
+		// Get optional persona name or file
+		target := ""
+		if validateCmd.NArg() > 0 {
+			target = validateCmd.Arg(0)
+		}
+
+		runValidate(logger, target, *minWords, *jsonOutput, *dryRun)
+
+	case "history":
+		// Handle history subcommand
+		historyCmd := flag.NewFlagSet("history", flag.ExitOnError)
+		dryRun := historyCmd.Bool("dry-run", false, "Read the sample personas instead of the personas repository")
+		historyCmd.Usage = func() {
+			fmt.Fprintf(os.Stderr, "Usage: studio history [options] <persona-name> [version]\n")
+			fmt.Fprintf(os.Stderr, "\nLists the versions in a persona's CHANGELOG.md: trigger, synthesizer and\n")
+			fmt.Fprintf(os.Stderr, "what changed. With a version number, prints that version's snapshot.\n\n")
+			historyCmd.PrintDefaults()
+		}
+
+		if err := historyCmd.Parse(os.Args[2:]); err != nil {
+			logger.Fatalf("Failed to parse history command: %v", err)
+		}
+		if historyCmd.NArg() < 1 {
+			historyCmd.Usage()
+			os.Exit(1)
+		}
+
+		version := 0
+		if historyCmd.NArg() > 1 {
+			n, err := strconv.Atoi(strings.TrimPrefix(historyCmd.Arg(1), "v"))
+			if err != nil || n < 1 {
+				logger.Fatalf("Invalid version %q: expected a number such as 3 or v3", historyCmd.Arg(1))
+			}
+			version = n
+		}
+
+		runHistory(logger, historyCmd.Arg(0), version, *dryRun)
+
+	case "export":
+		// Handle export subcommand
+		exportCmd := flag.NewFlagSet("export", flag.ExitOnError)
+		format := exportCmd.String("format", string(export.FormatCard), "Comma-separated export formats, or \"all\"")
+		output := exportCmd.String("o", "", "Output file, or directory for several formats (default stdout)")
+		model := exportCmd.String("model", "", fmt.Sprintf("Base model of assistants and Modelfiles (default %s, %s)", export.DefaultAssistantModel, export.DefaultOllamaModel))
+		creator := exportCmd.String("creator", "", "Creator credited in character cards")
+		avatar := exportCmd.String("avatar", "", "PNG image to embed a card-png export in")
+		dryRun := exportCmd.Bool("dry-run", false, "Export from the sample personas instead of the personas repository")
+		exportCmd.Usage = func() {
+			fmt.Fprintf(os.Stderr, "Usage: studio export [options] <persona-name | folder>\n")
+			fmt.Fprintf(os.Stderr, "\nConverts a persona folder from the personas repository, or a local one, into\n")
+			fmt.Fprintf(os.Stderr, "other runtimes' formats: card (Character Card V2 JSON), card-png (the card\n")
+			fmt.Fprintf(os.Stderr, "embedded in a PNG), assistant (OpenAI Assistants), ollama (Modelfile), json, yaml.\n\n")
+			exportCmd.PrintDefaults()
+		}
+
+		if err := exportCmd.Parse(os.Args[2:]); err != nil {
+			logger.Fatalf("Failed to parse export command: %v", err)
+		}
+		if exportCmd.NArg() < 1 {
+			exportCmd.Usage()
+			os.Exit(1)
+		}
+
+		var formats []export.Format
+		if *format == "all" {
+			formats = export.Formats
+		} else {
+			for _, name := range strings.Split(*format, ",") {
+				f, err := export.ParseFormat(strings.TrimSpace(name))
+				if err != nil {
+					logger.Fatal(err)
+				}
+				formats = append(formats, f)
+			}
+		}
+
+		opts := export.Options{Model: *model, Creator: *creator}
+		if *avatar != "" {
+			data, err := os.ReadFile(*avatar)
+			if err != nil {
+				logger.Fatalf("Failed to read avatar: %v", err)
+			}
+			opts.Avatar = data
+		}
+
+		runExport(logger, exportCmd.Arg(0), formats, *output, opts, *dryRun)
 
 	case "help", "-h", "--help":
 		printHelp()
@@ -103,8 +264,14 @@ func printHelp() {
 	fmt.Println()
 	fmt.Println("Usage:")
 	fmt.Println("  studio                    Run the main pipeline (monitor for new issues)")
+	fmt.Println("  studio --dry-run          Run the pipeline once without AI or GitHub credentials")
 	fmt.Println("  studio synthesize [name]  Regenerate synthesized.md from raw AI outputs")
 	fmt.Println("  studio batch <file.txt>   Generate personas from a list of names in a file")
+	fmt.Println("  studio usage              Report token usage and cost per persona, provider and day")
+	fmt.Println("  studio providers status   Test each AI provider and show its circuit breaker state")
+	fmt.Println("  studio validate [name]    Lint personas against the template and score completeness")
+	fmt.Println("  studio history <name> [v] List a persona's versions, or print one")
+	fmt.Println("  studio export <name>      Export a persona as a character card, assistant, Modelfile, JSON or YAML")
 	fmt.Println("  studio help               Show this help message")
 	fmt.Println()
 	fmt.Println("Examples:")
@@ -113,14 +280,26 @@ func printHelp() {
 	fmt.Println("  studio synthesize \"Elon Musk\"  # Regenerate specific persona")
 	fmt.Println("  studio batch names.txt         # Generate personas from names in file")
 	fmt.Println("  studio batch -force names.txt  # Force generation even if personas exist")
+	fmt.Println("  studio batch -dry-run names.txt # Show the branches, files and PRs a batch would create")
+	fmt.Println("  studio usage -since 2025-01-01 # Spend since the start of the year")
+	fmt.Println("  studio providers status -models # Check every provider and list its models")
+	fmt.Println("  studio validate -json \"Ada Lovelace\" # Machine-readable report for one persona")
+	fmt.Println("  studio history \"Ada Lovelace\" 2  # Print version 2 of a persona")
+	fmt.Println("  studio export -format all -o out/ \"Ada Lovelace\" # Every export format")
 }
 
-func runPipeline(logger *logrus.Logger) {
+// isRunFlag reports whether arg is a flag for the default pipeline rather than a subcommand
+func isRunFlag(arg string) bool {
+	return strings.HasPrefix(arg, "-") && arg != "-h" && arg != "--help"
+}
+
+func runPipeline(logger *logrus.Logger, dryRun bool) {
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
 		logger.Fatalf("Failed to load config: %v", err)
 	}
+	recorder := setupRuntime(cfg, logger, dryRun)
 
 	// Create and start pipeline
 	p, err := pipeline.New(cfg, logger)
@@ -128,6 +307,15 @@ func runPipeline(logger *logrus.Logger) {
 		logger.Fatalf("Failed to create pipeline: %v", err)
 	}
 
+	if recorder != nil {
+		logger.Info("Running one Studio pipeline iteration in dry-run mode...")
+		if err := p.RunOnce(context.Background()); err != nil {
+			logger.Errorf("Pipeline run failed: %v", err)
+		}
+		recorder.WriteReport(os.Stdout)
+		return
+	}
+
 	// Setup graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -148,16 +336,25 @@ func runPipeline(logger *logrus.Logger) {
 	}
 }
 
-func runSynthesize(logger *logrus.Logger, personaName string) {
+func runSynthesize(logger *logrus.Logger, personaName string, dryRun bool) {
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
 		logger.Fatalf("Failed to load config: %v", err)
 	}
+	recorder := setupRuntime(cfg, logger, dryRun)
+	if recorder != nil {
+		defer recorder.WriteReport(os.Stdout)
+	}
 
 	// Create synthesizer
 	ctx := context.Background()
-	synth := synthesizer.New(cfg, logger)
+	synth, err := synthesizer.New(cfg, logger)
+	if err != nil {
+		logger.Fatalf("Failed to create synthesizer: %v", err)
+	}
+	synth.SetLinter(pipeline.LinterFromConfig(cfg.Pipeline, logger))
+	synth.SetCompleteness(pipeline.CompletenessFromConfig(cfg.AI.Completeness))
 
 	if personaName == "" {
 		logger.Info("Regenerating synthesized.md for all personas...")
@@ -174,6 +371,380 @@ func runSynthesize(logger *logrus.Logger, personaName string) {
 	logger.Info("Synthesis complete!")
 }
 
+// setupRuntime prepares AI provider calls and the GitHub transport.
+// In dry-run mode it returns the recorder holding the GitHub writes.
+func setupRuntime(cfg *config.Config, logger *logrus.Logger, dryRun bool) *dryrun.GitHubTransport {
+	var recorder *dryrun.GitHubTransport
+	if dryRun {
+		recorder = setupDryRun(cfg, logger)
+	}
+
+	// In a dry run, replay serves recorded AI calls against the dry-run
+	// GitHub data, so a recorded run can be repeated offline
+	provider.Setup(cfg, logger, dryRun)
+	return recorder
+}
+
+// setupDryRun swaps the AI providers for deterministic fakes and GitHub for
+// a recorder, and keeps the run's local state out of the data directory
+func setupDryRun(cfg *config.Config, logger *logrus.Logger) *dryrun.GitHubTransport {
+	tmpDir, err := os.MkdirTemp("", "studio-dry-run-")
+	if err != nil {
+		logger.Fatalf("Failed to create dry-run directory: %v", err)
+	}
+	cfg.Pipeline.DataDir = tmpDir
+	cfg.Pipeline.ArtifactsDir = filepath.Join(tmpDir, "artifacts")
+	cfg.Usage.LedgerPath = filepath.Join(tmpDir, "usage.jsonl")
+	cfg.AI.BreakerStateFile = filepath.Join(tmpDir, "breakers.json")
+	cfg.AI.RateLimitStateFile = filepath.Join(tmpDir, "rate_limits.json")
+	if cfg.GitHub.Owner == "" || cfg.GitHub.Repo == "" {
+		cfg.GitHub.Owner, cfg.GitHub.Repo = "twin2ai", "studio"
+	}
+
+	var names []string
+	for _, p := range cfg.AI.Providers {
+		names = append(names, p.Name)
+	}
+
+	recorder, err := dryrun.NewGitHubTransport(dryrun.GitHubOptions{
+		Passthrough: cfg.GitHub.Token != "" && cfg.DryRun.FixturesDir == "",
+		Label:       cfg.GitHub.PersonaLabel,
+		FixturesDir: cfg.DryRun.FixturesDir,
+		SampleFiles: dryrun.SamplePersonaFiles(names),
+		OutputDir:   cfg.DryRun.OutputDir,
+	}, logger)
+	if err != nil {
+		logger.Fatalf("Failed to set up dry run: %v", err)
+	}
+
+	llm.SetTransport(dryrun.NewAITransport(logger))
+	githubclient.SetTransport(recorder)
+
+	if recorder.Passthrough() {
+		logger.Infof("Dry run: reading GitHub with your token, recording writes; local state in %s", tmpDir)
+	} else {
+		logger.Infof("Dry run: serving sample GitHub data, recording writes; local state in %s", tmpDir)
+	}
+	return recorder
+}
+
+func runUsage(logger *logrus.Logger, since, by string) {
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Fatalf("Failed to load config: %v", err)
+	}
+
+	records, err := usage.Load(cfg.Usage.LedgerPath)
+	if err != nil {
+		logger.Fatalf("Failed to load usage ledger: %v", err)
+	}
+
+	if since != "" {
+		sinceTime, err := time.Parse("2006-01-02", since)
+		if err != nil {
+			logger.Fatalf("Invalid -since date %q: %v", since, err)
+		}
+		records = usage.Since(records, sinceTime)
+	}
+
+	if len(records) == 0 {
+		fmt.Printf("No usage recorded in %s\n", cfg.Usage.LedgerPath)
+		return
+	}
+
+	groupings := map[string]func(usage.Record) string{
+		"persona":  usage.ByPersona,
+		"provider": usage.ByProvider,
+		"day":      usage.ByDay,
+		"stage":    usage.ByStage,
+	}
+	titles := map[string]string{
+		"persona":  "By persona",
+		"provider": "By provider",
+		"day":      "By day",
+		"stage":    "By stage",
+	}
+
+	selected := make(map[string]func(usage.Record) string)
+	var order []string
+	for _, name := range strings.Split(by, ",") {
+		name = strings.TrimSpace(name)
+		group, ok := groupings[name]
+		if !ok {
+			logger.Fatalf("Unknown grouping %q (use persona, provider, day or stage)", name)
+		}
+		selected[titles[name]] = group
+		order = append(order, titles[name])
+	}
+
+	fmt.Printf("Usage from %s (%d calls)\n", cfg.Usage.LedgerPath, len(records))
+	usage.WriteReport(os.Stdout, records, selected, order)
+}
+
+func runProvidersStatus(logger *logrus.Logger, listModels bool, dryRun bool) {
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Fatalf("Failed to load config: %v", err)
+	}
+	setupRuntime(cfg, logger, dryRun)
+
+	// Check generation providers, then synthesis and judge providers not
+	// already listed
+	providers, err := provider.NewRegistry(cfg.AI.Providers, logger)
+	if err != nil {
+		logger.Fatalf("Failed to create provider registry: %v", err)
+	}
+	all := providers.Providers()
+	listed := make(map[string]bool)
+	for _, p := range all {
+		listed[p.Name()] = true
+	}
+	extra := [][]config.ProviderConfig{cfg.AI.Synthesizers}
+	if cfg.Pipeline.Evaluation {
+		extra = append(extra, cfg.AI.Judges)
+	}
+	for _, cfgs := range extra {
+		others, err := provider.NewRegistry(cfgs, logger)
+		if err != nil {
+			continue
+		}
+		for _, p := range others.Providers() {
+			if !listed[p.Name()] {
+				listed[p.Name()] = true
+				all = append(all, p)
+			}
+		}
+	}
+
+	results := provider.CheckHealth(context.Background(), all, listModels, logger)
+	provider.WriteHealthReport(os.Stdout, results)
+
+	for _, health := range results {
+		if health.Err != nil {
+			os.Exit(1)
+		}
+	}
+}
+
+func runValidate(logger *logrus.Logger, target string, minWords int, jsonOutput bool, dryRun bool) {
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Fatalf("Failed to load config: %v", err)
+	}
+	if minWords == 0 {
+		minWords = cfg.Pipeline.ValidationMinWords
+	}
+	if jsonOutput {
+		// Keep stdout for the reports
+		logger.SetOutput(os.Stderr)
+	}
+
+	linter, err := validation.LoadLinter(validation.DefaultTemplate, validation.Options{MinWords: minWords})
+	if err != nil {
+		logger.Fatalf("Failed to load persona linter: %v", err)
+	}
+
+	var reports []*validation.Report
+	if data, err := os.ReadFile(target); err == nil {
+		// A local persona document, named by its title
+		report := linter.Lint("", string(data))
+		if report.Persona == "" {
+			report.Persona = target
+		}
+		reports = append(reports, report)
+	} else {
+		if dryRun {
+			setupDryRun(cfg, logger)
+		}
+		githubClient := githubclient.NewClient(
+			cfg.GitHub.Token,
+			cfg.GitHub.Owner,
+			cfg.GitHub.Repo,
+			cfg.GitHub.PersonasOwner,
+			cfg.GitHub.PersonasRepo,
+			cfg.GitHub.PersonaLabel,
+			logger,
+		)
+
+		ctx := context.Background()
+		folders := []string{models.FolderName(target)}
+		if target == "" {
+			folders, err = githubClient.ListPersonaFolders(ctx)
+			if err != nil {
+				logger.Fatalf("Failed to list personas: %v", err)
+			}
+		}
+
+		for _, folder := range folders {
+			content, err := githubClient.GetFileContent(ctx, fmt.Sprintf("personas/%s/synthesized.md", folder))
+			if err != nil {
+				if target != "" {
+					logger.Fatalf("Failed to fetch persona %s: %v", target, err)
+				}
+				logger.Warnf("Skipping %s: %v", folder, err)
+				continue
+			}
+			reports = append(reports, linter.Lint(folder, content))
+		}
+	}
+
+	if jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(reports); err != nil {
+			logger.Fatalf("Failed to encode reports: %v", err)
+		}
+	} else {
+		validation.WriteReport(os.Stdout, reports)
+	}
+
+	for _, report := range reports {
+		if !report.Passed {
+			os.Exit(1)
+		}
+	}
+}
+
+func runHistory(logger *logrus.Logger, personaName string, version int, dryRun bool) {
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Fatalf("Failed to load config: %v", err)
+	}
+	// Keep stdout for the history and snapshots
+	logger.SetOutput(os.Stderr)
+	if dryRun {
+		setupDryRun(cfg, logger)
+	}
+
+	githubClient := githubclient.NewClient(
+		cfg.GitHub.Token,
+		cfg.GitHub.Owner,
+		cfg.GitHub.Repo,
+		cfg.GitHub.PersonasOwner,
+		cfg.GitHub.PersonasRepo,
+		cfg.GitHub.PersonaLabel,
+		logger,
+	)
+
+	ctx := context.Background()
+	folder := strings.ReplaceAll(strings.ToLower(strings.ReplaceAll(personaName, " ", "_")), "/", "_")
+	history, err := githubClient.GetPersonaHistory(ctx, folder)
+	if err != nil {
+		logger.Fatalf("No CHANGELOG.md for persona %s; history is recorded from its next regeneration: %v", personaName, err)
+	}
+
+	if version == 0 {
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "VERSION\tDATE\tTRIGGER\tSYNTHESIZER\tCHANGES")
+		for i := len(history) - 1; i >= 0; i-- {
+			v := history[i]
+			date := "-"
+			if !v.Date.IsZero() {
+				date = v.Date.Format("2006-01-02")
+			}
+			synthesizer := v.Provenance.Synthesizer
+			if v.Provenance.SynthesizerModel != "" {
+				synthesizer += " (" + v.Provenance.SynthesizerModel + ")"
+			}
+			fmt.Fprintf(tw, "v%d\t%s\t%s\t%s\t%s\n", v.Number, date, v.Trigger, synthesizer, v.Summary)
+		}
+		tw.Flush()
+		return
+	}
+
+	for _, v := range history {
+		if v.Number != version {
+			continue
+		}
+		content, err := githubClient.GetFileContent(ctx, fmt.Sprintf("personas/%s/%s", folder, v.Path))
+		if err != nil {
+			logger.Fatalf("Failed to fetch v%d of %s: %v", version, personaName, err)
+		}
+		fmt.Print(content)
+		return
+	}
+	logger.Fatalf("Persona %s has no version %d (latest is v%d)", personaName, version, models.NextVersion(history)-1)
+}
+
+func runExport(logger *logrus.Logger, target string, formats []export.Format, output string, opts export.Options, dryRun bool) {
+	// Keep stdout for the export
+	logger.SetOutput(os.Stderr)
+
+	folder := strings.ReplaceAll(strings.ToLower(strings.ReplaceAll(target, " ", "_")), "/", "_")
+	dir := target
+	if _, err := os.Stat(filepath.Join(target, models.SynthesizedFile)); err == nil {
+		// A local persona folder
+		folder = filepath.Base(filepath.Clean(target))
+	} else {
+		cfg, err := config.Load()
+		if err != nil {
+			logger.Fatalf("Failed to load config: %v", err)
+		}
+		if dryRun {
+			setupDryRun(cfg, logger)
+		}
+		githubClient := githubclient.NewClient(
+			cfg.GitHub.Token,
+			cfg.GitHub.Owner,
+			cfg.GitHub.Repo,
+			cfg.GitHub.PersonasOwner,
+			cfg.GitHub.PersonasRepo,
+			cfg.GitHub.PersonaLabel,
+			logger,
+		)
+
+		dir, err = os.MkdirTemp("", "studio-export-")
+		if err != nil {
+			logger.Fatalf("Failed to create temporary directory: %v", err)
+		}
+		defer os.RemoveAll(dir)
+		if err := githubClient.DownloadPersonaFolder(context.Background(), folder, dir); err != nil {
+			logger.Fatalf("Failed to fetch persona %s: %v", target, err)
+		}
+	}
+
+	persona, err := models.LoadFolder(os.DirFS(dir))
+	if err != nil {
+		logger.Fatalf("Failed to load persona %s: %v", target, err)
+	}
+
+	// One format goes to stdout or the -o file; several go into the -o directory
+	info, statErr := os.Stat(output)
+	toDir := len(formats) > 1 || strings.HasSuffix(output, "/") || (statErr == nil && info.IsDir())
+	if toDir && output == "" {
+		logger.Fatal("Exporting several formats needs an output directory (-o)")
+	}
+	if toDir {
+		if err := os.MkdirAll(output, 0755); err != nil {
+			logger.Fatalf("Failed to create output directory: %v", err)
+		}
+	}
+
+	for _, format := range formats {
+		data, err := export.Export(persona, format, opts)
+		if err != nil {
+			logger.Fatalf("Failed to export %s as %s: %v", target, format, err)
+		}
+
+		path := output
+		switch {
+		case output == "":
+			os.Stdout.Write(data)
+			continue
+		case toDir:
+			path = filepath.Join(output, export.Filename(folder, format))
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			logger.Fatalf("Failed to write %s: %v", path, err)
+		}
+		logger.Infof("Exported %s as %s: %s", persona.Name, format, path)
+	}
+}
+
 func setupLogger() *logrus.Logger {
 	logger := logrus.New()
 
@@ -217,12 +788,16 @@ func setupLogger() *logrus.Logger {
 	return logger
 }
 
-func runBatch(logger *logrus.Logger, filePath string, force bool) {
+func runBatch(logger *logrus.Logger, filePath string, force bool, dryRun bool) {
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
 		logger.Fatalf("Failed to load config: %v", err)
 	}
+	recorder := setupRuntime(cfg, logger, dryRun)
+	if recorder != nil {
+		defer recorder.WriteReport(os.Stdout)
+	}
 
 	// Create GitHub client
 	githubClient := githubclient.NewClient(
@@ -236,13 +811,27 @@ func runBatch(logger *logrus.Logger, filePath string, force bool) {
 	)
 
 	// Create AI clients
-	claudeClient := claude.NewClient(cfg.AI.Claude.APIKey, cfg.AI.Claude.Model, logger)
-	geminiClient := gemini.NewClient(cfg.AI.Gemini.APIKey, cfg.AI.Gemini.Model, logger)
-	grokClient := grok.NewClient(cfg.AI.Grok.APIKey, cfg.AI.Grok.Model, logger)
-	gptClient := gpt.NewClient(cfg.AI.GPT.APIKey, cfg.AI.GPT.Model, logger)
+	providers, err := provider.NewRegistry(cfg.AI.Providers, logger)
+	if err != nil {
+		logger.Fatalf("Failed to create provider registry: %v", err)
+	}
+	synthesis, err := provider.NewSynthesisChain(cfg.AI.Synthesizers, logger)
+	if err != nil {
+		logger.Fatalf("Failed to create synthesis chain: %v", err)
+	}
 
 	// Create multi-provider generator
-	multiGenerator := multiprovider.NewGenerator(claudeClient, geminiClient, grokClient, gptClient, logger)
+	multiGenerator := multiprovider.NewGenerator(providers, synthesis, logger)
+	multiGenerator.SetMaxContinuations(cfg.AI.MaxContinuations)
+	multiGenerator.SetQuorum(pipeline.QuorumFromConfig(cfg.AI.Quorum))
+	multiGenerator.SetCompleteness(pipeline.CompletenessFromConfig(cfg.AI.Completeness))
+	multiGenerator.SetArtifactsDir(cfg.Pipeline.ArtifactsDir)
+	multiGenerator.SetPublishThinking(cfg.Pipeline.PublishThinking)
+	multiGenerator.SetStructuredOutput(cfg.Pipeline.StructuredOutput)
+	multiGenerator.SetLinter(pipeline.LinterFromConfig(cfg.Pipeline, logger))
+	multiGenerator.SetJudge(pipeline.JudgeFromConfig(cfg, logger))
+	multiGenerator.SetAnalyzer(pipeline.AnalyzerFromConfig(cfg.Pipeline, synthesis, logger))
+	multiGenerator.SetFactExtractor(pipeline.FactExtractorFromConfig(cfg.Pipeline, synthesis, logger))
 
 	// Create batch pipeline
 	batchPipeline, err := pipeline.NewBatchPipeline(cfg, githubClient, multiGenerator, logger, force)
@@ -0,0 +1,285 @@
+// Package analysis finds where the providers' raw personas contradict each
+// other: it extracts the key claims of every output, then compares them so
+// reviewers know which facts to verify before merging.
+package analysis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/twin2ai/studio/internal/llm"
+	"github.com/twin2ai/studio/internal/provider"
+)
+
+// SynthesisSource names the synthesized persona among the analyzed sources
+const SynthesisSource = "synthesis"
+
+// analysisAttempts caps the requests made for one extraction or comparison,
+// including the one correcting an invalid reply
+const analysisAttempts = 2
+
+// Categories of claim
+const (
+	CategoryFact   = "fact"
+	CategoryRole   = "role"
+	CategoryBelief = "belief"
+	CategoryOther  = "other"
+)
+
+var categories = []string{CategoryFact, CategoryRole, CategoryBelief, CategoryOther}
+
+// Source is a persona document to analyze
+type Source struct {
+	Name    string // Provider name, or SynthesisSource
+	Model   string
+	Content string
+	Claims  []Claim // Claims already extracted, e.g. from the fact sheet; nil to extract them from Content
+}
+
+// Claim is one checkable statement a source makes about its subject.
+// Claims taken from a fact sheet have no topic.
+type Claim struct {
+	Topic    string `json:"topic,omitempty"`
+	Category string `json:"category"`
+	Claim    string `json:"claim"`
+}
+
+// Analyzer extracts and compares claims with the analysis providers
+type Analyzer struct {
+	chain  *provider.SynthesisChain
+	logger *logrus.Logger
+}
+
+// NewAnalyzer creates an analyzer that runs on chain, falling back along it
+func NewAnalyzer(chain *provider.SynthesisChain, logger *logrus.Logger) *Analyzer {
+	return &Analyzer{chain: chain, logger: logger}
+}
+
+// Analyze extracts the claims of every raw output and the synthesis in
+// parallel, then compares them. Sources that come with their claims are not
+// extracted again. Sources whose claims cannot be extracted
+// are reported with their error and left out of the comparison; a failed
+// comparison is returned as the report's error.
+func (a *Analyzer) Analyze(ctx context.Context, subject string, raw []Source, synthesis Source) *Report {
+	sources := append(append([]Source{}, raw...), synthesis)
+	report := &Report{Subject: subject, Sources: make([]SourceClaims, len(sources))}
+
+	var wg sync.WaitGroup
+	for i, source := range sources {
+		wg.Add(1)
+		go func(i int, source Source) {
+			defer wg.Done()
+			report.Sources[i] = SourceClaims{Source: source.Name, Model: source.Model, Claims: source.Claims}
+			if source.Claims != nil {
+				return
+			}
+			claims, err := a.extract(ctx, subject, source)
+			if err != nil {
+				a.logger.Warnf("Failed to extract claims from %s: %v", source.Name, err)
+				report.Sources[i].Error = err.Error()
+				return
+			}
+			report.Sources[i].Claims = claims
+		}(i, source)
+	}
+	wg.Wait()
+
+	providers := 0
+	for _, s := range report.Sources {
+		if s.Error == "" && s.Source != SynthesisSource {
+			providers++
+		}
+	}
+	if providers < 2 {
+		report.Error = "fewer than two provider outputs to compare"
+		return report
+	}
+
+	disagreements, analyst, err := a.compare(ctx, subject, report.Sources)
+	if err != nil {
+		a.logger.Warnf("Failed to compare claims for %s: %v", subject, err)
+		report.Error = err.Error()
+		return report
+	}
+	report.Analyst = analyst
+	report.Disagreements = disagreements
+	return report
+}
+
+// extract asks for the key claims of one source
+func (a *Analyzer) extract(ctx context.Context, subject string, source Source) ([]Claim, error) {
+	req := llm.Request{System: extractPrompt, Schema: claimsSchema()}.
+		User(fmt.Sprintf("SUBJECT: %s\n\nPROFILE:\n<<<\n%s\n>>>", subject, source.Content))
+
+	var reply struct {
+		Claims []Claim `json:"claims"`
+	}
+	if _, err := a.complete(ctx, req, &reply); err != nil {
+		return nil, err
+	}
+	for i := range reply.Claims {
+		reply.Claims[i].Topic = strings.TrimSpace(reply.Claims[i].Topic)
+		reply.Claims[i].Claim = strings.TrimSpace(reply.Claims[i].Claim)
+	}
+	return reply.Claims, nil
+}
+
+// text joins the claim to its topic, if it has one
+func (c Claim) text() string {
+	if c.Topic == "" {
+		return c.Claim
+	}
+	return c.Topic + ": " + c.Claim
+}
+
+// compare asks which topics the sources' claims contradict each other on.
+// Positions naming unknown sources are dropped, as are disagreements left
+// with fewer than two positions.
+func (a *Analyzer) compare(ctx context.Context, subject string, sources []SourceClaims) ([]Disagreement, string, error) {
+	known := make(map[string]bool)
+	var names []string
+	var claims strings.Builder
+	for _, s := range sources {
+		if s.Error != "" {
+			continue
+		}
+		known[s.Source] = true
+		names = append(names, s.Source)
+		fmt.Fprintf(&claims, "### %s\n", s.Source)
+		for _, c := range s.Claims {
+			fmt.Fprintf(&claims, "- [%s] %s\n", c.Category, c.text())
+		}
+		claims.WriteString("\n")
+	}
+
+	req := llm.Request{System: comparePrompt, Schema: disagreementsSchema(names)}.
+		User(fmt.Sprintf("SUBJECT: %s\n\nCLAIMS BY SOURCE:\n\n%s", subject, claims.String()))
+
+	var reply struct {
+		Disagreements []Disagreement `json:"disagreements"`
+	}
+	analyst, err := a.complete(ctx, req, &reply)
+	if err != nil {
+		return nil, analyst, err
+	}
+
+	var disagreements []Disagreement
+	for _, d := range reply.Disagreements {
+		var positions []Position
+		for _, p := range d.Positions {
+			var sources []string
+			for _, name := range p.Sources {
+				if known[name] {
+					sources = append(sources, name)
+				}
+			}
+			if len(sources) > 0 && strings.TrimSpace(p.Claim) != "" {
+				sort.Strings(sources)
+				positions = append(positions, Position{Claim: strings.TrimSpace(p.Claim), Sources: sources})
+			}
+		}
+		if len(positions) < 2 {
+			continue
+		}
+		d.Positions = positions
+		disagreements = append(disagreements, d)
+	}
+	return disagreements, analyst, nil
+}
+
+// complete sends req along the chain and decodes the JSON reply into v,
+// sending validation errors back once so the provider can correct its
+// reply. It returns the provider and model that answered.
+func (a *Analyzer) complete(ctx context.Context, req llm.Request, v interface{}) (string, error) {
+	result, err := a.chain.CompleteJSON(ctx, req, llm.StageAnalysis, analysisAttempts, v, nil)
+	if result == nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s (%s)", result.Provider, result.Model), err
+}
+
+const extractPrompt = `You are a fact checker preparing to compare several persona profiles of the same real person. List the key claims the profile you are given makes about its subject: biographical facts (dates, places, family, education, positions held, works, events), the roles it says they had, and the beliefs or positions it attributes to them. Name each topic the way any profile of this person would, e.g. "Birth date", "Birthplace", "Education", "Role at Analytical Engine project", "View on religion", so claims from different profiles line up. State each claim as the profile does, briefly, without judging whether it is true. Skip style and voice observations.
+
+Reply with a single JSON object and nothing else:
+{"claims": [{"topic": "<topic>", "category": "fact|role|belief|other", "claim": "<what the profile says>"}]}`
+
+const comparePrompt = `You compare the claims several persona profiles make about the same real person. Each source's claims are listed under its name. Find every topic where the sources contradict each other: different dates, places, numbers, roles or stated beliefs. Claims that say the same thing in different words agree; a topic that only one source mentions is not a disagreement. For each disagreement give the distinct positions, the sources that hold each, and one sentence on what a reviewer should verify.
+
+Reply with a single JSON object and nothing else; use an empty list when the sources agree:
+{"disagreements": [{"topic": "<topic>", "category": "fact|role|belief|other", "positions": [{"claim": "<position>", "sources": ["<source>"]}], "verify": "<what to check>"}]}`
+
+// claimsSchema is the JSON Schema of an extraction reply
+func claimsSchema() json.RawMessage {
+	return encode(map[string]interface{}{
+		"type":                 "object",
+		"additionalProperties": false,
+		"required":             []string{"claims"},
+		"properties": map[string]interface{}{
+			"claims": map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type":                 "object",
+					"additionalProperties": false,
+					"required":             []string{"topic", "category", "claim"},
+					"properties": map[string]interface{}{
+						"topic":    map[string]interface{}{"type": "string", "minLength": 1},
+						"category": map[string]interface{}{"type": "string", "enum": categories},
+						"claim":    map[string]interface{}{"type": "string", "minLength": 1},
+					},
+				},
+			},
+		},
+	})
+}
+
+// disagreementsSchema is the JSON Schema of a comparison reply between sources
+func disagreementsSchema(sources []string) json.RawMessage {
+	return encode(map[string]interface{}{
+		"type":                 "object",
+		"additionalProperties": false,
+		"required":             []string{"disagreements"},
+		"properties": map[string]interface{}{
+			"disagreements": map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type":                 "object",
+					"additionalProperties": false,
+					"required":             []string{"topic", "category", "positions", "verify"},
+					"properties": map[string]interface{}{
+						"topic":    map[string]interface{}{"type": "string", "minLength": 1},
+						"category": map[string]interface{}{"type": "string", "enum": categories},
+						"verify":   map[string]interface{}{"type": "string"},
+						"positions": map[string]interface{}{
+							"type":     "array",
+							"minItems": 2,
+							"items": map[string]interface{}{
+								"type":                 "object",
+								"additionalProperties": false,
+								"required":             []string{"claim", "sources"},
+								"properties": map[string]interface{}{
+									"claim": map[string]interface{}{"type": "string", "minLength": 1},
+									"sources": map[string]interface{}{
+										"type":     "array",
+										"minItems": 1,
+										"items":    map[string]interface{}{"type": "string", "enum": sources},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+func encode(document map[string]interface{}) json.RawMessage {
+	encoded, _ := json.Marshal(document)
+	return encoded
+}
@@ -0,0 +1,190 @@
+package analysis
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// File is the report's name in the persona folder
+const File = "disagreements.md"
+
+// MetadataDisagreements is the .assets_status.json key holding the number
+// of topics the providers disagree on
+const MetadataDisagreements = "disagreements"
+
+// SourceClaims are the claims extracted from one source
+type SourceClaims struct {
+	Source string  `json:"source"`
+	Model  string  `json:"model,omitempty"`
+	Claims []Claim `json:"claims,omitempty"`
+	Error  string  `json:"error,omitempty"`
+}
+
+// Position is one side of a disagreement and the sources that hold it
+type Position struct {
+	Claim   string   `json:"claim"`
+	Sources []string `json:"sources"`
+}
+
+// Disagreement is a topic the sources contradict each other on
+type Disagreement struct {
+	Topic     string     `json:"topic"`
+	Category  string     `json:"category"`
+	Positions []Position `json:"positions"`
+	Verify    string     `json:"verify"`
+}
+
+// Adopted returns the position the synthesis holds, or nil when it takes
+// none of them
+func (d Disagreement) Adopted() *Position {
+	for i, p := range d.Positions {
+		for _, s := range p.Sources {
+			if s == SynthesisSource {
+				return &d.Positions[i]
+			}
+		}
+	}
+	return nil
+}
+
+// Report is the claims of a persona's raw outputs and synthesis and the
+// topics they disagree on. Error is set when the comparison did not run.
+type Report struct {
+	Subject       string         `json:"subject"`
+	Analyst       string         `json:"analyst,omitempty"`
+	Sources       []SourceClaims `json:"sources"`
+	Disagreements []Disagreement `json:"disagreements"`
+	Error         string         `json:"error,omitempty"`
+}
+
+// Compared reports whether the sources' claims were compared
+func (r *Report) Compared() bool {
+	return r.Error == ""
+}
+
+// Summary returns a one-line result, e.g. "3 disagreements: Birth date, Birthplace, Religion"
+func (r *Report) Summary() string {
+	if !r.Compared() {
+		return "not compared: " + r.Error
+	}
+	if len(r.Disagreements) == 0 {
+		return "no disagreements"
+	}
+	topics := make([]string, len(r.Disagreements))
+	for i, d := range r.Disagreements {
+		topics[i] = d.Topic
+	}
+	return fmt.Sprintf("%d %s: %s", len(topics), plural(len(topics), "disagreement", "disagreements"), strings.Join(topics, ", "))
+}
+
+// Markdown renders the disagreements as a table for a pull request body,
+// with the position the synthesis adopted
+func (r *Report) Markdown() string {
+	if !r.Compared() {
+		return fmt.Sprintf("The provider outputs were not compared: %s\n", r.Error)
+	}
+	if len(r.Disagreements) == 0 {
+		return fmt.Sprintf("The providers agree on every key claim they share (%s).\n", strings.Join(r.compared(), ", "))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "The providers disagree on **%d** %s. Check each before merging.\n\n",
+		len(r.Disagreements), plural(len(r.Disagreements), "topic", "topics"))
+	b.WriteString("| Topic | Positions | Synthesis | Verify |\n|-------|-----------|-----------|--------|\n")
+	for _, d := range r.Disagreements {
+		positions := make([]string, len(d.Positions))
+		for i, p := range d.Positions {
+			positions[i] = fmt.Sprintf("%s (%s)", cell(p.Claim), strings.Join(providers(p.Sources), ", "))
+		}
+		adopted := "*none*"
+		if p := d.Adopted(); p != nil {
+			adopted = cell(p.Claim)
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", cell(d.Topic), strings.Join(positions, "<br>"), adopted, cell(d.Verify))
+	}
+	if r.Analyst != "" {
+		fmt.Fprintf(&b, "\n*Compared by %s.*\n", r.Analyst)
+	}
+	return b.String()
+}
+
+// Document renders the report as disagreements.md: the disagreements,
+// then every source's extracted claims
+func (r *Report) Document() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Provider Disagreements: %s\n\n", r.Subject)
+	b.WriteString("Key claims were extracted from each raw output and the synthesis, then compared. ")
+	b.WriteString("Each row is a topic the providers contradict each other on; the synthesis column shows which position it kept.\n\n")
+	b.WriteString(r.Markdown())
+
+	b.WriteString("\n## Claims by Source\n")
+	for _, s := range r.Sources {
+		name := s.Source
+		if s.Model != "" {
+			name = fmt.Sprintf("%s (%s)", s.Source, s.Model)
+		}
+		fmt.Fprintf(&b, "\n### %s\n\n", name)
+		if s.Error != "" {
+			fmt.Fprintf(&b, "Claims could not be extracted: %s\n", s.Error)
+			continue
+		}
+		if len(s.Claims) == 0 {
+			b.WriteString("No claims found.\n")
+			continue
+		}
+		for _, c := range s.Claims {
+			if c.Topic == "" {
+				fmt.Fprintf(&b, "- (%s) %s\n", c.Category, c.Claim)
+			} else {
+				fmt.Fprintf(&b, "- **%s** (%s): %s\n", c.Topic, c.Category, c.Claim)
+			}
+		}
+	}
+	return b.String()
+}
+
+// Record writes the number of disagreements into asset status metadata
+func (r *Report) Record(metadata map[string]string) {
+	if r.Compared() {
+		metadata[MetadataDisagreements] = strconv.Itoa(len(r.Disagreements))
+	}
+}
+
+// compared returns the names of the sources whose claims were compared
+func (r *Report) compared() []string {
+	var names []string
+	for _, s := range r.Sources {
+		if s.Error == "" {
+			names = append(names, s.Source)
+		}
+	}
+	return names
+}
+
+// providers returns the provider sources of a position, leaving out the synthesis
+func providers(sources []string) []string {
+	var names []string
+	for _, s := range sources {
+		if s != SynthesisSource {
+			names = append(names, s)
+		}
+	}
+	if len(names) == 0 {
+		return []string{SynthesisSource}
+	}
+	return names
+}
+
+// cell makes text safe for a markdown table cell
+func cell(text string) string {
+	text = strings.ReplaceAll(strings.TrimSpace(text), "\n", " ")
+	return strings.ReplaceAll(text, "|", "\\|")
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
@@ -171,10 +171,10 @@ func (m *Monitor) checkGitHubPersonaForTriggers(ctx context.Context, personaName
 	status, err := m.statusManager.LoadStatus(personaName)
 	if err != nil {
 		m.logger.Debugf("Failed to load status for %s, assuming first generation: %v", personaName, err)
-		// Treat as first generation - create trigger for all prompt assets
+		// Treat as first generation - create trigger for all synthesis assets
 		return &PersonaAssetTrigger{
 			PersonaName:   personaName,
-			AssetTypes:    []AssetType{AssetTypePrompts, AssetTypePlatformPrompts, AssetTypeVariationPrompts},
+			AssetTypes:    append([]AssetType{}, synthesisAssets...),
 			TriggerReason: "first generation for persona",
 			DetectedAt:    time.Now(),
 		}, nil
@@ -186,8 +186,8 @@ func (m *Monitor) checkGitHubPersonaForTriggers(ctx context.Context, personaName
 
 	if synthesizedModTime.After(status.LastAssetsGeneration) {
 		triggerReason = "synthesized.md file was modified in GitHub"
-		// Add all prompt-related assets to regeneration queue
-		assetTypes = append(assetTypes, AssetTypePrompts, AssetTypePlatformPrompts, AssetTypeVariationPrompts)
+		// Add the prompts and fact sheet to the regeneration queue
+		assetTypes = append(assetTypes, synthesisAssets...)
 		m.logger.Infof("GitHub synthesized.md for %s modified at %v (last generation: %v)",
 			personaName, synthesizedModTime, status.LastAssetsGeneration)
 	} else if len(status.PendingAssets) > 0 {
@@ -227,10 +227,13 @@ func (m *Monitor) checkPersonaForTriggers(personaName string) (*PersonaAssetTrig
 
 	if modified {
 		triggerReason = "synthesized.md file was modified"
-		// Add all pending assets to regeneration queue
+		// Add all pending assets and the fact sheet to regeneration queue
 		for _, asset := range status.PendingAssets {
 			assetTypes = append(assetTypes, AssetType(asset))
 		}
+		if !contains(status.PendingAssets, string(AssetTypeFacts)) {
+			assetTypes = append(assetTypes, AssetTypeFacts)
+		}
 	} else if len(status.PendingAssets) > 0 {
 		triggerReason = "pending assets detected"
 		for _, asset := range status.PendingAssets {
@@ -289,6 +292,7 @@ func (m *Monitor) checkReadmeTriggers(personaName string) (bool, []AssetType, er
 		"<!-- GENERATE:prompts -->",
 		"<!-- GENERATE:platform_prompts -->",
 		"<!-- GENERATE:variation_prompts -->",
+		"<!-- GENERATE:facts -->",
 	}
 
 	assetMap := map[string]AssetType{
@@ -301,6 +305,7 @@ func (m *Monitor) checkReadmeTriggers(personaName string) (bool, []AssetType, er
 		"prompts":              AssetTypePrompts,
 		"platform_prompts":     AssetTypePlatformPrompts,
 		"variation_prompts":    AssetTypeVariationPrompts,
+		"facts":                AssetTypeFacts,
 	}
 
 	for _, trigger := range triggers {
@@ -6,18 +6,12 @@ import (
 	"os"
 	"path/filepath"
 	"time"
+
+	"github.com/twin2ai/studio/pkg/models"
 )
 
 // AssetStatus represents the status of asset generation for a persona
-type AssetStatus struct {
-	PersonaName           string            `json:"persona_name"`
-	LastSynthesizedUpdate time.Time         `json:"last_synthesized_update"`
-	LastAssetsGeneration  time.Time         `json:"last_assets_generation"`
-	PendingAssets         []string          `json:"pending_assets"`
-	GeneratedAssets       []string          `json:"generated_assets"`
-	AssetGenerationFlags  map[string]bool   `json:"asset_generation_flags"`
-	Metadata              map[string]string `json:"metadata,omitempty"`
-}
+type AssetStatus = models.AssetStatus
 
 // AssetType represents different types of assets that can be generated
 type AssetType string
@@ -34,8 +28,15 @@ const (
 	AssetTypePrompts          AssetType = "prompts"
 	AssetTypePlatformPrompts  AssetType = "platform_prompts"
 	AssetTypeVariationPrompts AssetType = "variation_prompts"
+
+	// AssetTypeFacts is facts.json, the consensus-tagged fact sheet
+	// extracted from the raw outputs
+	AssetTypeFacts AssetType = "facts"
 )
 
+// synthesisAssets are regenerated whenever synthesized.md changes
+var synthesisAssets = []AssetType{AssetTypePrompts, AssetTypePlatformPrompts, AssetTypeVariationPrompts, AssetTypeFacts}
+
 // StatusManager handles asset status tracking for personas
 type StatusManager struct {
 	baseDir string
@@ -0,0 +1,298 @@
+package cassette
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Mode selects how the transport treats recorded interactions
+type Mode string
+
+const (
+	ModeOff    Mode = "off"    // Always use the network, record nothing
+	ModeRecord Mode = "record" // Always use the network and (over)write recordings
+	ModeReplay Mode = "replay" // Only serve recordings; a missing one is an error
+	ModeAuto   Mode = "auto"   // Serve recordings when present, record the rest
+)
+
+// ParseMode validates a CASSETTE_MODE value
+func ParseMode(value string) (Mode, error) {
+	switch Mode(strings.ToLower(strings.TrimSpace(value))) {
+	case "", ModeOff:
+		return ModeOff, nil
+	case ModeRecord:
+		return ModeRecord, nil
+	case ModeReplay:
+		return ModeReplay, nil
+	case ModeAuto:
+		return ModeAuto, nil
+	default:
+		return "", fmt.Errorf("unknown cassette mode %q (use off, record, replay or auto)", value)
+	}
+}
+
+// Request is the recorded request a cassette answers
+type Request struct {
+	Method string          `json:"method"`
+	URL    string          `json:"url"` // Without query string (Gemini passes its API key there)
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// Response is one recorded response
+type Response struct {
+	StatusCode  int    `json:"status_code"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        string `json:"body"`
+}
+
+// Cassette holds the responses recorded for one request, stored as
+// <key>.json. A run that sends the same request several times, such as a
+// synthesis retry, gets the responses back in the order they were recorded.
+type Cassette struct {
+	Request   Request    `json:"request"`
+	Responses []Response `json:"responses"`
+}
+
+// Transport is an http.RoundTripper that records AI provider calls to
+// cassette files and replays them. Requests are matched on method, host,
+// path and the canonicalized JSON body, which holds the model, prompt,
+// temperature and seed; headers and query strings (API keys) are ignored.
+type Transport struct {
+	mode   Mode
+	dir    string
+	next   http.RoundTripper
+	logger *logrus.Logger
+
+	mu        sync.Mutex
+	calls     map[string]int       // Requests sent per key in this run
+	cassettes map[string]*Cassette // Cassettes read or recorded in this run
+}
+
+// NewTransport creates a cassette transport; next performs real requests
+func NewTransport(mode Mode, dir string, next http.RoundTripper, logger *logrus.Logger) *Transport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Transport{
+		mode:      mode,
+		dir:       dir,
+		next:      next,
+		logger:    logger,
+		calls:     make(map[string]int),
+		cassettes: make(map[string]*Cassette),
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.mode == ModeOff {
+		return t.next.RoundTrip(req)
+	}
+
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	key := Key(req.Method, req.URL.Host, req.URL.Path, body)
+	recorded, err := t.replay(key)
+	if err != nil {
+		return nil, err
+	}
+	if recorded != nil {
+		t.logger.Debugf("Cassette hit %s for %s %s%s", key[:12], req.Method, req.URL.Host, req.URL.Path)
+		return recorded.response(req), nil
+	}
+	if t.mode == ModeReplay {
+		return nil, fmt.Errorf("cassette: no recording for %s %s%s (key %s)", req.Method, req.URL.Host, req.URL.Path, key[:12])
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only successful responses are worth replaying; errors should be retried for real
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+
+	// Tee the body into the recording as the caller reads it, so streamed
+	// responses still arrive incrementally
+	resp.Body = &recordingBody{
+		body: resp.Body,
+		save: func(respBody []byte) {
+			recorded := Response{StatusCode: resp.StatusCode, ContentType: resp.Header.Get("Content-Type"), Body: string(respBody)}
+			if err := t.record(key, req, body, recorded); err != nil {
+				t.logger.Warnf("Failed to record cassette: %v", err)
+			} else {
+				t.logger.Debugf("Recorded cassette %s for %s %s%s", key[:12], req.Method, req.URL.Host, req.URL.Path)
+			}
+		},
+	}
+	return resp, nil
+}
+
+// replay counts a request and returns the recorded response for it: the
+// first recording for the first request with a key in this run, the second
+// for the second, and so on. It returns nil when there is none to replay.
+func (t *Transport) replay(key string) (*Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	call := t.calls[key]
+	t.calls[key]++
+
+	cassette, ok := t.cassettes[key]
+	if !ok {
+		cassette = &Cassette{}
+		if t.mode == ModeReplay || t.mode == ModeAuto {
+			loaded, err := load(t.path(key))
+			if err != nil && !os.IsNotExist(err) {
+				return nil, fmt.Errorf("failed to read cassette %s: %w", t.path(key), err)
+			}
+			if loaded != nil {
+				cassette = loaded
+			}
+		}
+		// Record mode starts every key afresh, replacing earlier recordings
+		t.cassettes[key] = cassette
+	}
+
+	if t.mode == ModeRecord || call >= len(cassette.Responses) {
+		return nil, nil
+	}
+	return &cassette.Responses[call], nil
+}
+
+// record appends a response to the key's cassette and writes it out
+func (t *Transport) record(key string, req *http.Request, body []byte, resp Response) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	cassette := t.cassettes[key]
+	cassette.Request.Method = req.Method
+	cassette.Request.URL = req.URL.Scheme + "://" + req.URL.Host + req.URL.Path
+	if json.Valid(body) {
+		cassette.Request.Body = canonicalJSON(body)
+	}
+	cassette.Responses = append(cassette.Responses, resp)
+	return save(t.path(key), cassette)
+}
+
+// path is the file holding a key's cassette
+func (t *Transport) path(key string) string {
+	return filepath.Join(t.dir, key+".json")
+}
+
+// recordingBody copies a response body as it is read and hands the copy to
+// save once the body has been read to the end. A body closed early, such as
+// a stream cut off by an idle timeout, is not recorded.
+type recordingBody struct {
+	body  io.ReadCloser
+	buf   bytes.Buffer
+	save  func([]byte)
+	saved bool
+}
+
+func (r *recordingBody) Read(p []byte) (int, error) {
+	n, err := r.body.Read(p)
+	r.buf.Write(p[:n])
+	if err == io.EOF && !r.saved {
+		r.saved = true
+		r.save(r.buf.Bytes())
+	}
+	return n, err
+}
+
+func (r *recordingBody) Close() error {
+	return r.body.Close()
+}
+
+// Key hashes the parts of a request that determine the response
+func Key(method, host, path string, body []byte) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n%s\n", method, host, path)
+	h.Write(canonicalJSON(body))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// canonicalJSON re-encodes a JSON body with sorted keys so that field order
+// does not affect the key; non-JSON bodies are used as-is
+func canonicalJSON(body []byte) []byte {
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return body
+	}
+	canonical, err := json.Marshal(value)
+	if err != nil {
+		return body
+	}
+	return canonical
+}
+
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+func load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("invalid cassette: %w", err)
+	}
+	return &cassette, nil
+}
+
+func save(path string, cassette *Cassette) error {
+	data, err := json.MarshalIndent(cassette, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cassette: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// response rebuilds the recorded response for req
+func (r *Response) response(req *http.Request) *http.Response {
+	header := make(http.Header)
+	if r.ContentType != "" {
+		header.Set("Content-Type", r.ContentType)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,185 @@
+package cassette
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// countingServer answers every request with "reply <n>" and the given status
+func countingServer(t *testing.T, status int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := hits.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		fmt.Fprintf(w, "reply %d", n)
+	}))
+	t.Cleanup(server.Close)
+	return server, &hits
+}
+
+func newTestTransport(mode Mode, dir string) *Transport {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return NewTransport(mode, dir, http.DefaultTransport, logger)
+}
+
+// send posts body through rt and returns the response status and body
+func send(t *testing.T, rt http.RoundTripper, url, body string) (int, string, error) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read response: %v", err)
+	}
+	return resp.StatusCode, string(data), nil
+}
+
+func TestRecordThenReplay(t *testing.T) {
+	server, hits := countingServer(t, http.StatusOK)
+	dir := t.TempDir()
+
+	recorder := newTestTransport(ModeRecord, dir)
+	for _, want := range []string{"reply 1", "reply 2"} {
+		if _, got, err := send(t, recorder, server.URL+"/v1/messages", `{"prompt":"hi"}`); err != nil || got != want {
+			t.Fatalf("record: got %q, %v, want %q", got, err, want)
+		}
+	}
+
+	// A new run replays the identical requests in recorded order, offline
+	server.Close()
+	player := newTestTransport(ModeReplay, dir)
+	for _, want := range []string{"reply 1", "reply 2"} {
+		status, got, err := send(t, player, server.URL+"/v1/messages", `{"prompt":"hi"}`)
+		if err != nil || status != http.StatusOK || got != want {
+			t.Fatalf("replay: got %d %q, %v, want %q", status, got, err, want)
+		}
+	}
+	if n := hits.Load(); n != 2 {
+		t.Errorf("server saw %d requests, want 2", n)
+	}
+
+	// The recordings are used up
+	if _, _, err := send(t, player, server.URL+"/v1/messages", `{"prompt":"hi"}`); err == nil {
+		t.Error("replay past the recordings succeeded, want an error")
+	}
+}
+
+func TestRecordStartsAfresh(t *testing.T) {
+	server, _ := countingServer(t, http.StatusOK)
+	dir := t.TempDir()
+
+	send(t, newTestTransport(ModeRecord, dir), server.URL, `{"prompt":"hi"}`)
+	send(t, newTestTransport(ModeRecord, dir), server.URL, `{"prompt":"hi"}`)
+
+	_, got, err := send(t, newTestTransport(ModeReplay, dir), server.URL, `{"prompt":"hi"}`)
+	if err != nil || got != "reply 2" {
+		t.Errorf("replay: got %q, %v, want the second run's %q", got, err, "reply 2")
+	}
+}
+
+func TestAutoRecordsMisses(t *testing.T) {
+	server, hits := countingServer(t, http.StatusOK)
+	dir := t.TempDir()
+
+	send(t, newTestTransport(ModeRecord, dir), server.URL, `{"prompt":"hi"}`)
+
+	auto := newTestTransport(ModeAuto, dir)
+	for _, want := range []string{"reply 1", "reply 2"} {
+		if _, got, err := send(t, auto, server.URL, `{"prompt":"hi"}`); err != nil || got != want {
+			t.Fatalf("auto: got %q, %v, want %q", got, err, want)
+		}
+	}
+	if n := hits.Load(); n != 2 {
+		t.Errorf("server saw %d requests, want 2", n)
+	}
+}
+
+func TestReplayMiss(t *testing.T) {
+	server, hits := countingServer(t, http.StatusOK)
+
+	_, _, err := send(t, newTestTransport(ModeReplay, t.TempDir()), server.URL, `{"prompt":"hi"}`)
+	if err == nil || !strings.Contains(err.Error(), "no recording") {
+		t.Errorf("replay miss error = %v, want no recording", err)
+	}
+	if n := hits.Load(); n != 0 {
+		t.Errorf("server saw %d requests on a replay miss, want 0", n)
+	}
+}
+
+func TestErrorsAreNotRecorded(t *testing.T) {
+	server, _ := countingServer(t, http.StatusTooManyRequests)
+	dir := t.TempDir()
+
+	status, _, err := send(t, newTestTransport(ModeRecord, dir), server.URL, `{"prompt":"hi"}`)
+	if err != nil || status != http.StatusTooManyRequests {
+		t.Fatalf("record: got %d, %v, want the 429 passed through", status, err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("recorded %d cassettes for an error response, want none", len(entries))
+	}
+}
+
+func TestStreamClosedEarlyIsNotRecorded(t *testing.T) {
+	server, _ := countingServer(t, http.StatusOK)
+	dir := t.TempDir()
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`{"prompt":"hi"}`))
+	resp, err := newTestTransport(ModeRecord, dir).RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	buf := make([]byte, 3)
+	if _, err := io.ReadFull(resp.Body, buf); err != nil {
+		t.Fatalf("failed to read the start of the body: %v", err)
+	}
+	resp.Body.Close()
+
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("recorded %d cassettes for a body closed early, want none", len(entries))
+	}
+}
+
+func TestCanonicalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		same bool
+	}{
+		{"key order", `{"model":"m","messages":[{"role":"user","content":"hi"}]}`, `{"messages":[{"content":"hi","role":"user"}],"model":"m"}`, true},
+		{"whitespace", `{"a": 1, "b": [1, 2]}`, `{"b":[1,2],"a":1}`, true},
+		{"different value", `{"a":1}`, `{"a":2}`, false},
+		{"array order", `{"a":[1,2]}`, `{"a":[2,1]}`, false},
+		{"not JSON", `plain text`, `plain text`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := bytes.Equal(canonicalJSON([]byte(tt.a)), canonicalJSON([]byte(tt.b)))
+			if got != tt.same {
+				t.Errorf("canonicalJSON(%s) == canonicalJSON(%s) is %v, want %v", tt.a, tt.b, got, tt.same)
+			}
+			if keysMatch := Key("POST", "h", "/p", []byte(tt.a)) == Key("POST", "h", "/p", []byte(tt.b)); keysMatch != tt.same {
+				t.Errorf("Key() match = %v, want %v", keysMatch, tt.same)
+			}
+		})
+	}
+}
@@ -5,21 +5,34 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
+
+	"github.com/twin2ai/studio/internal/llm"
 )
 
 const anthropicAPIURL = "https://api.anthropic.com/v1/messages"
 
+// defaultParams are the generation settings used unless configured otherwise:
+// creative persona generation, cooler synthesis and prompt generation, and
+// deterministic evaluation and analysis
+var defaultParams = llm.ParamSet{
+	llm.StageGeneration: {Temperature: llm.Float(0.7), MaxTokens: 20000},
+	llm.StageSynthesis:  {Temperature: llm.Float(0.3), MaxTokens: 20000},
+	llm.StagePrompt:     {Temperature: llm.Float(0.3), MaxTokens: 20000},
+	llm.StageEvaluation: {Temperature: llm.Float(0), MaxTokens: 4000},
+	llm.StageAnalysis:   {Temperature: llm.Float(0), MaxTokens: 8000},
+}
+
 type Client struct {
 	apiKey     string
 	model      string
-	httpClient *http.Client
+	params     llm.ParamSet
+	sender     *llm.Sender
 	logger     *logrus.Logger
 	promptPath string
 }
@@ -30,31 +43,57 @@ type Message struct {
 }
 
 type Request struct {
-	Model       string    `json:"model"`
-	Messages    []Message `json:"messages"`
-	MaxTokens   int       `json:"max_tokens"`
-	Temperature float64   `json:"temperature,omitempty"`
+	Model         string    `json:"model"`
+	System        string    `json:"system,omitempty"`
+	Messages      []Message `json:"messages"`
+	MaxTokens     int       `json:"max_tokens"`
+	Temperature   *float64  `json:"temperature,omitempty"`
+	TopP          *float64  `json:"top_p,omitempty"`
+	StopSequences []string  `json:"stop_sequences,omitempty"`
+	Thinking      *Thinking `json:"thinking,omitempty"`
+	Stream        bool      `json:"stream,omitempty"`
+}
+
+// Thinking enables extended thinking with a token budget
+type Thinking struct {
+	Type         string `json:"type"`
+	BudgetTokens int    `json:"budget_tokens"`
 }
 
 type Response struct {
 	Content []struct {
-		Type string `json:"type"`
-		Text string `json:"text"`
+		Type     string `json:"type"`
+		Text     string `json:"text"`
+		Thinking string `json:"thinking"` // Set on "thinking" blocks
 	} `json:"content"`
+	Model      string `json:"model"`
+	StopReason string `json:"stop_reason"`
+	Usage      struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
 }
 
 func NewClient(apiKey, model string, logger *logrus.Logger) *Client {
 	return &Client{
-		apiKey: apiKey,
-		model:  model,
-		httpClient: &http.Client{
-			Timeout: 600 * time.Second, // 10 minutes, matching other providers
-		},
+		apiKey:     apiKey,
+		model:      model,
+		sender:     llm.NewSender("claude", 600*time.Second, logger), // 10 minutes, matching other providers
 		logger:     logger,
 		promptPath: "prompts/persona_generation.txt",
 	}
 }
 
+// Sender returns the HTTP sender so callers can adjust its retry policy
+func (c *Client) Sender() *llm.Sender {
+	return c.sender
+}
+
+// SetParams overrides the default generation settings per stage
+func (c *Client) SetParams(params llm.ParamSet) {
+	c.params = params
+}
+
 func (c *Client) loadPromptTemplate() (string, error) {
 	data, err := os.ReadFile(c.promptPath)
 	if err != nil {
@@ -97,6 +136,20 @@ Format the output as a well-structured Markdown document suitable for documentat
 func (c *Client) GeneratePersona(ctx context.Context, issueContent string, template string) (string, error) {
 	c.logger.Info("Starting Claude persona generation")
 
+	prompt, err := c.BuildPersonaPrompt(issueContent, template)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := c.Generate(ctx, prompt, llm.StageGeneration)
+	if err != nil {
+		return "", err
+	}
+	return result.Text, nil
+}
+
+// BuildPersonaPrompt fills the persona generation prompt with the issue and template
+func (c *Client) BuildPersonaPrompt(issueContent string, template string) (string, error) {
 	// Load prompt template
 	promptTemplate, err := c.loadPromptTemplate()
 	if err != nil {
@@ -117,84 +170,64 @@ func (c *Client) GeneratePersona(ctx context.Context, issueContent string, templ
 		c.logger.Debug("No template provided")
 	}
 
-	c.logger.Infof("Using Claude model: %s", c.model)
-	c.logger.Debugf("Prompt length: %d characters", len(prompt))
+	return prompt, nil
+}
 
-	// Check if prompt is too large
-	if len(prompt) > 50000 {
-		c.logger.Warnf("Large prompt detected (%d chars), this might cause API issues", len(prompt))
-	}
+// Generate sends a single prompt with the settings for stage and reports the
+// text with its stop reason
+func (c *Client) Generate(ctx context.Context, prompt string, stage llm.Stage) (*llm.Result, error) {
+	return c.Send(ctx, llm.Prompt(prompt), stage)
+}
 
-	request := Request{
-		Model: c.model,
-		Messages: []Message{
-			{
-				Role:    "user",
-				Content: prompt,
-			},
-		},
-		MaxTokens:   20000,
-		Temperature: 0.7,
-	}
+// Send sends a conversation with the settings for stage and reports the
+// text with its stop reason
+func (c *Client) Send(ctx context.Context, req llm.Request, stage llm.Stage) (*llm.Result, error) {
+	started := time.Now()
+	result, err := c.send(ctx, req, stage)
+	c.sender.Observe(ctx, c.model, result, started, err)
+	return result, err
+}
 
-	body, err := json.Marshal(request)
-	if err != nil {
-		c.logger.Errorf("Failed to marshal request: %v", err)
-		return "", fmt.Errorf("failed to marshal request: %w", err)
+// send performs the request for Send
+func (c *Client) send(ctx context.Context, req llm.Request, stage llm.Stage) (*llm.Result, error) {
+	c.logger.Infof("Using Claude model: %s", c.model)
+	c.logger.Debugf("Conversation length: %d characters in %d messages", req.Len(), len(req.Messages))
+
+	// Check if prompt is too large
+	if req.Len() > 50000 {
+		c.logger.Warnf("Large prompt detected (%d chars), this might cause API issues", req.Len())
 	}
-	c.logger.Debugf("Request body length: %d bytes", len(body))
 
-	req, err := http.NewRequestWithContext(ctx, "POST", anthropicAPIURL, bytes.NewReader(body))
+	body, err := c.marshalRequest(req, stage, false)
 	if err != nil {
-		c.logger.Errorf("Failed to create HTTP request: %v", err)
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("x-api-key", c.apiKey)
-	req.Header.Set("anthropic-version", "2023-06-01")
-
 	c.logger.Info("Sending request to Claude API...")
 	c.logger.Debugf("Request URL: %s", anthropicAPIURL)
-	c.logger.Debugf("Request headers: Content-Type=%s, x-api-key=%s..., anthropic-version=%s",
-		req.Header.Get("Content-Type"),
-		c.apiKey[:min(10, len(c.apiKey))]+"...",
-		req.Header.Get("anthropic-version"))
+	c.logger.Debugf("Request headers: Content-Type=application/json, x-api-key=%s..., anthropic-version=2023-06-01",
+		c.apiKey[:min(10, len(c.apiKey))])
 
-	// Add timeout context for the request
 	requestStart := time.Now()
-	resp, err := c.httpClient.Do(req)
+	responseBody, err := c.sender.Do(ctx, func() (*http.Request, error) {
+		return c.newHTTPRequest(ctx, body)
+	})
 	requestDuration := time.Since(requestStart)
 
 	c.logger.Infof("HTTP request completed in %v", requestDuration)
 
 	if err != nil {
-		c.logger.Errorf("HTTP request failed after %v: %v", requestDuration, err)
-		return "", fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	c.logger.Infof("Received response with status: %d", resp.StatusCode)
-	c.logger.Debugf("Response headers: %+v", resp.Header)
-
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		c.logger.Errorf("Failed to read response body: %v", err)
-		return "", fmt.Errorf("failed to read response body: %w", err)
+		c.logger.Errorf("Claude request failed after %v: %v", requestDuration, err)
+		return nil, err
 	}
 	c.logger.Debugf("Response body length: %d bytes", len(responseBody))
 
-	if resp.StatusCode != http.StatusOK {
-		c.logger.Errorf("API request failed with status %d: %s", resp.StatusCode, string(responseBody))
-		return "", fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(responseBody))
-	}
-
 	c.logger.Debug("Parsing Claude API response...")
 	var response Response
 	if err := json.Unmarshal(responseBody, &response); err != nil {
 		c.logger.Errorf("Failed to decode response JSON: %v", err)
 		c.logger.Debugf("Raw response body: %s", string(responseBody))
-		return "", fmt.Errorf("failed to decode response: %w", err)
+		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
 	c.logger.Infof("Response contains %d content items", len(response.Content))
@@ -202,7 +235,7 @@ func (c *Client) GeneratePersona(ctx context.Context, issueContent string, templ
 	if len(response.Content) == 0 {
 		c.logger.Error("Empty content array in Claude response")
 		c.logger.Debugf("Full response: %+v", response)
-		return "", fmt.Errorf("empty response from Claude API")
+		return nil, fmt.Errorf("empty response from Claude API")
 	}
 
 	// Log content types for debugging
@@ -210,6 +243,33 @@ func (c *Client) GeneratePersona(ctx context.Context, issueContent string, templ
 		c.logger.Debugf("Content[%d]: type=%s, text_length=%d", i, content.Type, len(content.Text))
 	}
 
+	result := &llm.Result{
+		StopReason: stopReason(response.StopReason),
+		Usage: llm.Usage{
+			InputTokens:  response.Usage.InputTokens,
+			OutputTokens: response.Usage.OutputTokens,
+		},
+		Model: response.Model,
+	}
+	if result.StopReason.Truncated() {
+		c.logger.Warnf("Claude response hit the max_tokens limit (%d output tokens)", response.Usage.OutputTokens)
+	}
+
+	// Keep the thinking blocks separately so they can be reviewed
+	var thinking []string
+	for _, content := range response.Content {
+		switch content.Type {
+		case "thinking":
+			thinking = append(thinking, content.Thinking)
+		case "redacted_thinking":
+			thinking = append(thinking, "_[Thinking redacted by the provider]_")
+		}
+	}
+	result.Thinking = strings.Join(thinking, "\n\n")
+	if result.Thinking != "" {
+		c.logger.Infof("Captured %d thinking blocks (%d characters)", len(thinking), len(result.Thinking))
+	}
+
 	// With extended thinking, we want the final text content, not the thinking
 	for _, content := range response.Content {
 		if content.Type == "text" {
@@ -217,18 +277,103 @@ func (c *Client) GeneratePersona(ctx context.Context, issueContent string, templ
 			if content.Text == "" {
 				c.logger.Warn("Text content is empty")
 			}
-			return content.Text, nil
+			result.Text = content.Text
+			if prefillJSON(req, defaultParams.Resolve(stage, c.params)) {
+				result.Text = "{" + result.Text
+			}
+			return result, nil
 		}
 	}
 
 	// Fallback to first content if no specific text type found
 	c.logger.Warn("No 'text' type content found, using first content item")
-	firstContent := response.Content[0].Text
-	c.logger.Infof("Using fallback content, length: %d characters", len(firstContent))
-	if firstContent == "" {
+	result.Text = response.Content[0].Text
+	c.logger.Infof("Using fallback content, length: %d characters", len(result.Text))
+	if result.Text == "" {
 		c.logger.Error("Fallback content is also empty")
 	}
-	return firstContent, nil
+	return result, nil
+}
+
+// marshalRequest builds the Messages API request body for a conversation
+func (c *Client) marshalRequest(req llm.Request, stage llm.Stage, stream bool) ([]byte, error) {
+	params := defaultParams.Resolve(stage, c.params)
+	request := Request{
+		Model:         c.model,
+		System:        req.System,
+		Messages:      make([]Message, 0, len(req.Messages)),
+		MaxTokens:     params.MaxTokens,
+		Temperature:   params.Temperature,
+		TopP:          params.TopP,
+		StopSequences: params.StopSequences,
+		Stream:        stream,
+	}
+	for _, m := range req.Messages {
+		request.Messages = append(request.Messages, Message{Role: string(m.Role), Content: m.Content})
+	}
+
+	if params.ThinkingBudget > 0 {
+		if params.ThinkingBudget >= params.MaxTokens {
+			return nil, fmt.Errorf("thinking budget %d must be below max tokens %d", params.ThinkingBudget, params.MaxTokens)
+		}
+		// Extended thinking does not accept sampling overrides
+		request.Thinking = &Thinking{Type: "enabled", BudgetTokens: params.ThinkingBudget}
+		request.Temperature = nil
+		request.TopP = nil
+	}
+	if params.Seed != nil {
+		c.logger.Debug("Claude does not support a sampling seed, ignoring it")
+	}
+	if len(req.Schema) > 0 {
+		if prefillJSON(req, params) {
+			// Claude has no JSON mode; starting its reply with a brace keeps it to the document
+			request.Messages = append(request.Messages, Message{Role: "assistant", Content: "{"})
+		} else {
+			c.logger.Debug("Extended thinking cannot be combined with a prefilled reply, relying on the prompt for JSON output")
+		}
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		c.logger.Errorf("Failed to marshal request: %v", err)
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	c.logger.Debugf("Request body length: %d bytes", len(body))
+	return body, nil
+}
+
+// prefillJSON reports whether a JSON reply is requested by prefilling "{",
+// which extended thinking does not allow
+func prefillJSON(req llm.Request, params llm.Params) bool {
+	return len(req.Schema) > 0 && params.ThinkingBudget == 0
+}
+
+// newHTTPRequest creates an authenticated Messages API request
+func (c *Client) newHTTPRequest(ctx context.Context, body []byte) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", anthropicAPIURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", c.apiKey)
+	req.Header.Set("anthropic-version", "2023-06-01")
+	return req, nil
+}
+
+// stopReason maps Anthropic's stop_reason onto llm.StopReason
+func stopReason(reason string) llm.StopReason {
+	switch reason {
+	case "end_turn":
+		return llm.StopEnd
+	case "max_tokens":
+		return llm.StopMaxTokens
+	case "stop_sequence":
+		return llm.StopSequence
+	case "refusal":
+		return llm.StopSafety
+	default:
+		return llm.StopUnknown
+	}
 }
 
 func min(a, b int) int {
@@ -0,0 +1,61 @@
+package claude
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+const anthropicModelsURL = "https://api.anthropic.com/v1/models"
+
+// TestConnection sends a minimal message to confirm the API key and model work
+func (c *Client) TestConnection(ctx context.Context) error {
+	body, err := json.Marshal(Request{
+		Model:     c.model,
+		Messages:  []Message{{Role: "user", Content: "Say 'Hello, this is a test' to confirm the API is working."}},
+		MaxTokens: 10,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	_, err = c.sender.Do(ctx, func() (*http.Request, error) {
+		return c.newHTTPRequest(ctx, body)
+	})
+	if err != nil {
+		return fmt.Errorf("API connection test failed: %w", err)
+	}
+	return nil
+}
+
+// ListAvailableModels returns the model IDs available to the API key
+func (c *Client) ListAvailableModels(ctx context.Context) ([]string, error) {
+	responseBody, err := c.sender.Do(ctx, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", anthropicModelsURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("x-api-key", c.apiKey)
+		req.Header.Set("anthropic-version", "2023-06-01")
+		return req, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list models: %w", err)
+	}
+
+	var response struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(responseBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to decode models: %w", err)
+	}
+
+	models := make([]string, 0, len(response.Data))
+	for _, model := range response.Data {
+		models = append(models, model.ID)
+	}
+	return models, nil
+}
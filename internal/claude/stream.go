@@ -0,0 +1,161 @@
+package claude
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/twin2ai/studio/internal/llm"
+)
+
+// streamEvent covers the fields used from Messages API server-sent events
+type streamEvent struct {
+	Type    string `json:"type"`
+	Message struct {
+		Model string `json:"model"`
+		Usage struct {
+			InputTokens int `json:"input_tokens"`
+		} `json:"usage"`
+	} `json:"message"`
+	ContentBlock struct {
+		Type string `json:"type"`
+	} `json:"content_block"`
+	Delta struct {
+		Type       string `json:"type"`
+		Text       string `json:"text"`
+		Thinking   string `json:"thinking"`
+		StopReason string `json:"stop_reason"`
+	} `json:"delta"`
+	Usage struct {
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// GenerateStream sends a single prompt using server-sent events and calls
+// onDelta with each piece of text as it arrives. If the stream breaks after
+// text has been delivered, the partial text is returned with
+// llm.StopInterrupt so it can be continued.
+func (c *Client) GenerateStream(ctx context.Context, prompt string, stage llm.Stage, onDelta func(text string)) (*llm.Result, error) {
+	return c.SendStream(ctx, llm.Prompt(prompt), stage, onDelta)
+}
+
+// SendStream streams the reply to a conversation like GenerateStream
+func (c *Client) SendStream(ctx context.Context, req llm.Request, stage llm.Stage, onDelta func(text string)) (*llm.Result, error) {
+	started := time.Now()
+	result, err := c.sendStream(ctx, req, stage, onDelta)
+	c.sender.Observe(ctx, c.model, result, started, err)
+	return result, err
+}
+
+// sendStream performs the request for SendStream
+func (c *Client) sendStream(ctx context.Context, req llm.Request, stage llm.Stage, onDelta func(text string)) (*llm.Result, error) {
+	c.logger.Infof("Streaming from Claude model: %s", c.model)
+	c.logger.Debugf("Conversation length: %d characters in %d messages", req.Len(), len(req.Messages))
+
+	body, err := c.marshalRequest(req, stage, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *llm.Result
+	var text, thinking strings.Builder
+	requestStart := time.Now()
+
+	err = c.sender.Stream(ctx, func(ctx context.Context) (*http.Request, error) {
+		return c.newHTTPRequest(ctx, body)
+	}, func(r io.Reader) error {
+		result = &llm.Result{Model: c.model, StopReason: llm.StopUnknown}
+		text.Reset()
+		thinking.Reset()
+		if prefillJSON(req, defaultParams.Resolve(stage, c.params)) {
+			text.WriteString("{")
+		}
+		stopped := false
+
+		readErr := llm.ReadSSE(r, func(event, data string) error {
+			var ev streamEvent
+			if err := json.Unmarshal([]byte(data), &ev); err != nil {
+				return fmt.Errorf("failed to decode stream event: %w", err)
+			}
+
+			switch ev.Type {
+			case "message_start":
+				if ev.Message.Model != "" {
+					result.Model = ev.Message.Model
+				}
+				result.Usage.InputTokens = ev.Message.Usage.InputTokens
+			case "content_block_start":
+				// Separate consecutive thinking blocks
+				if strings.HasSuffix(ev.ContentBlock.Type, "thinking") && thinking.Len() > 0 {
+					thinking.WriteString("\n\n")
+				}
+				if ev.ContentBlock.Type == "redacted_thinking" {
+					thinking.WriteString("_[Thinking redacted by the provider]_")
+				}
+			case "content_block_delta":
+				// Thinking deltas are kept apart from the persona text
+				switch {
+				case ev.Delta.Type == "thinking_delta":
+					thinking.WriteString(ev.Delta.Thinking)
+				case ev.Delta.Type == "text_delta" && ev.Delta.Text != "":
+					text.WriteString(ev.Delta.Text)
+					if onDelta != nil {
+						onDelta(ev.Delta.Text)
+					}
+				}
+			case "message_delta":
+				if ev.Delta.StopReason != "" {
+					result.StopReason = stopReason(ev.Delta.StopReason)
+				}
+				result.Usage.OutputTokens = ev.Usage.OutputTokens
+			case "message_stop":
+				stopped = true
+			case "error":
+				// Overloaded and API errors can arrive mid-stream with status 200
+				status := http.StatusInternalServerError
+				if ev.Error.Type == "overloaded_error" {
+					status = 529
+				}
+				return &llm.APIError{StatusCode: status, Body: data}
+			}
+			return nil
+		})
+
+		if readErr != nil {
+			if text.Len() == 0 {
+				return readErr
+			}
+			c.logger.Warnf("Claude stream interrupted after %d characters: %v", text.Len(), readErr)
+			result.StopReason = llm.StopInterrupt
+		} else if !stopped && text.Len() > 0 {
+			c.logger.Warnf("Claude stream closed without message_stop after %d characters", text.Len())
+			result.StopReason = llm.StopInterrupt
+		}
+		return nil
+	})
+
+	c.logger.Infof("Claude stream completed in %v", time.Since(requestStart))
+	if err != nil {
+		c.logger.Errorf("Claude stream failed: %v", err)
+		return nil, err
+	}
+
+	result.Text = text.String()
+	result.Thinking = thinking.String()
+	if result.Text == "" {
+		return nil, fmt.Errorf("empty response from Claude API")
+	}
+	if result.StopReason.Truncated() {
+		c.logger.Warnf("Claude stream ended with stop reason %s (%d output tokens)", result.StopReason, result.Usage.OutputTokens)
+	}
+
+	return result, nil
+}
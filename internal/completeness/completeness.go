@@ -0,0 +1,310 @@
+package completeness
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/twin2ai/studio/internal/facts"
+	"github.com/twin2ai/studio/internal/llm"
+	"github.com/twin2ai/studio/internal/provider"
+	"github.com/twin2ai/studio/internal/validation"
+	"github.com/twin2ai/studio/pkg/models"
+)
+
+var listItemPattern = regexp.MustCompile(`^(?:[-*+]|\d+[.)])\s`)
+
+// Policy decides when a synthesis is incomplete and how it is repaired
+// before the PR is opened
+type Policy struct {
+	Retries        int  // Extra synthesis runs while the result is incomplete
+	MinSizePercent int  // Size, as a share of the median raw output, below which a synthesis is cut short; 0 disables the check
+	BySection      bool // Synthesize each template section on its own when the retries do not help
+}
+
+// DefaultPolicy is used until a policy is configured
+var DefaultPolicy = Policy{Retries: 1, MinSizePercent: 50, BySection: true}
+
+// Source is a raw output a synthesis is built from
+type Source struct {
+	Name       string
+	Content    string
+	StopReason llm.StopReason
+}
+
+// Context is what a combination prompt adds to the raw outputs. The
+// section-by-section fallback passes it on to every section prompt so a
+// rebuilt persona still follows it.
+type Context struct {
+	Feedback    string       // Formatted reviewer feedback to address; empty when there is none
+	UserPersona string       // User-supplied persona combined with the raw outputs
+	Facts       *facts.Sheet // Fact sheet from the raw outputs
+}
+
+// Result is the most complete synthesis found
+type Result struct {
+	provider.Synthesis
+	BySection bool // Rebuilt one template section at a time
+}
+
+// Synthesizer runs combination prompts through a synthesis chain and
+// repairs incomplete results
+type Synthesizer struct {
+	chain  *provider.SynthesisChain
+	linter *validation.Linter
+	policy Policy
+	logger *logrus.Logger
+}
+
+// NewSynthesizer creates a synthesizer. Without a linter the required
+// sections are not checked and there is no section-by-section fallback.
+func NewSynthesizer(chain *provider.SynthesisChain, linter *validation.Linter, policy Policy, logger *logrus.Logger) *Synthesizer {
+	return &Synthesizer{
+		chain:  chain,
+		linter: linter,
+		policy: policy,
+		logger: logger,
+	}
+}
+
+// Synthesize runs a combination prompt through the synthesis chain and
+// checks the result for truncation. An incomplete synthesis is run again,
+// then rebuilt section by section, and the most complete result is kept.
+func (s *Synthesizer) Synthesize(ctx context.Context, prompt string, sources []Source, sc Context) (*Result, error) {
+	synthesis, err := s.chain.Complete(ctx, llm.Prompt(prompt), llm.StageSynthesis)
+	if err != nil {
+		return nil, err
+	}
+	best := &Result{Synthesis: *synthesis}
+
+	check := s.newCheck(sources)
+	problems := check.problems(synthesis)
+	for attempt := 1; len(problems) > 0 && attempt <= s.policy.Retries; attempt++ {
+		s.logger.Warnf("Synthesis by %s is incomplete (%s); running it again (attempt %d/%d)",
+			best.Provider, strings.Join(problems, "; "), attempt, s.policy.Retries)
+
+		retry, err := s.chain.Complete(ctx, llm.Prompt(prompt), llm.StageSynthesis)
+		if err != nil {
+			s.logger.Warnf("Synthesis retry failed: %v", err)
+			break
+		}
+		if retryProblems := check.problems(retry); len(retryProblems) < len(problems) {
+			best = &Result{Synthesis: *retry}
+			problems = retryProblems
+		}
+	}
+
+	if len(problems) > 0 && s.policy.BySection && s.linter != nil {
+		s.logger.Warnf("Synthesis is still incomplete (%s); synthesizing section by section", strings.Join(problems, "; "))
+		sectioned, err := s.synthesizeBySection(ctx, sources, sc)
+		if err != nil {
+			s.logger.Warnf("Section-by-section synthesis failed: %v", err)
+		} else if sectionProblems := check.problems(sectioned); len(sectionProblems) < len(problems) {
+			best = &Result{Synthesis: *sectioned, BySection: true}
+			problems = sectionProblems
+		}
+	}
+
+	if len(problems) > 0 {
+		s.logger.Warnf("Keeping incomplete synthesis by %s: %s", best.Provider, strings.Join(problems, "; "))
+	}
+	return best, nil
+}
+
+// Problems lists why a raw output looks incomplete on its own, so it
+// should not be used as the persona without a synthesis
+func (s *Synthesizer) Problems(source Source) []string {
+	return s.newCheck([]Source{source}).problems(
+		&provider.Synthesis{Text: source.Content, StopReason: source.StopReason})
+}
+
+// check holds what a synthesis of some raw outputs is compared against
+type check struct {
+	minLength int
+	sections  []validation.Requirement // Required sections covered by at least one raw output
+}
+
+// newCheck measures the raw outputs a synthesis is built from
+func (s *Synthesizer) newCheck(sources []Source) *check {
+	c := &check{}
+
+	var lengths []int
+	var docs []*models.Persona
+	for _, source := range sources {
+		if source.Content == "" {
+			continue
+		}
+		lengths = append(lengths, len(strings.TrimSpace(source.Content)))
+		if doc, err := models.Parse(source.Content); err == nil {
+			docs = append(docs, doc)
+		}
+	}
+	if len(lengths) > 0 {
+		sort.Ints(lengths)
+		c.minLength = lengths[len(lengths)/2] * s.policy.MinSizePercent / 100
+	}
+
+	if s.linter != nil {
+		for _, req := range s.linter.Required() {
+			for _, doc := range docs {
+				if validation.FindSection(doc, req) != nil {
+					c.sections = append(c.sections, req)
+					break
+				}
+			}
+		}
+	}
+	return c
+}
+
+// problems lists why a synthesis looks incomplete; it is empty when the
+// synthesis looks complete
+func (c *check) problems(s *provider.Synthesis) []string {
+	var problems []string
+	text := strings.TrimSpace(s.Text)
+
+	if s.StopReason.Truncated() {
+		problems = append(problems, fmt.Sprintf("generation stopped early (%s)", s.StopReason))
+	}
+	if len(text) < c.minLength {
+		problems = append(problems, fmt.Sprintf("only %d characters against a minimum of %d from the raw outputs", len(text), c.minLength))
+	}
+	if cutOff(text) {
+		tail := text
+		if len(tail) > 40 {
+			tail = "..." + tail[len(tail)-40:]
+		}
+		problems = append(problems, fmt.Sprintf("ends mid-sentence: %q", tail))
+	}
+
+	if len(c.sections) > 0 {
+		doc, err := models.Parse(text)
+		var missing []string
+		for _, req := range c.sections {
+			if err != nil || validation.FindSection(doc, req) == nil {
+				missing = append(missing, req.Heading())
+			}
+		}
+		if len(missing) > 3 {
+			missing = append(missing[:3], fmt.Sprintf("and %d more", len(missing)-3))
+		}
+		if len(missing) > 0 {
+			problems = append(problems, fmt.Sprintf("missing sections the raw outputs have: %s", strings.Join(missing, ", ")))
+		}
+	}
+	return problems
+}
+
+// cutOff reports whether text stops mid-block: inside a code fence, on a
+// heading with nothing below it, or on a prose line that ends in a word or
+// a connector rather than closing punctuation. List items often end without
+// punctuation, so only a trailing connector counts for them.
+func cutOff(text string) bool {
+	if text == "" {
+		return false
+	}
+	if strings.Count(text, "```")%2 != 0 {
+		return true
+	}
+
+	last := strings.TrimSpace(text[strings.LastIndex(text, "\n")+1:])
+	if strings.HasPrefix(last, "#") {
+		return true
+	}
+	r, _ := utf8.DecodeLastRuneInString(last)
+	if strings.ContainsRune(",;:(-–—/&", r) {
+		return true
+	}
+	if unicode.IsLetter(r) || unicode.IsDigit(r) {
+		return !listItemPattern.MatchString(last) && !strings.HasPrefix(last, "|")
+	}
+	return false
+}
+
+// synthesizeBySection combines the raw outputs, and the user-supplied
+// persona if any, one required template section at a time, so no single
+// reply has to hold the whole persona. Every section prompt carries the
+// feedback and fact sheet of the full combination prompt.
+func (s *Synthesizer) synthesizeBySection(ctx context.Context, sources []Source, sc Context) (*provider.Synthesis, error) {
+	var docs []*models.Persona
+	var names []string
+	for _, source := range sources {
+		if source.Content == "" {
+			continue
+		}
+		if doc, err := models.Parse(source.Content); err == nil {
+			docs = append(docs, doc)
+			names = append(names, source.Name)
+		}
+	}
+	if sc.UserPersona != "" {
+		if doc, err := models.Parse(sc.UserPersona); err == nil {
+			docs = append(docs, doc)
+			names = append(names, "User-Supplied")
+		} else {
+			s.logger.Warnf("Leaving the user-supplied persona out of section-by-section synthesis: %v", err)
+		}
+	}
+
+	// The context every section is combined under
+	var guidance strings.Builder
+	if sc.Feedback != "" {
+		fmt.Fprintf(&guidance, "USER FEEDBACK TO ADDRESS (apply the points that concern this section):\n\n%s\n", sc.Feedback)
+	}
+	if sc.Facts != nil && len(sc.Facts.Facts) > 0 {
+		guidance.WriteString(sc.Facts.PromptBlock())
+		guidance.WriteString("\n")
+	}
+
+	result := &provider.Synthesis{StopReason: llm.StopEnd}
+	var b strings.Builder
+	for _, doc := range docs {
+		if doc.Title != "" {
+			fmt.Fprintf(&b, "# %s\n\n", doc.Title)
+			break
+		}
+	}
+
+	sections := 0
+	for _, req := range s.linter.Required() {
+		var versions []string
+		for i, doc := range docs {
+			if section := validation.FindSection(doc, req); section != nil {
+				rendered := (&models.Persona{Sections: []models.Section{*section}}).Markdown()
+				versions = append(versions, fmt.Sprintf("Version %d: %s\n<<<\n%s>>>", len(versions)+1, names[i], rendered))
+			}
+		}
+		if len(versions) == 0 {
+			continue
+		}
+
+		sectionReq := llm.Request{System: fmt.Sprintf(`You combine several versions of one section of a persona profile, each written by a different AI provider, into the best single version. Keep the specific facts, quotes, dates and examples, drop repetition, and resolve contradictions in favor of the better-supported claim.
+
+Start your response with the heading "## %s" and write only that section. Do NOT include any preambles or meta-commentary.`, req.Heading())}.User(guidance.String() + strings.Join(versions, "\n\n"))
+
+		synthesis, err := s.chain.Complete(ctx, sectionReq, llm.StageSynthesis)
+		if err != nil {
+			return nil, fmt.Errorf("failed to synthesize section %s: %w", req.Heading(), err)
+		}
+		b.WriteString(strings.TrimSpace(synthesis.Text))
+		b.WriteString("\n\n")
+		sections++
+
+		result.Provider, result.Model = synthesis.Provider, synthesis.Model
+		if synthesis.StopReason.Truncated() {
+			result.StopReason = synthesis.StopReason
+		}
+	}
+
+	if sections == 0 {
+		return nil, fmt.Errorf("no raw output has the template's sections")
+	}
+	result.Text = b.String()
+	return result, nil
+}
@@ -2,13 +2,21 @@ package config
 
 import (
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/twin2ai/studio/internal/llm"
 )
 
 type Config struct {
 	GitHub   GitHubConfig
 	AI       AIConfig
 	Pipeline PipelineConfig
+	Usage    UsageConfig
+	Cassette CassetteConfig
+	DryRun   DryRunConfig
 }
 
 type GitHubConfig struct {
@@ -25,16 +33,80 @@ type AIConfig struct {
 	Gemini GeminiConfig
 	Grok   GrokConfig
 	GPT    GPTConfig
+
+	// Providers lists the generating providers in the order they are queried
+	Providers []ProviderConfig
+
+	// Synthesizers lists the synthesis providers in fallback order
+	Synthesizers []ProviderConfig
+
+	// Judges lists the providers that score personas, in fallback order
+	Judges []ProviderConfig
+
+	// MaxContinuations caps the follow-up requests for output cut off at the token limit
+	MaxContinuations int
+
+	// Quorum is the share of providers that must succeed before synthesis
+	Quorum QuorumConfig
+
+	// Completeness decides when a synthesis is incomplete and how it is repaired
+	Completeness CompletenessConfig
+
+	// Breaker decides when a failing provider is skipped and for how long
+	Breaker llm.BreakerPolicy
+	// BreakerStateFile is where breakers are persisted, shared by every process
+	BreakerStateFile string
+	// RateLimitStateFile holds the rate limit buckets shared by every process
+	RateLimitStateFile string
+}
+
+// CompletenessConfig decides when a synthesis is treated as truncated and
+// synthesized again before the PR is opened
+type CompletenessConfig struct {
+	Retries        int  // Extra synthesis runs while the result is incomplete
+	MinSizePercent int  // Size, as a share of the median raw output, below which a synthesis is cut short
+	BySection      bool // Synthesize each template section separately when the retries do not help
+}
+
+// QuorumConfig decides when enough providers have succeeded to synthesize a persona
+type QuorumConfig struct {
+	MinProviders int      // Minimum number of successful providers
+	Required     []string // Providers that must succeed, by name
+	Retries      int      // Extra attempts for the failed providers when the quorum is missed
+	OnFailure    string   // "retry" leaves the issue for the next run, "pause" labels it
+	MaxAttempts  int      // Runs that may miss the quorum under "retry" before the issue is paused; 0 means no limit
+	PauseLabel   string   // Label put on paused issues; issues carrying it are skipped
+}
+
+// ProviderConfig describes one entry in the provider registry
+type ProviderConfig struct {
+	Name   string // Unique name, used for artifacts and raw/<name>.md
+	Type   string // Client implementation (claude, gemini, grok, gpt, openai)
+	APIKey string
+	Model  string
+
+	MaxRetries int           // Retries after a transient failure (429, 5xx, overloaded, connection reset)
+	Stream     bool          // Stream output and write partial artifacts as it arrives (claude, gemini)
+	Params     llm.ParamSet  // Generation settings per stage, overriding the client defaults
+	RateLimit  llm.RateLimit // Requests and tokens per minute, shared by every client of this provider
+
+	// OpenAI-compatible settings (grok, gpt, openai types)
+	BaseURL    string            // API root; empty uses the type's default endpoint
+	Headers    map[string]string // Extra request headers
+	AuthHeader string            // Header carrying the API key
+	AuthScheme string            // Scheme prefixed to the API key ("none" for the raw key)
 }
 
 type ClaudeConfig struct {
 	APIKey string
 	Model  string
+	Params llm.ParamSet
 }
 
 type GeminiConfig struct {
 	APIKey string
 	Model  string
+	Params llm.ParamSet // Also used by the default synthesis provider
 }
 
 type GrokConfig struct {
@@ -47,10 +119,53 @@ type GPTConfig struct {
 	Model  string
 }
 
+// UsageConfig controls the token usage and cost ledger
+type UsageConfig struct {
+	LedgerPath string // JSON Lines file receiving one record per provider call
+	Prices     string // MODEL_PRICES overrides: model=input/output per million tokens
+}
+
+// CassetteConfig controls record/replay of AI provider calls
+type CassetteConfig struct {
+	Mode string // off, record, replay or auto
+	Dir  string
+}
+
+// DryRunConfig controls the fake providers and GitHub recorder used by --dry-run
+type DryRunConfig struct {
+	FixturesDir string // issues.json and contents/<path> served instead of the built-in sample
+	OutputDir   string // receives a copy of every file the run would commit
+}
+
 type PipelineConfig struct {
 	PollInterval time.Duration
 	DataDir      string
 	LogDir       string
+	ArtifactsDir string // Local copies of every provider and combined output
+
+	// PublishThinking commits extended thinking to the persona folder as well
+	PublishThinking bool
+
+	// StructuredOutput also generates persona.json after synthesis
+	StructuredOutput bool
+
+	// ValidationMinWords is the word count below which the linter reports a
+	// persona section as short
+	ValidationMinWords int
+
+	// Evaluation scores the raw outputs and synthesis with a judge model
+	Evaluation bool
+
+	// EvaluationRubric is the file the judge's criteria are read from
+	EvaluationRubric string
+
+	// Analysis compares the claims of the raw outputs and reports where the
+	// providers disagree
+	Analysis bool
+
+	// FactSheet extracts facts.json from the raw outputs and adds its
+	// consensus to the synthesis prompt
+	FactSheet bool
 }
 
 func Load() (*Config, error) {
@@ -59,7 +174,7 @@ func Load() (*Config, error) {
 		pollInterval = 5 * time.Minute
 	}
 
-	return &Config{
+	cfg := &Config{
 		GitHub: GitHubConfig{
 			Token:         getEnv("GITHUB_TOKEN", ""),
 			Owner:         getEnv("GITHUB_OWNER", ""),
@@ -72,10 +187,12 @@ func Load() (*Config, error) {
 			Claude: ClaudeConfig{
 				APIKey: getEnv("ANTHROPIC_API_KEY", ""),
 				Model:  getEnv("CLAUDE_MODEL", "claude-opus-4-20250514"),
+				Params: loadParams("CLAUDE"),
 			},
 			Gemini: GeminiConfig{
 				APIKey: getEnv("GOOGLE_API_KEY", ""),
 				Model:  getEnv("GEMINI_MODEL", "gemini-2.0-flash-exp"),
+				Params: loadParams("GEMINI"),
 			},
 			Grok: GrokConfig{
 				APIKey: getEnv("GROK_API_KEY", ""),
@@ -85,13 +202,239 @@ func Load() (*Config, error) {
 				APIKey: getEnv("OPENAI_API_KEY", ""),
 				Model:  getEnv("GPT_MODEL", "gpt-4"),
 			},
+			MaxContinuations: getEnvInt("MAX_CONTINUATIONS", 3),
+			Completeness: CompletenessConfig{
+				Retries:        getEnvInt("SYNTHESIS_RETRIES", 1),
+				MinSizePercent: getEnvInt("SYNTHESIS_MIN_SIZE_PERCENT", 50),
+				BySection:      getEnvBool("SYNTHESIS_BY_SECTION", true),
+			},
 		},
 		Pipeline: PipelineConfig{
 			PollInterval: pollInterval,
 			DataDir:      getEnv("DATA_DIR", "./data"),
 			LogDir:       getEnv("LOG_DIR", "./logs"),
+			ArtifactsDir: getEnv("ARTIFACTS_DIR", "artifacts"),
+
+			PublishThinking:  getEnvBool("PUBLISH_THINKING", false),
+			StructuredOutput: getEnvBool("STRUCTURED_OUTPUT", false),
+
+			ValidationMinWords: getEnvInt("VALIDATION_MIN_WORDS", 100),
+
+			Evaluation:       getEnvBool("EVALUATION", false),
+			EvaluationRubric: getEnv("EVALUATION_RUBRIC", "prompts/evaluation_rubric.txt"),
+
+			Analysis:  getEnvBool("ANALYSIS", false),
+			FactSheet: getEnvBool("FACT_SHEET", true),
 		},
-	}, nil
+	}
+
+	cfg.Usage = UsageConfig{
+		LedgerPath: getEnv("USAGE_LEDGER", filepath.Join(cfg.Pipeline.DataDir, "usage.jsonl")),
+		Prices:     getEnv("MODEL_PRICES", ""),
+	}
+
+	cfg.Cassette = CassetteConfig{
+		Mode: getEnv("CASSETTE_MODE", "off"),
+		Dir:  getEnv("CASSETTE_DIR", "cassettes"),
+	}
+
+	cfg.DryRun = DryRunConfig{
+		FixturesDir: getEnv("DRY_RUN_FIXTURES", ""),
+		OutputDir:   getEnv("DRY_RUN_OUTPUT", ""),
+	}
+
+	cfg.AI.Providers = loadProviders(getEnv("AI_PROVIDERS", "claude,gemini,grok,gpt"), cfg.AI)
+	synthesizers := getEnv("SYNTHESIS_PROVIDERS", "gemini")
+	cfg.AI.Synthesizers = loadProviders(synthesizers, cfg.AI)
+	cfg.AI.Judges = loadProviders(getEnv("JUDGE_PROVIDERS", synthesizers), cfg.AI)
+
+	breakerCooldown, err := time.ParseDuration(getEnv("BREAKER_COOLDOWN", "5m"))
+	if err != nil {
+		breakerCooldown = llm.DefaultBreakerPolicy().Cooldown
+	}
+	cfg.AI.Breaker = llm.BreakerPolicy{
+		FailureThreshold: getEnvInt("BREAKER_FAILURE_THRESHOLD", llm.DefaultBreakerPolicy().FailureThreshold),
+		Cooldown:         breakerCooldown,
+	}
+	cfg.AI.BreakerStateFile = getEnv("BREAKER_STATE", filepath.Join(cfg.Pipeline.DataDir, "breakers.json"))
+	cfg.AI.RateLimitStateFile = getEnv("RATE_LIMIT_STATE", filepath.Join(cfg.Pipeline.DataDir, "rate_limits.json"))
+
+	cfg.AI.Quorum = QuorumConfig{
+		MinProviders: getEnvInt("QUORUM_MIN_PROVIDERS", 1),
+		Required:     splitList(getEnv("QUORUM_REQUIRED_PROVIDERS", "")),
+		Retries:      getEnvInt("QUORUM_RETRIES", 1),
+		OnFailure:    getEnv("QUORUM_ON_FAILURE", "retry"),
+		MaxAttempts:  getEnvInt("QUORUM_MAX_ATTEMPTS", 5),
+		PauseLabel:   getEnv("QUORUM_PAUSE_LABEL", "studio-paused"),
+	}
+
+	return cfg, nil
+}
+
+// loadProviders parses a comma-separated provider list. Each entry is either
+// a provider type ("claude") or a name:type pair ("claude-sonnet:claude"),
+// which allows the same client type to be registered more than once.
+// Per-provider settings are read from <NAME>_MODEL and <NAME>_API_KEY and
+// fall back to the defaults of the provider type.
+func loadProviders(list string, ai AIConfig) []ProviderConfig {
+	var providers []ProviderConfig
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, providerType := entry, entry
+		if parts := strings.SplitN(entry, ":", 2); len(parts) == 2 {
+			name = strings.TrimSpace(parts[0])
+			providerType = strings.TrimSpace(parts[1])
+		}
+
+		defaultKey, defaultModel := ai.defaultsFor(providerType)
+		prefix := EnvPrefix(name)
+
+		providers = append(providers, ProviderConfig{
+			Name:       name,
+			Type:       providerType,
+			APIKey:     getEnv(prefix+"_API_KEY", defaultKey),
+			Model:      getEnv(prefix+"_MODEL", defaultModel),
+			MaxRetries: getEnvInt(prefix+"_MAX_RETRIES", 3),
+			Stream:     getEnvBool(prefix+"_STREAM", providerType == "claude" || providerType == "gemini"),
+			Params:     loadParams(prefix),
+			RateLimit: llm.RateLimit{
+				RequestsPerMinute: getEnvInt(prefix+"_REQUESTS_PER_MINUTE", 30),
+				TokensPerMinute:   getEnvInt(prefix+"_TOKENS_PER_MINUTE", 0),
+			},
+			BaseURL:    getEnv(prefix+"_BASE_URL", ""),
+			Headers:    parseHeaders(getEnv(prefix+"_HEADERS", "")),
+			AuthHeader: getEnv(prefix+"_AUTH_HEADER", "Authorization"),
+			AuthScheme: getEnv(prefix+"_AUTH_SCHEME", "Bearer"),
+		})
+	}
+	return providers
+}
+
+// HasSynthesizerKey reports whether any synthesis provider has an API key
+func (ai AIConfig) HasSynthesizerKey() bool {
+	for _, s := range ai.Synthesizers {
+		if s.APIKey != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// ProviderNames returns the names of the generation providers, in order
+func (ai AIConfig) ProviderNames() []string {
+	names := make([]string, 0, len(ai.Providers))
+	for _, p := range ai.Providers {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// defaultsFor returns the API key and model configured for a provider type
+func (ai AIConfig) defaultsFor(providerType string) (string, string) {
+	switch providerType {
+	case "claude":
+		return ai.Claude.APIKey, ai.Claude.Model
+	case "gemini":
+		return ai.Gemini.APIKey, ai.Gemini.Model
+	case "grok":
+		return ai.Grok.APIKey, ai.Grok.Model
+	case "gpt":
+		return ai.GPT.APIKey, ai.GPT.Model
+	default:
+		return "", ""
+	}
+}
+
+// defaultThinkingBudget is used when thinking is enabled without a budget
+const defaultThinkingBudget = 10000
+
+// loadParams reads the generation settings for an environment prefix.
+// <PREFIX>_TEMPERATURE, _TOP_P, _MAX_TOKENS, _SEED, _STOP_SEQUENCES and
+// _THINKING_BUDGET apply to every stage; <PREFIX>_<STAGE>_<SETTING> overrides
+// one stage (GENERATION, SYNTHESIS, FEEDBACK or PROMPT). Feedback
+// regeneration inherits the generation settings. <PREFIX>_THINKING turns
+// extended thinking on (with a default budget) or off for every stage.
+func loadParams(prefix string) llm.ParamSet {
+	base := readParams(prefix)
+	params := make(llm.ParamSet)
+	for _, stage := range llm.Stages {
+		inherited := base
+		if stage == llm.StageFeedback {
+			inherited = params[llm.StageGeneration]
+		}
+		params[stage] = inherited.Merge(readParams(prefix + "_" + strings.ToUpper(string(stage))))
+	}
+
+	thinking, err := strconv.ParseBool(os.Getenv(prefix + "_THINKING"))
+	if err != nil {
+		return params
+	}
+	for stage, p := range params {
+		switch {
+		case !thinking:
+			p.ThinkingBudget = 0
+		case p.ThinkingBudget == 0:
+			p.ThinkingBudget = defaultThinkingBudget
+		}
+		params[stage] = p
+	}
+	return params
+}
+
+// readParams reads one set of generation settings; unset or invalid values stay empty
+func readParams(prefix string) llm.Params {
+	params := llm.Params{
+		MaxTokens:      getEnvInt(prefix+"_MAX_TOKENS", 0),
+		ThinkingBudget: getEnvInt(prefix+"_THINKING_BUDGET", 0),
+	}
+	if value, err := strconv.ParseFloat(os.Getenv(prefix+"_TEMPERATURE"), 64); err == nil {
+		params.Temperature = llm.Float(value)
+	}
+	if value, err := strconv.ParseFloat(os.Getenv(prefix+"_TOP_P"), 64); err == nil {
+		params.TopP = llm.Float(value)
+	}
+	if value, err := strconv.Atoi(os.Getenv(prefix + "_SEED")); err == nil {
+		params.Seed = llm.Int(value)
+	}
+	params.StopSequences = splitList(os.Getenv(prefix + "_STOP_SEQUENCES"))
+	return params
+}
+
+// splitList parses a comma-separated list, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseHeaders parses a comma-separated list of Name=Value pairs
+func parseHeaders(value string) map[string]string {
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			continue
+		}
+		headers[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	return headers
+}
+
+// EnvPrefix converts a provider name into its environment variable prefix
+// (e.g. "claude-sonnet" -> "CLAUDE_SONNET")
+func EnvPrefix(name string) string {
+	prefix := strings.ToUpper(name)
+	prefix = strings.ReplaceAll(prefix, "-", "_")
+	prefix = strings.ReplaceAll(prefix, ".", "_")
+	return prefix
 }
 
 func getEnv(key, defaultValue string) string {
@@ -100,3 +443,17 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value, err := strconv.ParseBool(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,267 @@
+package dryrun
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// streamChunkSize is the approximate size of each streamed text delta
+const streamChunkSize = 400
+
+// AITransport answers AI provider requests with deterministic fake personas.
+// It understands the Claude, Gemini and OpenAI-compatible wire formats,
+// including their streaming variants, so the real clients run unchanged.
+type AITransport struct {
+	logger *logrus.Logger
+}
+
+// NewAITransport creates a transport that never contacts an AI service
+func NewAITransport(logger *logrus.Logger) *AITransport {
+	return &AITransport{logger: logger}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *AITransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("dry run: failed to read request body: %w", err)
+		}
+	}
+
+	var payload map[string]interface{}
+	_ = json.Unmarshal(body, &payload)
+	prompt := strings.Join(collectStrings(payload, nil), "\n")
+	model, _ := payload["model"].(string)
+	path := req.URL.Path
+
+	t.logger.Debugf("Dry run: faking %s %s%s", req.Method, req.URL.Host, path)
+
+	switch {
+	case strings.HasSuffix(path, "/messages"):
+		text := FakePersona(personaNameFromPrompt(prompt), "claude")
+		if prefilled(payload) == "{" {
+			text = strings.TrimPrefix(FakeJSON(prompt), "{")
+		}
+		var thinking string
+		if _, ok := payload["thinking"]; ok {
+			thinking = fakeThinking(personaNameFromPrompt(prompt))
+		}
+		if stream, _ := payload["stream"].(bool); stream {
+			return sseResponse(req, claudeEvents(model, thinking, text, prompt)), nil
+		}
+		content := []map[string]string{{"type": "text", "text": text}}
+		if thinking != "" {
+			content = append([]map[string]string{{"type": "thinking", "thinking": thinking, "signature": "dryrun"}}, content...)
+		}
+		return jsonResponse(req, map[string]interface{}{
+			"id":          "msg_dryrun",
+			"type":        "message",
+			"role":        "assistant",
+			"model":       model,
+			"content":     content,
+			"stop_reason": "end_turn",
+			"usage":       map[string]int{"input_tokens": estimateTokens(prompt), "output_tokens": estimateTokens(text)},
+		}), nil
+
+	case strings.HasSuffix(path, ":streamGenerateContent"):
+		text := FakePersona(personaNameFromPrompt(prompt), "gemini")
+		return sseResponse(req, geminiEvents(text, prompt)), nil
+
+	case strings.HasSuffix(path, ":generateContent"):
+		text := FakePersona(personaNameFromPrompt(prompt), "gemini")
+		if config, _ := payload["generationConfig"].(map[string]interface{}); config["responseMimeType"] == "application/json" {
+			text = FakeJSON(prompt)
+		}
+		return jsonResponse(req, geminiChunk(text, "STOP", prompt)), nil
+
+	case strings.HasSuffix(path, "/chat/completions"):
+		text := FakePersona(personaNameFromPrompt(prompt), model)
+		if _, ok := payload["response_format"]; ok {
+			text = FakeJSON(prompt)
+		}
+		return jsonResponse(req, map[string]interface{}{
+			"id":    "chatcmpl-dryrun",
+			"model": model,
+			"choices": []map[string]interface{}{{
+				"index":         0,
+				"message":       map[string]string{"role": "assistant", "content": text},
+				"finish_reason": "stop",
+			}},
+			"usage": map[string]int{"prompt_tokens": estimateTokens(prompt), "completion_tokens": estimateTokens(text)},
+		}), nil
+
+	case req.Method == http.MethodGet && strings.HasSuffix(path, "/models"):
+		return jsonResponse(req, map[string]interface{}{"models": []interface{}{}, "data": []interface{}{}}), nil
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusNotFound,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"error":{"message":"dry run: unsupported endpoint"}}`)),
+		Request:    req,
+	}, nil
+}
+
+// claudeEvents renders thinking and text as a Claude messages stream
+func claudeEvents(model, thinking, text, prompt string) []string {
+	events := []string{sseEvent("message_start", map[string]interface{}{
+		"type":    "message_start",
+		"message": map[string]interface{}{"model": model, "usage": map[string]int{"input_tokens": estimateTokens(prompt)}},
+	})}
+	if thinking != "" {
+		events = append(events, sseEvent("content_block_start", map[string]interface{}{
+			"type":          "content_block_start",
+			"content_block": map[string]string{"type": "thinking"},
+		}))
+		for _, chunk := range splitChunks(thinking) {
+			events = append(events, sseEvent("content_block_delta", map[string]interface{}{
+				"type":  "content_block_delta",
+				"delta": map[string]string{"type": "thinking_delta", "thinking": chunk},
+			}))
+		}
+	}
+	for _, chunk := range splitChunks(text) {
+		events = append(events, sseEvent("content_block_delta", map[string]interface{}{
+			"type":  "content_block_delta",
+			"delta": map[string]string{"type": "text_delta", "text": chunk},
+		}))
+	}
+	events = append(events,
+		sseEvent("message_delta", map[string]interface{}{
+			"type":  "message_delta",
+			"delta": map[string]string{"stop_reason": "end_turn"},
+			"usage": map[string]int{"output_tokens": estimateTokens(text)},
+		}),
+		sseEvent("message_stop", map[string]string{"type": "message_stop"}),
+	)
+	return events
+}
+
+// geminiEvents renders text as a Gemini streamGenerateContent stream
+func geminiEvents(text, prompt string) []string {
+	chunks := splitChunks(text)
+	var events []string
+	for i, chunk := range chunks {
+		finish := ""
+		if i == len(chunks)-1 {
+			finish = "STOP"
+		}
+		events = append(events, sseEvent("", geminiChunk(chunk, finish, prompt)))
+	}
+	return events
+}
+
+// geminiChunk builds a generateContent response body
+func geminiChunk(text, finishReason, prompt string) map[string]interface{} {
+	candidate := map[string]interface{}{
+		"content": map[string]interface{}{"role": "model", "parts": []map[string]string{{"text": text}}},
+	}
+	if finishReason != "" {
+		candidate["finishReason"] = finishReason
+	}
+	return map[string]interface{}{
+		"candidates": []interface{}{candidate},
+		"usageMetadata": map[string]int{
+			"promptTokenCount":     estimateTokens(prompt),
+			"candidatesTokenCount": estimateTokens(text),
+		},
+	}
+}
+
+// sseEvent encodes one server-sent event
+func sseEvent(event string, data interface{}) string {
+	encoded, _ := json.Marshal(data)
+	if event == "" {
+		return fmt.Sprintf("data: %s\n\n", encoded)
+	}
+	return fmt.Sprintf("event: %s\ndata: %s\n\n", event, encoded)
+}
+
+func sseResponse(req *http.Request, events []string) *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"text/event-stream"}},
+		Body:       io.NopCloser(strings.NewReader(strings.Join(events, ""))),
+		Request:    req,
+	}
+}
+
+func jsonResponse(req *http.Request, v interface{}) *http.Response {
+	return statusResponse(req, http.StatusOK, v)
+}
+
+func statusResponse(req *http.Request, status int, v interface{}) *http.Response {
+	encoded, _ := json.Marshal(v)
+	return &http.Response{
+		StatusCode:    status,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(encoded)),
+		ContentLength: int64(len(encoded)),
+		Request:       req,
+	}
+}
+
+// splitChunks cuts text into line-aligned pieces of roughly streamChunkSize bytes
+func splitChunks(text string) []string {
+	var chunks []string
+	for len(text) > streamChunkSize {
+		cut := strings.IndexByte(text[streamChunkSize:], '\n')
+		if cut < 0 {
+			break
+		}
+		cut += streamChunkSize + 1
+		chunks = append(chunks, text[:cut])
+		text = text[cut:]
+	}
+	if text != "" {
+		chunks = append(chunks, text)
+	}
+	return chunks
+}
+
+// prefilled returns the content of a trailing assistant message, which
+// Claude continues rather than answers
+func prefilled(payload map[string]interface{}) string {
+	messages, _ := payload["messages"].([]interface{})
+	if len(messages) == 0 {
+		return ""
+	}
+	last, _ := messages[len(messages)-1].(map[string]interface{})
+	if last["role"] != "assistant" {
+		return ""
+	}
+	content, _ := last["content"].(string)
+	return content
+}
+
+// collectStrings gathers every string value in a decoded JSON document
+func collectStrings(v interface{}, out []string) []string {
+	switch val := v.(type) {
+	case string:
+		out = append(out, val)
+	case []interface{}:
+		for _, item := range val {
+			out = collectStrings(item, out)
+		}
+	case map[string]interface{}:
+		for _, item := range val {
+			out = collectStrings(item, out)
+		}
+	}
+	return out
+}
+
+// estimateTokens approximates a token count at four bytes per token
+func estimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
@@ -0,0 +1,528 @@
+package dryrun
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// fakeSHA is the commit every dry-run branch starts from
+const fakeSHA = "0000000000000000000000000000000000000000"
+
+// firstPRNumber keeps dry-run pull requests clear of real issue numbers
+const firstPRNumber = 9000
+
+// GitHubOptions configures a GitHubTransport
+type GitHubOptions struct {
+	// Passthrough sends reads the recorder cannot answer to the real API
+	Passthrough bool
+	// Label is put on the built-in sample issue
+	Label string
+	// FixturesDir holds optional issues.json and contents/<path> files used
+	// instead of the built-in samples when reads are not passed through
+	FixturesDir string
+	// SampleFiles are served as the default branch contents when reads are
+	// not passed through and no fixtures directory is set
+	SampleFiles map[string]string
+	// OutputDir, when set, receives a copy of every file the run would
+	// commit, laid out as <owner>/<repo>/<branch>/<path>
+	OutputDir string
+}
+
+// Action is one write the pipeline would have made on GitHub
+type Action struct {
+	Kind   string // branch, file, pull, comment or label
+	Repo   string
+	Target string // branch name, file path, or issue/PR number
+	Branch string
+	Detail string
+	Body   string
+}
+
+// GitHubTransport records every GitHub write instead of sending it and
+// answers reads from what it has recorded, the fixtures, or the real API
+type GitHubTransport struct {
+	opts   GitHubOptions
+	next   http.RoundTripper
+	logger *logrus.Logger
+
+	mu       sync.Mutex
+	actions  []Action
+	issues   []map[string]interface{}
+	files    map[string]string            // default branch contents when offline
+	branches map[string]map[string]string // dry-run branch -> path -> content
+	pulls    map[int]map[string]interface{}
+	nextPR   int
+	nextID   int64
+}
+
+// NewGitHubTransport creates a recording transport
+func NewGitHubTransport(opts GitHubOptions, logger *logrus.Logger) (*GitHubTransport, error) {
+	t := &GitHubTransport{
+		opts:     opts,
+		next:     http.DefaultTransport,
+		logger:   logger,
+		files:    make(map[string]string),
+		branches: make(map[string]map[string]string),
+		pulls:    make(map[int]map[string]interface{}),
+		nextPR:   firstPRNumber,
+		nextID:   1,
+	}
+
+	if opts.FixturesDir != "" {
+		if err := t.loadFixtures(opts.FixturesDir); err != nil {
+			return nil, err
+		}
+	} else {
+		for p, content := range opts.SampleFiles {
+			t.files[p] = content
+		}
+		t.issues = []map[string]interface{}{sampleIssue(opts.Label)}
+	}
+
+	return t, nil
+}
+
+// Passthrough reports whether unanswered reads go to the real GitHub API
+func (t *GitHubTransport) Passthrough() bool {
+	return t.opts.Passthrough
+}
+
+// Actions returns the recorded writes in the order they were made
+func (t *GitHubTransport) Actions() []Action {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Action(nil), t.actions...)
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *GitHubTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	repo, rest := splitRepoPath(req.URL.Path)
+
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return t.write(req, repo, rest)
+	}
+
+	resp, req := t.readRecorded(req, rest)
+	if resp != nil {
+		return resp, nil
+	}
+	if t.opts.Passthrough {
+		return t.passthrough(req)
+	}
+	return t.readOffline(req, repo, rest), nil
+}
+
+// write records a mutating request and returns a plausible response
+func (t *GitHubTransport) write(req *http.Request, repo, rest string) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("dry run: failed to read request body: %w", err)
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	parts := strings.Split(rest, "/")
+	switch {
+	case req.Method == http.MethodPost && rest == "git/refs":
+		var ref struct {
+			Ref string `json:"ref"`
+			SHA string `json:"sha"`
+		}
+		_ = json.Unmarshal(body, &ref)
+		branch := strings.TrimPrefix(ref.Ref, "refs/heads/")
+		if _, ok := t.branches[branch]; !ok {
+			t.branches[branch] = make(map[string]string)
+		}
+		t.record(Action{Kind: "branch", Repo: repo, Target: branch})
+		return statusResponse(req, http.StatusCreated, refJSON(branch)), nil
+
+	case req.Method == http.MethodPut && len(parts) > 1 && parts[0] == "contents":
+		var file struct {
+			Message string `json:"message"`
+			Content string `json:"content"`
+			Branch  string `json:"branch"`
+			SHA     string `json:"sha"`
+		}
+		_ = json.Unmarshal(body, &file)
+		content, _ := base64.StdEncoding.DecodeString(file.Content)
+		filePath := strings.Join(parts[1:], "/")
+		if t.branches[file.Branch] == nil {
+			t.branches[file.Branch] = make(map[string]string)
+		}
+		t.branches[file.Branch][filePath] = string(content)
+
+		verb := "create"
+		if file.SHA != "" {
+			verb = "update"
+		}
+		t.record(Action{Kind: "file", Repo: repo, Target: filePath, Branch: file.Branch, Detail: verb, Body: string(content)})
+		t.writeOutput(repo, file.Branch, filePath, content)
+
+		status := http.StatusCreated
+		if verb == "update" {
+			status = http.StatusOK
+		}
+		return statusResponse(req, status, map[string]interface{}{
+			"content": contentJSON(filePath, string(content)),
+			"commit":  map[string]string{"sha": fakeSHA, "message": file.Message},
+		}), nil
+
+	case req.Method == http.MethodPost && rest == "pulls":
+		var pr struct {
+			Title string `json:"title"`
+			Head  string `json:"head"`
+			Base  string `json:"base"`
+			Body  string `json:"body"`
+		}
+		_ = json.Unmarshal(body, &pr)
+		number := t.nextPR
+		t.nextPR++
+		pull := pullJSON(repo, number, pr.Title, pr.Head, pr.Base, pr.Body)
+		t.pulls[number] = pull
+		t.record(Action{Kind: "pull", Repo: repo, Target: "#" + strconv.Itoa(number), Branch: pr.Head,
+			Detail: fmt.Sprintf("%q %s -> %s", pr.Title, pr.Head, pr.Base), Body: pr.Body})
+		return statusResponse(req, http.StatusCreated, pull), nil
+
+	case req.Method == http.MethodPost && len(parts) == 3 && parts[0] == "issues" && parts[2] == "comments":
+		var comment struct {
+			Body string `json:"body"`
+		}
+		_ = json.Unmarshal(body, &comment)
+		id := t.nextID
+		t.nextID++
+		t.record(Action{Kind: "comment", Repo: repo, Target: "#" + parts[1], Body: comment.Body})
+		return statusResponse(req, http.StatusCreated, map[string]interface{}{
+			"id":         id,
+			"body":       comment.Body,
+			"created_at": time.Now().UTC().Format(time.RFC3339),
+		}), nil
+
+	case req.Method == http.MethodPost && len(parts) == 3 && parts[0] == "issues" && parts[2] == "labels":
+		var labels []string
+		_ = json.Unmarshal(body, &labels)
+		t.record(Action{Kind: "label", Repo: repo, Target: "#" + parts[1], Detail: "+ " + strings.Join(labels, ", ")})
+		var out []map[string]string
+		for _, label := range labels {
+			out = append(out, map[string]string{"name": label})
+		}
+		return statusResponse(req, http.StatusOK, out), nil
+
+	case req.Method == http.MethodDelete && len(parts) == 4 && parts[0] == "issues" && parts[2] == "labels":
+		t.record(Action{Kind: "label", Repo: repo, Target: "#" + parts[1], Detail: "- " + parts[3]})
+		return statusResponse(req, http.StatusOK, []interface{}{}), nil
+	}
+
+	t.record(Action{Kind: "other", Repo: repo, Target: req.Method + " " + req.URL.Path, Body: string(body)})
+	return statusResponse(req, http.StatusOK, map[string]interface{}{}), nil
+}
+
+// readRecorded answers reads about branches, files and pull requests the
+// dry run itself created. For anything else it returns a nil response and
+// the request to forward, rewritten to read dry-run branches from the
+// default branch they were cut from.
+func (t *GitHubTransport) readRecorded(req *http.Request, rest string) (*http.Response, *http.Request) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	parts := strings.Split(rest, "/")
+	switch {
+	case strings.HasPrefix(rest, "git/ref/heads/"):
+		branch := strings.TrimPrefix(rest, "git/ref/heads/")
+		if _, ok := t.branches[branch]; ok {
+			return jsonResponse(req, refJSON(branch)), req
+		}
+
+	case len(parts) > 1 && parts[0] == "contents":
+		files, ok := t.branches[req.URL.Query().Get("ref")]
+		if !ok {
+			return nil, req
+		}
+		filePath := strings.Join(parts[1:], "/")
+		if content, found := files[filePath]; found {
+			return jsonResponse(req, contentJSON(filePath, content)), req
+		}
+		forward := req.Clone(req.Context())
+		query := forward.URL.Query()
+		query.Del("ref")
+		forward.URL.RawQuery = query.Encode()
+		return nil, forward
+
+	case len(parts) == 2 && parts[0] == "pulls":
+		number, _ := strconv.Atoi(parts[1])
+		if pull, ok := t.pulls[number]; ok {
+			return jsonResponse(req, pull), req
+		}
+	}
+	return nil, req
+}
+
+// readOffline answers reads from the fixtures and built-in samples
+func (t *GitHubTransport) readOffline(req *http.Request, repo, rest string) *http.Response {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	parts := strings.Split(rest, "/")
+	switch {
+	case strings.HasPrefix(req.URL.Path, "/search/"):
+		return jsonResponse(req, map[string]interface{}{"total_count": 0, "incomplete_results": false, "items": []interface{}{}})
+
+	case rest == "":
+		name := path.Base(repo)
+		return jsonResponse(req, map[string]interface{}{"name": name, "full_name": repo, "default_branch": "main"})
+
+	case strings.HasPrefix(rest, "git/ref/heads/"):
+		return jsonResponse(req, refJSON(strings.TrimPrefix(rest, "git/ref/heads/")))
+
+	case len(parts) > 1 && parts[0] == "contents":
+		filePath := strings.Join(parts[1:], "/")
+		if content, ok := t.files[filePath]; ok {
+			return jsonResponse(req, contentJSON(filePath, content))
+		}
+		if entries := t.listDir(filePath); len(entries) > 0 {
+			return jsonResponse(req, entries)
+		}
+
+	case rest == "issues":
+		return jsonResponse(req, t.matchIssues(req.URL.Query().Get("labels")))
+
+	case len(parts) == 2 && parts[0] == "issues":
+		for _, issue := range t.issues {
+			if fmt.Sprint(issue["number"]) == parts[1] {
+				return jsonResponse(req, issue)
+			}
+		}
+
+	case rest == "pulls":
+		var pulls []map[string]interface{}
+		for _, number := range sortedKeys(t.pulls) {
+			pulls = append(pulls, t.pulls[number])
+		}
+		if pulls == nil {
+			pulls = []map[string]interface{}{}
+		}
+		return jsonResponse(req, pulls)
+
+	case len(parts) == 3 && parts[2] == "comments", rest == "commits":
+		return jsonResponse(req, []interface{}{})
+	}
+
+	return statusResponse(req, http.StatusNotFound, map[string]string{"message": "Not Found"})
+}
+
+// passthrough sends a read to the real GitHub API
+func (t *GitHubTransport) passthrough(req *http.Request) (*http.Response, error) {
+	return t.next.RoundTrip(req)
+}
+
+// record appends an action; callers hold t.mu
+func (t *GitHubTransport) record(action Action) {
+	t.actions = append(t.actions, action)
+	target := action.Target
+	if action.Branch != "" && action.Kind == "file" {
+		target += " @ " + action.Branch
+	}
+	t.logger.Infof("Dry run: would %s %s in %s %s", actionVerb(action), action.Kind, action.Repo, target)
+}
+
+// writeOutput mirrors a recorded file into the output directory, if any
+func (t *GitHubTransport) writeOutput(repo, branch, filePath string, content []byte) {
+	if t.opts.OutputDir == "" {
+		return
+	}
+	target := filepath.Join(t.opts.OutputDir, filepath.FromSlash(repo), filepath.FromSlash(branch), filepath.FromSlash(filePath))
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		t.logger.Warnf("Dry run: failed to create output directory: %v", err)
+		return
+	}
+	if err := os.WriteFile(target, content, 0644); err != nil {
+		t.logger.Warnf("Dry run: failed to write %s: %v", target, err)
+	}
+}
+
+// listDir lists the immediate children of dir in the offline contents
+func (t *GitHubTransport) listDir(dir string) []map[string]interface{} {
+	prefix := strings.TrimSuffix(dir, "/") + "/"
+	seen := make(map[string]string)
+	for p := range t.files {
+		if !strings.HasPrefix(p, prefix) {
+			continue
+		}
+		name := strings.TrimPrefix(p, prefix)
+		kind := "file"
+		if i := strings.Index(name, "/"); i >= 0 {
+			name, kind = name[:i], "dir"
+		}
+		seen[name] = kind
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var entries []map[string]interface{}
+	for _, name := range names {
+		entries = append(entries, map[string]interface{}{"type": seen[name], "name": name, "path": prefix + name, "sha": fakeSHA})
+	}
+	return entries
+}
+
+// matchIssues returns the issues carrying every label in the comma-separated list
+func (t *GitHubTransport) matchIssues(labels string) []map[string]interface{} {
+	matched := []map[string]interface{}{}
+	for _, issue := range t.issues {
+		have := make(map[string]bool)
+		if list, ok := issue["labels"].([]interface{}); ok {
+			for _, l := range list {
+				if label, ok := l.(map[string]interface{}); ok {
+					have[fmt.Sprint(label["name"])] = true
+				}
+			}
+		}
+		ok := true
+		for _, want := range strings.Split(labels, ",") {
+			if want != "" && !have[want] {
+				ok = false
+			}
+		}
+		if ok {
+			matched = append(matched, issue)
+		}
+	}
+	return matched
+}
+
+// loadFixtures reads issues.json and the contents/ tree from dir
+func (t *GitHubTransport) loadFixtures(dir string) error {
+	data, err := os.ReadFile(filepath.Join(dir, "issues.json"))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read issues fixture: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &t.issues); err != nil {
+			return fmt.Errorf("failed to parse issues fixture: %w", err)
+		}
+	}
+
+	contentsDir := filepath.Join(dir, "contents")
+	err = filepath.Walk(contentsDir, func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(contentsDir, p)
+		if err != nil {
+			return err
+		}
+		t.files[filepath.ToSlash(rel)] = string(content)
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read contents fixtures: %w", err)
+	}
+	return nil
+}
+
+// sampleIssue is the persona request served when no fixtures are configured
+func sampleIssue(label string) map[string]interface{} {
+	return map[string]interface{}{
+		"number":     1,
+		"title":      "Create Persona: " + SampleIssueName,
+		"state":      "open",
+		"body":       "**Full Name:** " + SampleIssueName + "\n\n<<<\n**Background & Context:**\nEnglish mathematician and writer, known for her work on Charles Babbage's Analytical Engine.\n>>>\n",
+		"labels":     []interface{}{map[string]interface{}{"name": label}},
+		"html_url":   "https://github.com/dry-run/issues/1",
+		"created_at": "2024-01-01T00:00:00Z",
+	}
+}
+
+// splitRepoPath splits /repos/{owner}/{repo}/rest into "owner/repo" and rest
+func splitRepoPath(p string) (string, string) {
+	parts := strings.SplitN(strings.TrimPrefix(p, "/api/v3"), "/", 5)
+	if len(parts) < 4 || parts[1] != "repos" {
+		return "", strings.TrimPrefix(p, "/")
+	}
+	rest := ""
+	if len(parts) == 5 {
+		rest = parts[4]
+	}
+	return parts[2] + "/" + parts[3], rest
+}
+
+func refJSON(branch string) map[string]interface{} {
+	return map[string]interface{}{
+		"ref":    "refs/heads/" + branch,
+		"object": map[string]string{"sha": fakeSHA, "type": "commit"},
+	}
+}
+
+func contentJSON(filePath, content string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":     "file",
+		"encoding": "base64",
+		"name":     path.Base(filePath),
+		"path":     filePath,
+		"sha":      fakeSHA,
+		"size":     len(content),
+		"content":  base64.StdEncoding.EncodeToString([]byte(content)),
+	}
+}
+
+func pullJSON(repo string, number int, title, head, base, body string) map[string]interface{} {
+	return map[string]interface{}{
+		"number":   number,
+		"state":    "open",
+		"title":    title,
+		"body":     body,
+		"html_url": fmt.Sprintf("https://github.com/%s/pull/%d", repo, number),
+		"head":     map[string]string{"ref": head, "sha": fakeSHA},
+		"base":     map[string]string{"ref": base, "sha": fakeSHA},
+	}
+}
+
+func sortedKeys(m map[int]map[string]interface{}) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+func actionVerb(action Action) string {
+	switch action.Kind {
+	case "file":
+		return action.Detail
+	case "label":
+		if strings.HasPrefix(action.Detail, "-") {
+			return "remove"
+		}
+		return "add"
+	case "other":
+		return "send"
+	}
+	return "create"
+}
@@ -0,0 +1,284 @@
+package dryrun
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/twin2ai/studio/pkg/models"
+	"github.com/twin2ai/studio/pkg/schema"
+)
+
+const (
+	// SampleIssueName is the persona requested by the built-in sample issue
+	SampleIssueName = "Ada Lovelace"
+	// SamplePersonaName is the persona already present in the built-in sample files
+	SamplePersonaName = "Grace Hopper"
+)
+
+var (
+	titlePattern   = regexp.MustCompile(`(?m)^Title:\s*(.+?)\s*$`)
+	headingPattern = regexp.MustCompile(`(?m)^# (.+?) — Persona Profile`)
+	criteriaBlock  = regexp.MustCompile(`(?s)CRITERIA:\n(.*?)\n\n`)
+	criterionLine  = regexp.MustCompile(`(?m)^- ([a-z0-9_-]+):`)
+	sourcePattern  = regexp.MustCompile(`_Generated by the (\S+) dry-run provider`)
+	claimSource    = regexp.MustCompile(`(?m)^### (\S+)$`)
+	claimLine      = regexp.MustCompile(`^- \[([a-z]+)\] ([^:]+): (.+)$`)
+	subjectPattern = regexp.MustCompile(`(?m)^SUBJECT:\s*(.+?)\s*$`)
+	factSource     = regexp.MustCompile(`(?m)^SOURCE: (\S+)$`)
+)
+
+// Markers identifying the JSON-mode prompts that are not persona.json
+const (
+	judgeMarker         = `{"scores": [`        // Evaluation, which asks for scores
+	claimsMarker        = `{"claims": [`        // Claim extraction
+	disagreementsMarker = `{"disagreements": [` // Claim comparison
+	factsMarker         = `{"facts": [`         // Fact sheet extraction
+)
+
+// personaSections mirrors the section headings of templates/persona_template.md
+var personaSections = []string{
+	"0. Core Essence",
+	"1. Biographical Foundation and Personality",
+	"2. Voice/Communication Analysis",
+	"3. Signature Language Patterns",
+	"4. Narrative/Communication Structure",
+	"5. Subject Matter Expertise",
+	"6. Philosophical Framework",
+	"7. Emotional Range and Expression",
+	"8. Distinctive Patterns and Quirks",
+	"9. Evolution Over Time",
+	"10. Practical Application Guidelines",
+	"10.5. Platform Adaptation Bank",
+}
+
+// FakePersona returns a deterministic persona document for name as written by source
+func FakePersona(name, source string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s — Persona Profile\n\n", name)
+	fmt.Fprintf(&b, "_Generated by the %s dry-run provider; no AI service was called._\n\n", source)
+	for _, section := range personaSections {
+		fmt.Fprintf(&b, "## %s\n\n", section)
+		fmt.Fprintf(&b, "- %s: placeholder notes on %s for %s.\n", source, strings.ToLower(sectionTitle(section)), name)
+		fmt.Fprintf(&b, "- Example: \"%s would say something characteristic here.\"\n\n", name)
+	}
+	return b.String()
+}
+
+// FakeProfile returns a deterministic persona.json document for name that
+// matches the persona schema
+func FakeProfile(name string) string {
+	profile := models.Profile{
+		SchemaVersion: schema.PersonaVersion,
+		Identity: models.Identity{
+			Name:    name,
+			Summary: fmt.Sprintf("Dry-run profile of %s; no AI service was called.", name),
+		},
+		Background: models.Background{Summary: fmt.Sprintf("Placeholder background for %s.", name)},
+		PersonalityTraits: []models.Trait{
+			{Trait: "Curious", Description: fmt.Sprintf("Placeholder trait for %s.", name)},
+		},
+		SpeechStyle:   models.SpeechStyle{Tone: "Placeholder tone", SampleQuotes: []string{fmt.Sprintf("%s would say something characteristic here.", name)}},
+		Values:        []models.Value{{Value: "Placeholder value"}},
+		Relationships: []models.Relationship{},
+		KeyFacts:      []models.KeyFact{{Fact: fmt.Sprintf("Placeholder fact about %s.", name)}},
+	}
+	encoded, _ := json.MarshalIndent(profile, "", "  ")
+	return string(encoded)
+}
+
+// fakeThinking returns deterministic reasoning notes for name
+func fakeThinking(name string) string {
+	return fmt.Sprintf("The request is for a persona of %s. Dry-run mode: no reasoning was produced, so this placeholder stands in for the extended thinking blocks.", name)
+}
+
+// SamplePersonaFiles returns raw outputs for the sample persona, keyed by
+// repository path, so synthesis can run without a personas repository
+func SamplePersonaFiles(providers []string) map[string]string {
+	folder := strings.ToLower(strings.ReplaceAll(SamplePersonaName, " ", "_"))
+	files := make(map[string]string)
+	for _, name := range providers {
+		files[fmt.Sprintf("personas/%s/raw/%s.md", folder, name)] = FakePersona(SamplePersonaName, name)
+	}
+	synthesized := FakePersona(SamplePersonaName, "synthesis")
+	files[fmt.Sprintf("personas/%s/synthesized.md", folder)] = synthesized
+
+	// One recorded version, so history has something to show
+	first := models.Version{Number: 1, Date: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Trigger: "issue #1",
+		Provenance: models.Provenance{Synthesizer: "synthesis", Method: "synthesized"}, Path: models.SnapshotPath(1)}
+	if doc, err := models.Parse(synthesized); err == nil {
+		first.Summary = models.ChangeSummary(nil, doc)
+	}
+	for _, name := range providers {
+		first.Provenance.Providers = append(first.Provenance.Providers, models.ProviderOutput{Provider: name})
+	}
+	files[fmt.Sprintf("personas/%s/%s", folder, first.Path)] = synthesized
+	files[fmt.Sprintf("personas/%s/%s", folder, models.ChangelogFile)] = models.RenderChangelog(SamplePersonaName, []models.Version{first})
+	return files
+}
+
+// personaNameFromPrompt finds the persona a prompt is about
+func personaNameFromPrompt(prompt string) string {
+	if m := titlePattern.FindStringSubmatch(prompt); m != nil {
+		return m[1]
+	}
+	if m := headingPattern.FindStringSubmatch(prompt); m != nil {
+		return m[1]
+	}
+	return "Dry Run Persona"
+}
+
+// FakeJSON answers a JSON-mode request: scores for an evaluation prompt,
+// claims, disagreements or facts for an analysis prompt, persona.json
+// otherwise
+func FakeJSON(prompt string) string {
+	switch {
+	case strings.Contains(prompt, judgeMarker):
+		return FakeScores(prompt)
+	case strings.Contains(prompt, disagreementsMarker):
+		return FakeDisagreements(prompt)
+	case strings.Contains(prompt, claimsMarker):
+		return FakeClaims(prompt)
+	case strings.Contains(prompt, factsMarker):
+		return FakeFacts(prompt)
+	}
+	return FakeProfile(personaNameFromPrompt(prompt))
+}
+
+// FakeScores returns deterministic judge scores between 5 and 9 for the
+// criteria listed in an evaluation prompt
+func FakeScores(prompt string) string {
+	type score struct {
+		Criterion string `json:"criterion"`
+		Score     int    `json:"score"`
+		Reason    string `json:"reason"`
+	}
+	var scores []score
+	if block := criteriaBlock.FindStringSubmatch(prompt); block != nil {
+		for _, m := range criterionLine.FindAllStringSubmatch(block[1], -1) {
+			h := fnv.New32a()
+			h.Write([]byte(m[1] + prompt))
+			scores = append(scores, score{Criterion: m[1], Score: 5 + int(h.Sum32()%5), Reason: "Dry-run score; no AI service was called."})
+		}
+	}
+	encoded, _ := json.Marshal(map[string]interface{}{"scores": scores})
+	return string(encoded)
+}
+
+// FakeClaims returns deterministic claims for the profile in an extraction
+// prompt. The birth year depends on the provider that wrote the profile, so
+// the dry run reports a disagreement.
+func FakeClaims(prompt string) string {
+	source := "synthesis"
+	if m := sourcePattern.FindStringSubmatch(prompt); m != nil {
+		source = m[1]
+	}
+	h := fnv.New32a()
+	h.Write([]byte(source))
+	name := personaNameFromPrompt(prompt)
+	if m := subjectPattern.FindStringSubmatch(prompt); m != nil {
+		name = m[1]
+	}
+
+	claims := []map[string]string{
+		{"topic": "Birth year", "category": "fact", "claim": fmt.Sprintf("%d", 1815+int(h.Sum32()%2))},
+		{"topic": "Primary role", "category": "role", "claim": fmt.Sprintf("Placeholder role for %s", name)},
+	}
+	encoded, _ := json.Marshal(map[string]interface{}{"claims": claims})
+	return string(encoded)
+}
+
+// FakeDisagreements compares the claims listed in a comparison prompt and
+// reports every topic whose sources give different claims
+func FakeDisagreements(prompt string) string {
+	type position struct {
+		Claim   string   `json:"claim"`
+		Sources []string `json:"sources"`
+	}
+	type disagreement struct {
+		Topic     string     `json:"topic"`
+		Category  string     `json:"category"`
+		Positions []position `json:"positions"`
+		Verify    string     `json:"verify"`
+	}
+
+	var topics []string
+	categories := make(map[string]string)
+	positions := make(map[string][]position)
+	source := ""
+	for _, line := range strings.Split(prompt, "\n") {
+		if m := claimSource.FindStringSubmatch(line); m != nil {
+			source = m[1]
+			continue
+		}
+		m := claimLine.FindStringSubmatch(line)
+		if m == nil || source == "" {
+			continue
+		}
+		topic, claim := m[2], m[3]
+		if _, ok := positions[topic]; !ok {
+			topics = append(topics, topic)
+			categories[topic] = m[1]
+		}
+		held := false
+		for i := range positions[topic] {
+			if positions[topic][i].Claim == claim {
+				positions[topic][i].Sources = append(positions[topic][i].Sources, source)
+				held = true
+			}
+		}
+		if !held {
+			positions[topic] = append(positions[topic], position{Claim: claim, Sources: []string{source}})
+		}
+	}
+
+	disagreements := []disagreement{}
+	for _, topic := range topics {
+		if len(positions[topic]) > 1 {
+			disagreements = append(disagreements, disagreement{Topic: topic, Category: categories[topic], Positions: positions[topic],
+				Verify: "Dry-run disagreement; no AI service was called."})
+		}
+	}
+	encoded, _ := json.Marshal(map[string]interface{}{"disagreements": disagreements})
+	return string(encoded)
+}
+
+// FakeFacts returns a deterministic fact sheet for the sources in an
+// extraction prompt: one fact stated by all of them, one by all but the
+// last and one by the first alone
+func FakeFacts(prompt string) string {
+	var sources []string
+	for _, m := range factSource.FindAllStringSubmatch(prompt, -1) {
+		sources = append(sources, m[1])
+	}
+	if len(sources) == 0 {
+		sources = []string{"dryrun"}
+	}
+	name := "Dry Run Persona"
+	if m := subjectPattern.FindStringSubmatch(prompt); m != nil {
+		name = m[1]
+	}
+
+	most := sources
+	if len(sources) > 2 {
+		most = sources[:len(sources)-1]
+	}
+	facts := []map[string]interface{}{
+		{"statement": fmt.Sprintf("%s was born in 1815", name), "category": "biography", "sources": sources},
+		{"statement": fmt.Sprintf("%s is known for placeholder work", name), "category": "work", "sources": most},
+		{"statement": fmt.Sprintf("%s kept a placeholder diary", name), "category": "other", "sources": sources[:1]},
+	}
+	encoded, _ := json.Marshal(map[string]interface{}{"facts": facts})
+	return string(encoded)
+}
+
+// sectionTitle strips the leading number from a section heading
+func sectionTitle(section string) string {
+	if i := strings.Index(section, ". "); i >= 0 {
+		return section[i+2:]
+	}
+	return section
+}
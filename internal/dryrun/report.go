@@ -0,0 +1,74 @@
+package dryrun
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// reportSections lists action kinds in the order the report shows them
+var reportSections = []struct {
+	kind  string
+	title string
+}{
+	{"branch", "Branches"},
+	{"file", "Files"},
+	{"pull", "Pull requests"},
+	{"comment", "Comments"},
+	{"label", "Labels"},
+	{"other", "Other requests"},
+}
+
+// WriteReport prints every GitHub write recorded during the dry run
+func (t *GitHubTransport) WriteReport(w io.Writer) {
+	actions := t.Actions()
+	fmt.Fprintf(w, "\nDry run complete: %d GitHub writes recorded, none sent\n", len(actions))
+
+	for _, section := range reportSections {
+		var matching []Action
+		for _, action := range actions {
+			if action.Kind == section.kind {
+				matching = append(matching, action)
+			}
+		}
+		if len(matching) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(w, "\n%s (%d)\n", section.title, len(matching))
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, action := range matching {
+			fmt.Fprintf(tw, "  %s\t%s\t%s\n", action.Repo, action.Target, describe(action))
+		}
+		tw.Flush()
+	}
+}
+
+// describe summarises an action for the report
+func describe(action Action) string {
+	switch action.Kind {
+	case "file":
+		return fmt.Sprintf("%s on %s (%d bytes)", action.Detail, action.Branch, len(action.Body))
+	case "comment":
+		return fmt.Sprintf("%q (%d chars)", firstLine(action.Body), len(action.Body))
+	case "other":
+		return fmt.Sprintf("%d byte body", len(action.Body))
+	}
+	return action.Detail
+}
+
+// firstLine returns the first non-empty line of s, shortened for display
+func firstLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if runes := []rune(line); len(runes) > 60 {
+			line = string(runes[:57]) + "..."
+		}
+		return line
+	}
+	return ""
+}
@@ -0,0 +1,180 @@
+package evaluation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/twin2ai/studio/internal/llm"
+	"github.com/twin2ai/studio/internal/provider"
+)
+
+// SynthesisOutput names the synthesized persona among the scored outputs
+const SynthesisOutput = "synthesis"
+
+// judgeAttempts caps the requests made to score one output, including the
+// one correcting an invalid reply
+const judgeAttempts = 2
+
+// Output is a persona document to score
+type Output struct {
+	Name    string // Provider name, or SynthesisOutput
+	Model   string
+	Content string
+}
+
+// Judge scores personas against a rubric with the judge providers
+type Judge struct {
+	chain  *provider.SynthesisChain
+	rubric Rubric
+	logger *logrus.Logger
+}
+
+// NewJudge creates a judge that runs on chain, falling back along it
+func NewJudge(chain *provider.SynthesisChain, rubric Rubric, logger *logrus.Logger) *Judge {
+	return &Judge{chain: chain, rubric: rubric, logger: logger}
+}
+
+// Rubric returns the criteria the judge scores
+func (j *Judge) Rubric() Rubric {
+	return j.rubric
+}
+
+// Evaluate scores the raw outputs and the synthesis of a persona about
+// subject, in parallel. Outputs that cannot be scored are reported with
+// their error and left out of the comparison.
+func (j *Judge) Evaluate(ctx context.Context, subject string, raw []Output, synthesis Output) *Report {
+	outputs := append(append([]Output{}, raw...), synthesis)
+	report := &Report{Rubric: j.rubric, Outputs: make([]Evaluation, len(outputs))}
+
+	var wg sync.WaitGroup
+	judges := make([]string, len(outputs))
+	for i, output := range outputs {
+		wg.Add(1)
+		go func(i int, output Output) {
+			defer wg.Done()
+			evaluation, judge, err := j.score(ctx, subject, output)
+			if err != nil {
+				j.logger.Warnf("Failed to evaluate %s: %v", output.Name, err)
+				evaluation = &Evaluation{Output: output.Name, Model: output.Model, Error: err.Error()}
+			}
+			report.Outputs[i] = *evaluation
+			judges[i] = judge
+		}(i, output)
+	}
+	wg.Wait()
+
+	for _, judge := range judges {
+		if judge != "" {
+			report.Judge = judge
+			break
+		}
+	}
+	report.compare()
+	return report
+}
+
+// score asks the judge for one output's scores, sending validation errors
+// back once so the judge can correct its reply
+func (j *Judge) score(ctx context.Context, subject string, output Output) (*Evaluation, string, error) {
+	req := llm.Request{System: j.systemPrompt(), Schema: j.schema()}.
+		User(fmt.Sprintf("SUBJECT: %s\n\nPROFILE:\n<<<\n%s\n>>>", subject, output.Content))
+
+	var reply struct {
+		Scores []Score `json:"scores"`
+	}
+	var scores []Score
+	result, err := j.chain.CompleteJSON(ctx, req, llm.StageEvaluation, judgeAttempts, &reply, func() (err error) {
+		scores, err = j.checkScores(reply.Scores)
+		return err
+	})
+	if result == nil {
+		return nil, "", err
+	}
+	judge := fmt.Sprintf("%s (%s)", result.Provider, result.Model)
+	if err != nil {
+		return nil, judge, err
+	}
+	return &Evaluation{Output: output.Name, Model: output.Model, Scores: scores, Overall: overall(scores)}, judge, nil
+}
+
+// checkScores puts a judge's scores in rubric order, checking that every
+// criterion has one
+func (j *Judge) checkScores(reply []Score) ([]Score, error) {
+	byName := make(map[string]Score)
+	for _, score := range reply {
+		byName[strings.ToLower(score.Criterion)] = score
+	}
+	scores := make([]Score, 0, len(j.rubric))
+	var missing []string
+	for _, criterion := range j.rubric {
+		score, ok := byName[criterion.Name]
+		if !ok {
+			missing = append(missing, criterion.Name)
+			continue
+		}
+		score.Criterion = criterion.Name
+		scores = append(scores, score)
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("no score for %s", strings.Join(missing, ", "))
+	}
+	return scores, nil
+}
+
+// systemPrompt explains the rubric and the reply format to the judge
+func (j *Judge) systemPrompt() string {
+	var criteria strings.Builder
+	for _, c := range j.rubric {
+		fmt.Fprintf(&criteria, "- %s: %s\n", c.Name, c.Description)
+	}
+	return fmt.Sprintf(`You are a strict, impartial judge of persona profiles: documents describing a real person in enough detail for an AI to emulate them. Score the profile you are given on each criterion below from 1 (poor) to 10 (excellent). Judge the document against what is known about its subject; do not reward length for its own sake.
+
+CRITERIA:
+%s
+Reply with a single JSON object and nothing else, with one entry per criterion:
+{"scores": [{"criterion": "<name>", "score": <1-10>, "reason": "<one sentence>"}]}`, criteria.String())
+}
+
+// schema is the JSON Schema of the judge's reply for this rubric
+func (j *Judge) schema() json.RawMessage {
+	document := map[string]interface{}{
+		"type":                 "object",
+		"additionalProperties": false,
+		"required":             []string{"scores"},
+		"properties": map[string]interface{}{
+			"scores": map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type":                 "object",
+					"additionalProperties": false,
+					"required":             []string{"criterion", "score", "reason"},
+					"properties": map[string]interface{}{
+						"criterion": map[string]interface{}{"type": "string", "enum": j.rubric.Names()},
+						"score":     map[string]interface{}{"type": "integer", "enum": []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}},
+						"reason":    map[string]interface{}{"type": "string"},
+					},
+				},
+			},
+		},
+	}
+	encoded, _ := json.Marshal(document)
+	return encoded
+}
+
+// overall is the mean score, rounded to one decimal
+func overall(scores []Score) float64 {
+	if len(scores) == 0 {
+		return 0
+	}
+	total := 0
+	for _, s := range scores {
+		total += s.Score
+	}
+	return math.Round(float64(total)/float64(len(scores))*10) / 10
+}
@@ -0,0 +1,187 @@
+package evaluation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Metadata keys written to .assets_status.json
+const (
+	MetadataEvaluation = "evaluation"         // Overall score per output, e.g. "claude=7.6,gemini=7.2,synthesis=8.0"
+	MetadataJudge      = "evaluation_judge"   // Provider and model that scored
+	MetadataFlagged    = "evaluation_flagged" // "true" when the synthesis scored below the best raw output
+	metadataScores     = "evaluation_"        // Prefix of the per-criterion scores of one output
+)
+
+// Score is a judge's score for one criterion
+type Score struct {
+	Criterion string `json:"criterion"`
+	Score     int    `json:"score"`
+	Reason    string `json:"reason"`
+}
+
+// Evaluation is the judge's verdict on one output
+type Evaluation struct {
+	Output  string  `json:"output"`
+	Model   string  `json:"model,omitempty"`
+	Scores  []Score `json:"scores,omitempty"`
+	Overall float64 `json:"overall"`
+	Error   string  `json:"error,omitempty"`
+}
+
+// Scored reports whether the judge scored the output
+func (e Evaluation) Scored() bool {
+	return e.Error == "" && len(e.Scores) > 0
+}
+
+// Score returns the score for a criterion, or 0 when it was not scored
+func (e Evaluation) Score(criterion string) int {
+	for _, s := range e.Scores {
+		if s.Criterion == criterion {
+			return s.Score
+		}
+	}
+	return 0
+}
+
+// Report is the evaluation of a persona's raw outputs and synthesis. Best
+// is the highest scoring raw output; Flagged means the synthesis scored
+// below it.
+type Report struct {
+	Judge   string       `json:"judge"`
+	Rubric  Rubric       `json:"rubric"`
+	Outputs []Evaluation `json:"outputs"`
+	Best    string       `json:"best,omitempty"`
+	Flagged bool         `json:"flagged"`
+}
+
+// Synthesis returns the synthesis evaluation, or nil when there is none
+func (r *Report) Synthesis() *Evaluation {
+	for i := range r.Outputs {
+		if r.Outputs[i].Output == SynthesisOutput {
+			return &r.Outputs[i]
+		}
+	}
+	return nil
+}
+
+// compare finds the best raw output and flags a synthesis that scored
+// below it
+func (r *Report) compare() {
+	var best *Evaluation
+	for i := range r.Outputs {
+		e := &r.Outputs[i]
+		if e.Output != SynthesisOutput && e.Scored() && (best == nil || e.Overall > best.Overall) {
+			best = e
+		}
+	}
+	if best == nil {
+		return
+	}
+	r.Best = best.Output
+	if synthesis := r.Synthesis(); synthesis != nil && synthesis.Scored() {
+		r.Flagged = synthesis.Overall < best.Overall
+	}
+}
+
+// Summary returns a one-line result, e.g. "synthesis 8.2, best provider claude 7.9"
+func (r *Report) Summary() string {
+	synthesis := r.Synthesis()
+	if synthesis == nil || !synthesis.Scored() {
+		return "synthesis not scored"
+	}
+	summary := fmt.Sprintf("synthesis %.1f", synthesis.Overall)
+	for _, e := range r.Outputs {
+		if e.Output == r.Best {
+			summary += fmt.Sprintf(", best provider %s %.1f", e.Output, e.Overall)
+		}
+	}
+	return summary
+}
+
+// Markdown renders the scores as a table for a pull request body
+func (r *Report) Markdown() string {
+	var b strings.Builder
+	if r.Flagged {
+		fmt.Fprintf(&b, "⚠️ **The synthesis scored below %s's raw output.** Compare them before merging.\n\n", r.Best)
+	}
+
+	b.WriteString("| Output |")
+	for _, c := range r.Rubric {
+		fmt.Fprintf(&b, " %s |", strings.ToUpper(c.Name[:1])+c.Name[1:])
+	}
+	b.WriteString(" Overall |\n|--------|")
+	b.WriteString(strings.Repeat("---|", len(r.Rubric)+1))
+	b.WriteString("\n")
+
+	for _, e := range r.Outputs {
+		name := e.Output
+		if e.Model != "" {
+			name = fmt.Sprintf("%s (%s)", e.Output, e.Model)
+		}
+		if e.Output == SynthesisOutput {
+			name = "**" + name + "**"
+		}
+		fmt.Fprintf(&b, "| %s |", name)
+		if !e.Scored() {
+			b.WriteString(strings.Repeat(" – |", len(r.Rubric)))
+			b.WriteString(" not scored |\n")
+			continue
+		}
+		for _, c := range r.Rubric {
+			fmt.Fprintf(&b, " %d |", e.Score(c.Name))
+		}
+		if e.Output == SynthesisOutput {
+			fmt.Fprintf(&b, " **%.1f** |\n", e.Overall)
+		} else {
+			fmt.Fprintf(&b, " %.1f |\n", e.Overall)
+		}
+	}
+
+	if r.Judge != "" {
+		fmt.Fprintf(&b, "\n*Scored 1-10 by %s.*\n", r.Judge)
+	}
+
+	var reasons strings.Builder
+	for _, e := range r.Outputs {
+		if e.Error != "" {
+			fmt.Fprintf(&reasons, "- **%s**: not scored: %s\n", e.Output, e.Error)
+			continue
+		}
+		for _, s := range e.Scores {
+			if s.Reason != "" {
+				fmt.Fprintf(&reasons, "- **%s** %s %d: %s\n", e.Output, s.Criterion, s.Score, s.Reason)
+			}
+		}
+	}
+	if reasons.Len() > 0 {
+		b.WriteString("\n<details><summary>Judge's reasons</summary>\n\n")
+		b.WriteString(reasons.String())
+		b.WriteString("\n</details>\n")
+	}
+	return b.String()
+}
+
+// Record writes the scores into asset status metadata
+func (r *Report) Record(metadata map[string]string) {
+	var overall []string
+	for _, e := range r.Outputs {
+		if !e.Scored() {
+			continue
+		}
+		overall = append(overall, fmt.Sprintf("%s=%.1f", e.Output, e.Overall))
+
+		scores := make([]string, len(e.Scores))
+		for i, s := range e.Scores {
+			scores[i] = fmt.Sprintf("%s=%d", s.Criterion, s.Score)
+		}
+		metadata[metadataScores+e.Output] = strings.Join(scores, ",")
+	}
+	if len(overall) == 0 {
+		return
+	}
+	metadata[MetadataEvaluation] = strings.Join(overall, ",")
+	metadata[MetadataJudge] = r.Judge
+	metadata[MetadataFlagged] = strconv.FormatBool(r.Flagged)
+}
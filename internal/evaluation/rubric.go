@@ -0,0 +1,80 @@
+// Package evaluation scores persona documents against a rubric using a
+// judge model, so the synthesis can be compared with the raw outputs it
+// was built from.
+package evaluation
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// DefaultRubricFile is where the rubric is read from unless configured otherwise
+const DefaultRubricFile = "prompts/evaluation_rubric.txt"
+
+// Criterion is one dimension a persona is scored on
+type Criterion struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Rubric is the list of criteria a judge scores
+type Rubric []Criterion
+
+// DefaultRubric returns the built-in rubric
+func DefaultRubric() Rubric {
+	return Rubric{
+		{Name: "accuracy", Description: "Facts, dates, events and quotes are correct and attributed; nothing is invented or misattributed"},
+		{Name: "depth", Description: "Specific anecdotes, examples and evidence rather than generic description"},
+		{Name: "voice", Description: "Captures how the person actually speaks and writes well enough to emulate them"},
+		{Name: "consistency", Description: "Sections agree with each other; contradictions in the person are explained, not accidental"},
+		{Name: "coverage", Description: "Every section of the persona template is present and substantive"},
+	}
+}
+
+// Names returns the criterion names in order
+func (r Rubric) Names() []string {
+	names := make([]string, len(r))
+	for i, c := range r {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// LoadRubric reads a rubric file with one "- name: description" line per
+// criterion. Blank lines and lines starting with # are ignored.
+func LoadRubric(path string) (Rubric, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open rubric: %w", err)
+	}
+	defer file.Close()
+
+	var rubric Rubric
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, description, ok := strings.Cut(strings.TrimSpace(strings.TrimPrefix(line, "-")), ":")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !ok || name == "" || strings.ContainsAny(name, " \t") {
+			return nil, fmt.Errorf("invalid rubric line %q: expected \"- name: description\"", line)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("criterion %q is listed twice", name)
+		}
+		seen[name] = true
+		rubric = append(rubric, Criterion{Name: name, Description: strings.TrimSpace(description)})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rubric: %w", err)
+	}
+	if len(rubric) == 0 {
+		return nil, fmt.Errorf("rubric %s has no criteria", path)
+	}
+	return rubric, nil
+}
@@ -0,0 +1,169 @@
+package facts
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/twin2ai/studio/internal/llm"
+	"github.com/twin2ai/studio/internal/provider"
+)
+
+// extractAttempts caps the requests made to extract a fact sheet,
+// including the one correcting an invalid reply
+const extractAttempts = 2
+
+// maxFacts caps the facts asked for, keeping the sheet and the synthesis
+// prompt it is added to a manageable size
+const maxFacts = 60
+
+var categories = []string{"biography", "career", "work", "belief", "relationship", "other"}
+
+// Output is one provider's raw persona
+type Output struct {
+	Provider string
+	Content  string
+}
+
+// Extractor builds fact sheets with the synthesis providers
+type Extractor struct {
+	chain  *provider.SynthesisChain
+	logger *logrus.Logger
+}
+
+// NewExtractor creates an extractor that runs on chain, falling back along it
+func NewExtractor(chain *provider.SynthesisChain, logger *logrus.Logger) *Extractor {
+	return &Extractor{chain: chain, logger: logger}
+}
+
+// Extract lists the atomic facts stated by the outputs, merging facts that
+// several providers state in different words. Each fact's support is the
+// number of providers that stated it; facts are ordered by support.
+func (e *Extractor) Extract(ctx context.Context, personaName string, outputs []Output) (*Sheet, error) {
+	if len(outputs) == 0 {
+		return nil, errors.New("no provider outputs to extract facts from")
+	}
+
+	outputs = unique(outputs)
+	sheet := &Sheet{Persona: personaName, GeneratedAt: time.Now().UTC(), SourceHash: HashOutputs(outputs)}
+	known := make(map[string]bool)
+	var profiles []string
+	for _, o := range outputs {
+		known[o.Provider] = true
+		sheet.Providers = append(sheet.Providers, o.Provider)
+		profiles = append(profiles, fmt.Sprintf("SOURCE: %s\n<<<\n%s\n>>>", o.Provider, o.Content))
+	}
+
+	subject := personaName
+	if subject == "" {
+		subject = "the person these profiles describe"
+	}
+	req := llm.Request{System: extractPrompt, Schema: factsSchema(sheet.Providers)}.
+		User(fmt.Sprintf("SUBJECT: %s\n\n%s", subject, strings.Join(profiles, "\n\n")))
+
+	var reply struct {
+		Facts []Fact `json:"facts"`
+	}
+	result, err := e.chain.CompleteJSON(ctx, req, llm.StageAnalysis, extractAttempts, &reply, nil)
+	if err != nil {
+		return nil, err
+	}
+	sheet.Extractor = fmt.Sprintf("%s (%s)", result.Provider, result.Model)
+
+	for _, f := range reply.Facts {
+		seen := make(map[string]bool)
+		var sources []string
+		for _, s := range f.Sources {
+			if known[s] && !seen[s] {
+				seen[s] = true
+				sources = append(sources, s)
+			}
+		}
+		statement := strings.TrimSpace(f.Statement)
+		if len(sources) == 0 || statement == "" {
+			continue
+		}
+		sort.Strings(sources)
+		sheet.Facts = append(sheet.Facts, Fact{
+			Statement:  statement,
+			Category:   f.Category,
+			Sources:    sources,
+			Support:    len(sources),
+			Confidence: ConfidenceFor(len(sources), len(sheet.Providers)),
+		})
+	}
+	sort.SliceStable(sheet.Facts, func(i, j int) bool { return sheet.Facts[i].Support > sheet.Facts[j].Support })
+	return sheet, nil
+}
+
+// HashOutputs identifies a set of raw outputs, so a fact sheet is only
+// extracted again when they change. The order of the outputs does not matter.
+func HashOutputs(outputs []Output) string {
+	outputs = unique(outputs)
+	sort.Slice(outputs, func(i, j int) bool { return outputs[i].Provider < outputs[j].Provider })
+
+	h := sha256.New()
+	for _, o := range outputs {
+		fmt.Fprintf(h, "%s\n%d\n%s", o.Provider, len(o.Content), o.Content)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// unique keeps the first output of each provider
+func unique(outputs []Output) []Output {
+	seen := make(map[string]bool)
+	var kept []Output
+	for _, o := range outputs {
+		if !seen[o.Provider] {
+			seen[o.Provider] = true
+			kept = append(kept, o)
+		}
+	}
+	return kept
+}
+
+var extractPrompt = fmt.Sprintf(`You build fact sheets from several persona profiles of the same real person, each written independently by a different AI provider and labelled with its SOURCE name. List the atomic facts the profiles state about the person: one checkable statement each, such as a date, place, relationship, position held, work, event or stated belief. Skip observations about voice and style.
+
+When several profiles state the same fact, even in different words, list it once with every source that states it. List each source only if its profile actually states the fact. When profiles contradict each other, list each version as a separate fact with its own sources. Give at most %d facts, the most important first.
+
+Reply with a single JSON object and nothing else:
+{"facts": [{"statement": "<fact>", "category": "%s", "sources": ["<source>"]}]}`, maxFacts, strings.Join(categories, "|"))
+
+// factsSchema is the JSON Schema of an extraction reply from sources
+func factsSchema(sources []string) json.RawMessage {
+	document := map[string]interface{}{
+		"type":                 "object",
+		"additionalProperties": false,
+		"required":             []string{"facts"},
+		"properties": map[string]interface{}{
+			"facts": map[string]interface{}{
+				"type":     "array",
+				"maxItems": maxFacts,
+				"items": map[string]interface{}{
+					"type":                 "object",
+					"additionalProperties": false,
+					"required":             []string{"statement", "category", "sources"},
+					"properties": map[string]interface{}{
+						"statement": map[string]interface{}{"type": "string", "minLength": 1},
+						"category":  map[string]interface{}{"type": "string", "enum": categories},
+						"sources": map[string]interface{}{
+							"type":     "array",
+							"minItems": 1,
+							"items":    map[string]interface{}{"type": "string", "enum": sources},
+						},
+					},
+				},
+			},
+		},
+	}
+	encoded, _ := json.Marshal(document)
+	return encoded
+}
@@ -0,0 +1,87 @@
+package facts
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/twin2ai/studio/internal/assets"
+	"github.com/twin2ai/studio/internal/github"
+	"github.com/twin2ai/studio/internal/usage"
+	"github.com/twin2ai/studio/pkg/models"
+)
+
+// Service regenerates the facts.json of personas in the personas repository
+type Service struct {
+	extractor *Extractor
+	github    *github.Client
+	providers []string
+	logger    *logrus.Logger
+}
+
+// NewService creates a service that reads raw/<provider>.md for each of
+// providers and opens a PR with the new fact sheet
+func NewService(extractor *Extractor, githubClient *github.Client, providers []string, logger *logrus.Logger) *Service {
+	return &Service{extractor: extractor, github: githubClient, providers: providers, logger: logger}
+}
+
+// Generate extracts the fact sheet of the persona in folderName from its
+// raw outputs and opens a PR when the facts differ from its facts.json.
+// Nothing is extracted while the raw outputs match the ones facts.json was
+// built from. It is the asset monitor's callback for assets.AssetTypeFacts.
+func (s *Service) Generate(ctx context.Context, folderName string, assetType assets.AssetType) error {
+	baseFolder := fmt.Sprintf("personas/%s", folderName)
+
+	personaName := folderName
+	if synthesized, err := s.github.GetFileContent(ctx, fmt.Sprintf("%s/%s", baseFolder, models.SynthesizedFile)); err == nil {
+		if p, err := models.Parse(synthesized); err == nil && p.Name != "" {
+			personaName = p.Name
+		}
+	}
+
+	var outputs []Output
+	for _, name := range s.providers {
+		content, err := s.github.GetFileContent(ctx, fmt.Sprintf("%s/%s/%s.md", baseFolder, models.RawDir, name))
+		if err != nil {
+			s.logger.Debugf("No %s raw output for %s: %v", name, folderName, err)
+			continue
+		}
+		outputs = append(outputs, Output{Provider: name, Content: content})
+	}
+	if len(outputs) == 0 {
+		return fmt.Errorf("no raw outputs found for %s", folderName)
+	}
+
+	// The sheet only depends on the raw outputs; skip the extraction when
+	// facts.json was built from the same ones
+	var previous *Sheet
+	if existing, err := s.github.GetFileContent(ctx, fmt.Sprintf("%s/%s", baseFolder, models.FactsFile)); err == nil {
+		if previous, err = Parse([]byte(existing)); err == nil && previous.SourceHash == HashOutputs(outputs) {
+			s.logger.Infof("Raw outputs for %s are unchanged since its fact sheet, skipping extraction", personaName)
+			return nil
+		}
+	}
+
+	ctx = usage.WithLabels(ctx, usage.Labels{Persona: personaName, Stage: usage.StageAnalysis})
+	sheet, err := s.extractor.Extract(ctx, personaName, outputs)
+	if err != nil {
+		return fmt.Errorf("failed to extract facts: %w", err)
+	}
+
+	if previous != nil && previous.SameFacts(sheet) {
+		s.logger.Infof("Fact sheet for %s is unchanged, skipping PR", personaName)
+		return nil
+	}
+
+	content, err := sheet.JSON()
+	if err != nil {
+		return err
+	}
+	pr, err := s.github.CreateFactSheetPR(ctx, personaName, folderName, content, sheet.Summary())
+	if err != nil {
+		return fmt.Errorf("failed to create fact sheet PR: %w", err)
+	}
+	s.logger.Infof("Created fact sheet PR #%d for %s (%s): %s", pr.GetNumber(), personaName, sheet.Summary(), pr.GetHTMLURL())
+	return nil
+}
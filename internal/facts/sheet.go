@@ -0,0 +1,151 @@
+// Package facts builds a persona's fact sheet: the atomic facts stated by
+// its raw provider outputs, each tagged with how many providers stated it
+// independently. Synthesis prompts use it to favour consensus.
+package facts
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MetadataFacts is the .assets_status.json key holding the number of facts
+// per confidence level, e.g. "high=15,medium=5,low=4"
+const MetadataFacts = "facts"
+
+// Confidence levels of a fact
+const (
+	ConfidenceHigh   = "high"   // Stated by most providers
+	ConfidenceMedium = "medium" // Stated by more than one provider, but not most
+	ConfidenceLow    = "low"    // Single-source claim
+)
+
+// Fact is one atomic statement about the persona's subject
+type Fact struct {
+	Statement  string   `json:"statement"`
+	Category   string   `json:"category"`
+	Sources    []string `json:"sources"`
+	Support    int      `json:"support"` // Number of providers that stated it
+	Confidence string   `json:"confidence"`
+}
+
+// Sheet is a persona's facts.json
+type Sheet struct {
+	Persona     string    `json:"persona"`
+	GeneratedAt time.Time `json:"generated_at"`
+	Extractor   string    `json:"extractor,omitempty"`
+	SourceHash  string    `json:"source_hash,omitempty"` // HashOutputs of the raw outputs it was extracted from
+	Providers   []string  `json:"providers"`
+	Facts       []Fact    `json:"facts"`
+}
+
+// ConfidenceFor rates a fact stated by support of providers
+func ConfidenceFor(support, providers int) string {
+	switch {
+	case support <= 1:
+		return ConfidenceLow
+	case support*2 > providers:
+		return ConfidenceHigh
+	default:
+		return ConfidenceMedium
+	}
+}
+
+// Parse reads a facts.json
+func Parse(data []byte) (*Sheet, error) {
+	var sheet Sheet
+	if err := json.Unmarshal(data, &sheet); err != nil {
+		return nil, fmt.Errorf("failed to parse fact sheet: %w", err)
+	}
+	return &sheet, nil
+}
+
+// JSON encodes the sheet as facts.json
+func (s *Sheet) JSON() (string, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode fact sheet: %w", err)
+	}
+	return string(data) + "\n", nil
+}
+
+// Count returns the number of facts with a confidence level
+func (s *Sheet) Count(confidence string) int {
+	n := 0
+	for _, f := range s.Facts {
+		if f.Confidence == confidence {
+			n++
+		}
+	}
+	return n
+}
+
+// Summary returns a one-line count, e.g. "24 facts: 15 high, 5 medium, 4 single-source"
+func (s *Sheet) Summary() string {
+	return fmt.Sprintf("%d facts: %d high, %d medium, %d single-source",
+		len(s.Facts), s.Count(ConfidenceHigh), s.Count(ConfidenceMedium), s.Count(ConfidenceLow))
+}
+
+// Record writes the fact counts into asset status metadata
+func (s *Sheet) Record(metadata map[string]string) {
+	metadata[MetadataFacts] = fmt.Sprintf("%s=%d,%s=%d,%s=%d", ConfidenceHigh, s.Count(ConfidenceHigh),
+		ConfidenceMedium, s.Count(ConfidenceMedium), ConfidenceLow, s.Count(ConfidenceLow))
+}
+
+// SameFacts reports whether two sheets list the same facts, ignoring when
+// and by whom they were extracted
+func (s *Sheet) SameFacts(other *Sheet) bool {
+	if other == nil || len(s.Facts) != len(other.Facts) {
+		return false
+	}
+	a, _ := json.Marshal(s.Facts)
+	b, _ := json.Marshal(other.Facts)
+	return string(a) == string(b)
+}
+
+// Apply adds the sheet's consensus block to a combination prompt: in place
+// of {{FACTS}} when the prompt has it, else just before {{PERSONAS}}. A nil
+// sheet only removes the placeholder.
+func Apply(prompt string, sheet *Sheet) string {
+	block := ""
+	if sheet != nil && len(sheet.Facts) > 0 {
+		block = sheet.PromptBlock()
+	}
+	if strings.Contains(prompt, "{{FACTS}}") {
+		return strings.ReplaceAll(prompt, "{{FACTS}}", block)
+	}
+	if block == "" {
+		return prompt
+	}
+	if i := strings.Index(prompt, "{{PERSONAS}}"); i >= 0 {
+		return prompt[:i] + block + "\n" + prompt[i:]
+	}
+	return prompt + "\n\n" + block
+}
+
+// PromptBlock tells the synthesizer which facts the providers agree on
+func (s *Sheet) PromptBlock() string {
+	var b strings.Builder
+	b.WriteString("FACT CONSENSUS:\n\n")
+	fmt.Fprintf(&b, "These facts were extracted from the input personas, with how many of the %d providers stated each independently.\n", len(s.Providers))
+	b.WriteString("- Favour high-consensus facts. Where the personas conflict, follow the version most providers state.\n")
+	b.WriteString("- Keep a single-source claim only when it is plausible and adds something, and mark it in the text with \"(single source)\".\n")
+
+	for _, level := range []struct{ confidence, heading string }{
+		{ConfidenceHigh, "High consensus"},
+		{ConfidenceMedium, "Some agreement"},
+		{ConfidenceLow, "Single source"},
+	} {
+		if s.Count(level.confidence) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n%s:\n", level.heading)
+		for _, f := range s.Facts {
+			if f.Confidence == level.confidence {
+				fmt.Fprintf(&b, "- [%d/%d: %s] %s\n", f.Support, len(s.Providers), strings.Join(f.Sources, ", "), f.Statement)
+			}
+		}
+	}
+	return b.String()
+}
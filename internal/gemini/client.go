@@ -5,19 +5,35 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
+
+	"github.com/twin2ai/studio/internal/llm"
 )
 
+// PromptModel is the model used by GeneratePersonaPrompt
+const PromptModel = "gemini-2.5-flash"
+
+// defaultParams are the generation settings used unless configured otherwise:
+// creative persona generation, cooler synthesis and prompt generation, and
+// deterministic evaluation and analysis
+var defaultParams = llm.ParamSet{
+	llm.StageGeneration: {Temperature: llm.Float(0.7), MaxTokens: 20000, Seed: llm.Int(12)},
+	llm.StageSynthesis:  {Temperature: llm.Float(0.3), MaxTokens: 20000, Seed: llm.Int(12)},
+	llm.StagePrompt:     {Temperature: llm.Float(0.3), MaxTokens: 20000},
+	llm.StageEvaluation: {Temperature: llm.Float(0), MaxTokens: 4000, Seed: llm.Int(12)},
+	llm.StageAnalysis:   {Temperature: llm.Float(0), MaxTokens: 8000, Seed: llm.Int(12)},
+}
+
 type Client struct {
-	apiKey     string
-	model      string
-	httpClient *http.Client
-	logger     *logrus.Logger
+	apiKey string
+	model  string
+	params llm.ParamSet
+	sender *llm.Sender
+	logger *logrus.Logger
 }
 
 type Content struct {
@@ -30,9 +46,19 @@ type Part struct {
 }
 
 type GenerationConfig struct {
-	MaxOutputTokens int     `json:"maxOutputTokens"`
-	Seed            int     `json:"seed,omitempty"`
-	Temperature     float64 `json:"temperature,omitempty"`
+	MaxOutputTokens int             `json:"maxOutputTokens,omitempty"`
+	Seed            *int            `json:"seed,omitempty"`
+	Temperature     *float64        `json:"temperature,omitempty"`
+	TopP            *float64        `json:"topP,omitempty"`
+	StopSequences   []string        `json:"stopSequences,omitempty"`
+	ThinkingConfig  *ThinkingConfig `json:"thinkingConfig,omitempty"`
+	// ResponseMimeType set to application/json turns on JSON mode
+	ResponseMimeType string `json:"responseMimeType,omitempty"`
+}
+
+// ThinkingConfig sets the thinking budget of Gemini 2.5 models
+type ThinkingConfig struct {
+	ThinkingBudget int `json:"thinkingBudget"`
 }
 
 type SafetySetting struct {
@@ -41,9 +67,10 @@ type SafetySetting struct {
 }
 
 type Request struct {
-	Contents         []Content        `json:"contents"`
-	GenerationConfig GenerationConfig `json:"generationConfig"`
-	SafetySettings   []SafetySetting  `json:"safetySettings,omitempty"`
+	SystemInstruction *Content         `json:"systemInstruction,omitempty"`
+	Contents          []Content        `json:"contents"`
+	GenerationConfig  GenerationConfig `json:"generationConfig"`
+	SafetySettings    []SafetySetting  `json:"safetySettings,omitempty"`
 }
 
 type Response struct {
@@ -74,26 +101,69 @@ func NewClient(apiKey, model string, logger *logrus.Logger) *Client {
 	return &Client{
 		apiKey: apiKey,
 		model:  model,
-		httpClient: &http.Client{
-			Timeout: 600 * time.Second,
-		},
+		sender: llm.NewSender("gemini", 600*time.Second, logger),
 		logger: logger,
 	}
 }
 
-// GeneratePersona generates a persona with standard temperature (0.7) for creative output
-// This method is used for initial persona generation where creativity is desired
+// Sender returns the HTTP sender so callers can adjust its retry policy
+func (c *Client) Sender() *llm.Sender {
+	return c.sender
+}
+
+// SetParams overrides the default generation settings per stage
+func (c *Client) SetParams(params llm.ParamSet) {
+	c.params = params
+}
+
+// generationConfig returns the generation settings for stage
+func (c *Client) generationConfig(stage llm.Stage) GenerationConfig {
+	params := defaultParams.Resolve(stage, c.params)
+	config := GenerationConfig{
+		MaxOutputTokens: params.MaxTokens,
+		Seed:            params.Seed,
+		Temperature:     params.Temperature,
+		TopP:            params.TopP,
+		StopSequences:   params.StopSequences,
+	}
+	if params.ThinkingBudget > 0 {
+		config.ThinkingConfig = &ThinkingConfig{ThinkingBudget: params.ThinkingBudget}
+	}
+	return config
+}
+
+// post sends a JSON request body to url through the retrying sender
+func (c *Client) post(ctx context.Context, url string, body []byte) ([]byte, error) {
+	return c.sender.Do(ctx, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
+}
+
+// GeneratePersona generates a persona with the generation settings (temperature 0.7
+// by default). This method is used for initial persona generation where creativity is desired
 func (c *Client) GeneratePersona(ctx context.Context, prompt string) (string, error) {
-	// Use standard temperature for creative persona generation
-	return c.generateWithTemperature(ctx, prompt, 0.7)
+	result, err := c.Generate(ctx, prompt, llm.StageGeneration)
+	if err != nil {
+		return "", err
+	}
+	return result.Text, nil
 }
 
-// GenerateSynthesis generates content with lower temperature (0.3) for consistent synthesis
-// Use this for combining multiple inputs, regenerating with feedback, or any task
-// requiring predictable, faithful output rather than creative interpretation
+// GenerateSynthesis generates content with the synthesis settings (temperature 0.3 by
+// default) for consistent synthesis. Use this for combining multiple inputs, regenerating
+// with feedback, or any task requiring predictable, faithful output rather than creative
+// interpretation
 func (c *Client) GenerateSynthesis(ctx context.Context, prompt string) (string, error) {
-	// Use lower temperature for synthesis to get more consistent, predictable output
-	return c.generateWithTemperature(ctx, prompt, 0.3)
+	result, err := c.Generate(ctx, prompt, llm.StageSynthesis)
+	if err != nil {
+		return "", err
+	}
+	return result.Text, nil
 }
 
 // GeneratePersonaSynthesis is deprecated - use GenerateSynthesis instead
@@ -102,77 +172,137 @@ func (c *Client) GeneratePersonaSynthesis(ctx context.Context, prompt string) (s
 	return c.GenerateSynthesis(ctx, prompt)
 }
 
-func (c *Client) generateWithTemperature(ctx context.Context, prompt string, temperature float64) (string, error) {
-	url := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/%s:generateContent?key=%s", c.model, c.apiKey)
+// Generate sends a single prompt with the settings for stage and reports the
+// text with its stop reason
+func (c *Client) Generate(ctx context.Context, prompt string, stage llm.Stage) (*llm.Result, error) {
+	return c.Send(ctx, llm.Prompt(prompt), stage)
+}
 
-	request := Request{
-		Contents: []Content{
-			{
-				Parts: []Part{
-					{Text: prompt},
-				},
-			},
-		},
-		GenerationConfig: GenerationConfig{
-			MaxOutputTokens: 20000,
-			Temperature:     temperature,
-			Seed:            12,
-		},
-	}
+// Send sends a conversation with the settings for stage and reports the
+// text with its stop reason
+func (c *Client) Send(ctx context.Context, req llm.Request, stage llm.Stage) (*llm.Result, error) {
+	started := time.Now()
+	result, err := c.send(ctx, req, stage)
+	c.sender.Observe(ctx, c.model, result, started, err)
+	return result, err
+}
 
-	c.logger.Debugf("Gemini request with temperature %.1f for prompt length: %d", temperature, len(prompt))
+// send performs the request for Send
+func (c *Client) send(ctx context.Context, req llm.Request, stage llm.Stage) (*llm.Result, error) {
+	url := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/%s:generateContent?key=%s", c.model, c.apiKey)
 
-	body, err := json.Marshal(request)
+	c.logger.Debugf("Gemini %s request for %d characters in %d messages", stage, req.Len(), len(req.Messages))
+
+	body, err := c.marshalRequest(req, stage)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
+		return nil, err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	responseBody, err := c.post(ctx, url, body)
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return nil, err
+	}
+
+	var response Response
+	if err := json.Unmarshal(responseBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	if len(response.Candidates) == 0 || len(response.Candidates[0].Content.Parts) == 0 {
+		return nil, fmt.Errorf("empty response from Gemini API")
+	}
 
-	resp, err := c.httpClient.Do(req)
+	candidate := response.Candidates[0]
+	result := &llm.Result{
+		Text:       joinParts(candidate.Content.Parts),
+		StopReason: stopReason(candidate.FinishReason),
+		Usage: llm.Usage{
+			InputTokens:  response.UsageMetadata.PromptTokenCount,
+			OutputTokens: response.UsageMetadata.CandidatesTokenCount,
+		},
+		Model: c.model,
+	}
+	if result.StopReason.Truncated() {
+		c.logger.Warnf("Gemini response hit the MAX_TOKENS limit (%d output tokens)", response.UsageMetadata.CandidatesTokenCount)
+	}
+
+	return result, nil
+}
+
+// marshalRequest builds the generateContent request body for a conversation
+func (c *Client) marshalRequest(req llm.Request, stage llm.Stage) ([]byte, error) {
+	system, contents := conversation(req)
+	request := Request{
+		SystemInstruction: system,
+		Contents:          contents,
+		GenerationConfig:  c.generationConfig(stage),
+	}
+	if len(req.Schema) > 0 {
+		request.GenerationConfig.ResponseMimeType = "application/json"
+	}
+
+	body, err := json.Marshal(request)
 	if err != nil {
-		return "", fmt.Errorf("failed to send request: %w", err)
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
-	defer resp.Body.Close()
+	return body, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+// conversation maps a request onto a system instruction and contents
+func conversation(req llm.Request) (*Content, []Content) {
+	var system *Content
+	if req.System != "" {
+		system = &Content{Parts: []Part{{Text: req.System}}}
+	}
+	contents := make([]Content, 0, len(req.Messages))
+	for _, m := range req.Messages {
+		contents = append(contents, Content{Role: role(m.Role), Parts: []Part{{Text: m.Content}}})
 	}
+	return system, contents
+}
 
-	var response Response
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
+// role maps a conversation role onto Gemini's user and model roles
+func role(r llm.Role) string {
+	if r == llm.RoleAssistant {
+		return "model"
 	}
+	return "user"
+}
 
-	if len(response.Candidates) == 0 || len(response.Candidates[0].Content.Parts) == 0 {
-		return "", fmt.Errorf("empty response from Gemini API")
+// joinParts concatenates the text of all parts of a candidate
+func joinParts(parts []Part) string {
+	var text strings.Builder
+	for _, part := range parts {
+		text.WriteString(part.Text)
 	}
+	return text.String()
+}
 
-	return response.Candidates[0].Content.Parts[0].Text, nil
+// stopReason maps Gemini's finishReason onto llm.StopReason
+func stopReason(reason string) llm.StopReason {
+	switch reason {
+	case "STOP", "":
+		return llm.StopEnd
+	case "MAX_TOKENS":
+		return llm.StopMaxTokens
+	case "SAFETY", "RECITATION", "BLOCKLIST", "PROHIBITED_CONTENT", "SPII":
+		return llm.StopSafety
+	default:
+		return llm.StopUnknown
+	}
 }
 
 // GeneratePersonaPrompt generates a persona prompt using Gemini Flash with optimized settings
-func (c *Client) GeneratePersonaPrompt(ctx context.Context, promptInput string) (string, error) {
+func (c *Client) GeneratePersonaPrompt(ctx context.Context, req llm.Request) (string, error) {
 	c.logger.Debugf("Generating persona prompt with Gemini Flash")
 
+	system, contents := conversation(req)
 	request := Request{
-		Contents: []Content{
-			{
-				Parts: []Part{
-					{Text: promptInput},
-				},
-			},
-		},
-		GenerationConfig: GenerationConfig{
-			Temperature:     0.3,   // Lower temperature for more consistent prompt generation
-			MaxOutputTokens: 20000, // Increased to handle internal reasoning + output
-		},
+		SystemInstruction: system,
+		Contents:          contents,
+		// Prompt generation settings: lower temperature for consistency and a
+		// high token limit to leave room for internal reasoning
+		GenerationConfig: c.generationConfig(llm.StagePrompt),
 		SafetySettings: []SafetySetting{
 			{Category: "HARM_CATEGORY_HARASSMENT", Threshold: "BLOCK_ONLY_HIGH"},
 			{Category: "HARM_CATEGORY_HATE_SPEECH", Threshold: "BLOCK_ONLY_HIGH"},
@@ -187,41 +317,28 @@ func (c *Client) GeneratePersonaPrompt(ctx context.Context, promptInput string)
 	}
 
 	// Use the working model and endpoint
-	flashModel := "gemini-2.5-flash"
 	endpoint := "v1"
-	url := fmt.Sprintf("https://generativelanguage.googleapis.com/%s/models/%s:generateContent?key=%s", endpoint, flashModel, c.apiKey)
+	url := fmt.Sprintf("https://generativelanguage.googleapis.com/%s/models/%s:generateContent?key=%s", endpoint, PromptModel, c.apiKey)
 
-	return c.tryGenerateWithModel(ctx, url, requestBody, flashModel)
+	return c.tryGenerateWithModel(ctx, url, requestBody, PromptModel)
 }
 
 // tryGenerateWithModel attempts to generate content with a specific model
-func (c *Client) tryGenerateWithModel(ctx context.Context, url string, requestBody []byte, model string) (string, error) {
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(requestBody))
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
+func (c *Client) tryGenerateWithModel(ctx context.Context, url string, requestBody []byte, model string) (text string, err error) {
+	started := time.Now()
+	var usage llm.Usage
+	defer func() {
+		c.sender.Observe(ctx, model, &llm.Result{Usage: usage}, started, err)
+	}()
 
 	c.logger.Debugf("Sending prompt generation request to %s", model)
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Read the full response body for debugging
-	responseBody, err := io.ReadAll(resp.Body)
+	responseBody, err := c.post(ctx, url, requestBody)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response body: %w", err)
+		c.logger.Errorf("%s API error: %v", model, err)
+		return "", err
 	}
 
-	c.logger.Debugf("%s response status: %d, body length: %d bytes", model, resp.StatusCode, len(responseBody))
-
-	if resp.StatusCode != http.StatusOK {
-		c.logger.Errorf("%s API error response: %s", model, string(responseBody))
-		return "", fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(responseBody))
-	}
+	c.logger.Debugf("%s response body length: %d bytes", model, len(responseBody))
 
 	// Log a snippet of the response for debugging (first 500 chars)
 	if len(responseBody) > 500 {
@@ -239,6 +356,11 @@ func (c *Client) tryGenerateWithModel(ctx context.Context, url string, requestBo
 
 	c.logger.Debugf("Response has %d candidates", len(response.Candidates))
 
+	usage = llm.Usage{
+		InputTokens:  response.UsageMetadata.PromptTokenCount,
+		OutputTokens: response.UsageMetadata.CandidatesTokenCount,
+	}
+
 	// Log usage metadata if available
 	if response.UsageMetadata.TotalTokenCount > 0 {
 		c.logger.Debugf("Token usage - Prompt: %d, Candidates: %d, Total: %d",
@@ -258,7 +380,7 @@ func (c *Client) tryGenerateWithModel(ctx context.Context, url string, requestBo
 		for _, rating := range response.PromptFeedback.SafetyRatings {
 			c.logger.Warnf("Safety rating - %s: %s", rating.Category, rating.Probability)
 		}
-		return "", fmt.Errorf("prompt blocked by safety filter: %s", response.PromptFeedback.BlockReason)
+		return "", fmt.Errorf("prompt rejected: %w", &llm.SafetyError{Reason: response.PromptFeedback.BlockReason})
 	}
 
 	if len(response.Candidates) == 0 {
@@ -276,7 +398,7 @@ func (c *Client) tryGenerateWithModel(ctx context.Context, url string, requestBo
 		for _, rating := range candidate.SafetyRatings {
 			c.logger.Warnf("Safety rating - %s: %s", rating.Category, rating.Probability)
 		}
-		return "", fmt.Errorf("content filtered for safety: finish reason SAFETY")
+		return "", fmt.Errorf("response rejected: %w", &llm.SafetyError{Reason: "finish reason SAFETY"})
 	}
 
 	if candidate.FinishReason == "MAX_TOKENS" {
@@ -299,50 +421,56 @@ func (c *Client) tryGenerateWithModel(ctx context.Context, url string, requestBo
 	return result, nil
 }
 
-// TestConnection tests the API connection and model availability with a simple prompt
+// TestConnection sends a minimal request to confirm the API key and the
+// configured model work
 func (c *Client) TestConnection(ctx context.Context) error {
-	testPrompt := "Say 'Hello, this is a test' to confirm the API is working."
-
-	c.logger.Infof("Testing Gemini API connection...")
+	c.logger.Debugf("Testing Gemini API connection with %s...", c.model)
 
-	// Try the current configured model first
-	result, err := c.GeneratePersonaPrompt(ctx, testPrompt)
-	if err == nil && result != "" {
-		c.logger.Infof("API connection test successful. Response: %s", result)
-		return nil
+	url := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/%s:generateContent?key=%s", c.model, c.apiKey)
+	request := Request{
+		Contents:         []Content{{Parts: []Part{{Text: "Say 'Hello, this is a test' to confirm the API is working."}}}},
+		GenerationConfig: GenerationConfig{Temperature: llm.Float(0.1), MaxOutputTokens: 10},
 	}
 
-	c.logger.Warnf("API connection test failed: %v", err)
-	return fmt.Errorf("API connection test failed: %w", err)
+	requestBody, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+	if _, err := c.post(ctx, url, requestBody); err != nil {
+		return fmt.Errorf("API connection test failed: %w", err)
+	}
+	return nil
 }
 
-// ListAvailableModels tests the configured model (simplified since we know gemini-2.5-flash works)
-func (c *Client) ListAvailableModels(ctx context.Context) {
-	c.logger.Infof("Testing configured model: gemini-2.5-flash with v1 endpoint...")
-
-	testPrompt := "Test"
-	url := fmt.Sprintf("https://generativelanguage.googleapis.com/v1/models/gemini-2.5-flash:generateContent?key=%s", c.apiKey)
-
-	request := Request{
-		Contents:         []Content{{Parts: []Part{{Text: testPrompt}}}},
-		GenerationConfig: GenerationConfig{Temperature: 0.1, MaxOutputTokens: 10},
+// ListAvailableModels returns the models available to the API key that
+// support generateContent
+func (c *Client) ListAvailableModels(ctx context.Context) ([]string, error) {
+	url := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models?key=%s", c.apiKey)
+	responseBody, err := c.sender.Do(ctx, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, "GET", url, nil)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list models: %w", err)
 	}
 
-	requestBody, _ := json.Marshal(request)
-	req, _ := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(requestBody))
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		c.logger.Errorf("❌ gemini-2.5-flash: %v", err)
-		return
+	var response struct {
+		Models []struct {
+			Name                       string   `json:"name"`
+			SupportedGenerationMethods []string `json:"supportedGenerationMethods"`
+		} `json:"models"`
+	}
+	if err := json.Unmarshal(responseBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to decode models: %w", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode == 200 {
-		c.logger.Infof("✅ gemini-2.5-flash: Available and working")
-	} else {
-		body, _ := io.ReadAll(resp.Body)
-		c.logger.Errorf("❌ gemini-2.5-flash: HTTP %d - %s", resp.StatusCode, string(body))
+	var models []string
+	for _, model := range response.Models {
+		for _, method := range model.SupportedGenerationMethods {
+			if method == "generateContent" {
+				models = append(models, strings.TrimPrefix(model.Name, "models/"))
+				break
+			}
+		}
 	}
+	return models, nil
 }
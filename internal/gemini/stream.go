@@ -0,0 +1,119 @@
+package gemini
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/twin2ai/studio/internal/llm"
+)
+
+// GenerateStream sends a single prompt via streamGenerateContent and calls
+// onDelta with each piece of text as it arrives. If the stream breaks after
+// text has been delivered, the partial text is returned with
+// llm.StopInterrupt so it can be continued.
+func (c *Client) GenerateStream(ctx context.Context, prompt string, stage llm.Stage, onDelta func(text string)) (*llm.Result, error) {
+	return c.SendStream(ctx, llm.Prompt(prompt), stage, onDelta)
+}
+
+// SendStream streams the reply to a conversation like GenerateStream
+func (c *Client) SendStream(ctx context.Context, req llm.Request, stage llm.Stage, onDelta func(text string)) (*llm.Result, error) {
+	started := time.Now()
+	result, err := c.sendStream(ctx, req, stage, onDelta)
+	c.sender.Observe(ctx, c.model, result, started, err)
+	return result, err
+}
+
+// sendStream performs the request for SendStream
+func (c *Client) sendStream(ctx context.Context, req llm.Request, stage llm.Stage, onDelta func(text string)) (*llm.Result, error) {
+	url := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/%s:streamGenerateContent?alt=sse&key=%s", c.model, c.apiKey)
+
+	c.logger.Debugf("Gemini %s stream for %d characters in %d messages", stage, req.Len(), len(req.Messages))
+
+	body, err := c.marshalRequest(req, stage)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *llm.Result
+	var text strings.Builder
+	requestStart := time.Now()
+
+	err = c.sender.Stream(ctx, func(ctx context.Context) (*http.Request, error) {
+		httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+		return httpReq, nil
+	}, func(r io.Reader) error {
+		result = &llm.Result{Model: c.model, StopReason: llm.StopUnknown}
+		text.Reset()
+		finished := false
+
+		readErr := llm.ReadSSE(r, func(event, data string) error {
+			var chunk Response
+			if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+				return fmt.Errorf("failed to decode stream chunk: %w", err)
+			}
+
+			if chunk.PromptFeedback.BlockReason != "" {
+				return fmt.Errorf("prompt rejected: %w", &llm.SafetyError{Reason: chunk.PromptFeedback.BlockReason})
+			}
+
+			if chunk.UsageMetadata.TotalTokenCount > 0 {
+				result.Usage.InputTokens = chunk.UsageMetadata.PromptTokenCount
+				result.Usage.OutputTokens = chunk.UsageMetadata.CandidatesTokenCount
+			}
+
+			if len(chunk.Candidates) == 0 {
+				return nil
+			}
+
+			candidate := chunk.Candidates[0]
+			if delta := joinParts(candidate.Content.Parts); delta != "" {
+				text.WriteString(delta)
+				if onDelta != nil {
+					onDelta(delta)
+				}
+			}
+			if candidate.FinishReason != "" {
+				result.StopReason = stopReason(candidate.FinishReason)
+				finished = true
+			}
+			return nil
+		})
+
+		if readErr != nil {
+			if text.Len() == 0 {
+				return readErr
+			}
+			c.logger.Warnf("Gemini stream interrupted after %d characters: %v", text.Len(), readErr)
+			result.StopReason = llm.StopInterrupt
+		} else if !finished && text.Len() > 0 {
+			c.logger.Warnf("Gemini stream closed without a finish reason after %d characters", text.Len())
+			result.StopReason = llm.StopInterrupt
+		}
+		return nil
+	})
+
+	c.logger.Debugf("Gemini stream completed in %v", time.Since(requestStart))
+	if err != nil {
+		return nil, err
+	}
+
+	result.Text = text.String()
+	if result.Text == "" {
+		return nil, fmt.Errorf("empty response from Gemini API")
+	}
+	if result.StopReason.Truncated() {
+		c.logger.Warnf("Gemini stream ended with stop reason %s (%d output tokens)", result.StopReason, result.Usage.OutputTokens)
+	}
+
+	return result, nil
+}
@@ -2,13 +2,19 @@ package github
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
 	"github.com/google/go-github/v57/github"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/oauth2"
+
+	"github.com/twin2ai/studio/internal/assets"
+	"github.com/twin2ai/studio/internal/validation"
+	"github.com/twin2ai/studio/pkg/models"
 )
 
 type Client struct {
@@ -27,6 +33,9 @@ func (c *Client) GetClient() *github.Client {
 
 func NewClient(token, issuesOwner, issuesRepo, personasOwner, personasRepo, label string, logger *logrus.Logger) *Client {
 	ctx := context.Background()
+	if rt := currentTransport(); rt != nil {
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Transport: rt})
+	}
 	ts := oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: token},
 	)
@@ -414,8 +423,11 @@ func (c *Client) FileExists(ctx context.Context, filePath string) bool {
 	return exists
 }
 
-// CreateSynthesisUpdatePR creates a PR to update synthesized.md from raw outputs
-func (c *Client) CreateSynthesisUpdatePR(ctx context.Context, personaName, folderName, synthesizedContent string) (*github.PullRequest, error) {
+// CreateSynthesisUpdatePR creates a PR to update synthesized.md from raw outputs.
+// sources lists the raw file names (without extension) used for the synthesis,
+// and synthesizer and model name the provider that produced it. report is the
+// linter report on the synthesis, shown in the PR body; nil when not run.
+func (c *Client) CreateSynthesisUpdatePR(ctx context.Context, personaName, folderName, synthesizedContent, synthesizer, model string, sources []string, report *validation.Report) (*github.PullRequest, error) {
 	// Create branch name for synthesis update
 	sanitizedName := strings.ToLower(strings.ReplaceAll(personaName, " ", "-"))
 	sanitizedName = strings.ReplaceAll(sanitizedName, "/", "-")
@@ -471,6 +483,16 @@ func (c *Client) CreateSynthesisUpdatePR(ctx context.Context, personaName, folde
 		fileOpts.SHA = existingFile.SHA
 	}
 
+	// Snapshot the new synthesis into history/ and the changelog
+	version := models.Version{Trigger: models.TriggerResynthesis,
+		Provenance: models.Provenance{Synthesizer: synthesizer, SynthesizerModel: model, Method: "synthesized"}}
+	for _, source := range sources {
+		version.Provenance.Providers = append(version.Provenance.Providers, models.ProviderOutput{Provider: source})
+	}
+	if _, err := c.RecordVersion(ctx, folderName, branchName, personaName, synthesizedContent, version); err != nil {
+		c.logger.Warnf("Failed to record version history: %v", err)
+	}
+
 	// Update the file
 	_, _, err = c.client.Repositories.UpdateFile(
 		ctx, c.personasOwner, c.personasRepo, filePath, fileOpts)
@@ -478,6 +500,22 @@ func (c *Client) CreateSynthesisUpdatePR(ctx context.Context, personaName, folde
 		return nil, fmt.Errorf("failed to update file: %w", err)
 	}
 
+	// Record the synthesizer in the asset status, when the persona has one
+	if err := c.recordSynthesizer(ctx, folderName, branchName, defaultBranch, synthesizer, model); err != nil {
+		c.logger.Warnf("Failed to record synthesizer in asset status: %v", err)
+	}
+
+	// List the raw files the synthesis was built from
+	var sourceList strings.Builder
+	for _, source := range sources {
+		sourceList.WriteString(fmt.Sprintf("- **%s**: personas/%s/raw/%s.md\n", source, folderName, source))
+	}
+
+	var validationReport string
+	if report != nil {
+		validationReport = fmt.Sprintf("\n## ✅ Validation\n%s", report.Markdown())
+	}
+
 	// Create pull request
 	prBody := fmt.Sprintf(`This PR regenerates the synthesized.md for: **%s**
 
@@ -485,25 +523,20 @@ func (c *Client) CreateSynthesisUpdatePR(ctx context.Context, personaName, folde
 Synthesized.md has been regenerated from the existing raw AI outputs.
 
 ## 📊 Source Files Used
-- **Claude**: personas/%s/raw/claude.md
-- **Gemini**: personas/%s/raw/gemini.md
-- **Grok**: personas/%s/raw/grok.md
-- **GPT-4**: personas/%s/raw/gpt.md
-
+%s
 ## 🧬 Synthesis Process
 1. Retrieved all raw AI outputs from the repository
 2. Applied the standard persona combination prompt
-3. Used Gemini to synthesize a comprehensive unified persona
-4. Temperature: 0.3 for consistent synthesis
+3. Synthesized a comprehensive unified persona with **%s** (%s)
 
 ## 📝 Changes
-- Only synthesized.md is updated
+- Only synthesized.md is updated, with the new version added to history/ and CHANGELOG.md
 - Raw files remain unchanged
 - This preserves the original AI outputs while refreshing the synthesis
-
+%s
 ---
 *This is an automated PR created by [Studio](https://github.com/twin2ai/studio) synthesize command*`,
-		personaName, folderName, folderName, folderName, folderName)
+		personaName, sourceList.String(), synthesizer, model, validationReport)
 
 	pr := &github.NewPullRequest{
 		Title: github.String(fmt.Sprintf("Regenerate synthesized.md for %s", personaName)),
@@ -528,3 +561,50 @@ Synthesized.md has been regenerated from the existing raw AI outputs.
 
 	return pullRequest, nil
 }
+
+// recordSynthesizer updates the synthesizer metadata in a persona's
+// .assets_status.json on the given branch. Personas without a status file
+// are left alone.
+func (c *Client) recordSynthesizer(ctx context.Context, folderName, branch, baseBranch, synthesizer, model string) error {
+	statusPath := fmt.Sprintf("personas/%s/.assets_status.json", folderName)
+	existingFile, _, _, err := c.client.Repositories.GetContents(
+		ctx, c.personasOwner, c.personasRepo, statusPath,
+		&github.RepositoryContentGetOptions{Ref: baseBranch})
+	if err != nil || existingFile == nil {
+		return nil
+	}
+
+	content, err := existingFile.GetContent()
+	if err != nil {
+		return fmt.Errorf("failed to decode asset status: %w", err)
+	}
+
+	var status assets.AssetStatus
+	if err := json.Unmarshal([]byte(content), &status); err != nil {
+		return fmt.Errorf("failed to parse asset status: %w", err)
+	}
+	if status.Metadata == nil {
+		status.Metadata = make(map[string]string)
+	}
+	status.LastSynthesizedUpdate = time.Now()
+	status.Metadata["synthesizer"] = synthesizer
+	status.Metadata["synthesizer_model"] = model
+	status.Metadata["synthesis"] = "synthesized"
+
+	statusContent, err := c.AssetStatusJSON(&status)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = c.client.Repositories.UpdateFile(ctx, c.personasOwner, c.personasRepo, statusPath,
+		&github.RepositoryContentFileOptions{
+			Message: github.String(fmt.Sprintf("Update asset status for %s", status.PersonaName)),
+			Content: []byte(statusContent),
+			Branch:  github.String(branch),
+			SHA:     existingFile.SHA,
+		})
+	if err != nil {
+		return fmt.Errorf("failed to update asset status: %w", err)
+	}
+	return nil
+}
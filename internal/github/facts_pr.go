@@ -0,0 +1,72 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+
+	"github.com/twin2ai/studio/pkg/models"
+)
+
+// CreateFactSheetPR creates a PR that writes a regenerated facts.json into
+// a persona folder. summary describes the new sheet, e.g. its fact counts.
+func (c *Client) CreateFactSheetPR(ctx context.Context, personaName, folderName, content, summary string) (*github.PullRequest, error) {
+	sanitizedName := strings.ToLower(strings.ReplaceAll(personaName, " ", "-"))
+	sanitizedName = strings.ReplaceAll(sanitizedName, "/", "-")
+	branchName := fmt.Sprintf("facts/%s-%d", sanitizedName, time.Now().Unix())
+
+	repo, _, err := c.client.Repositories.Get(ctx, c.personasOwner, c.personasRepo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get personas repo: %w", err)
+	}
+	defaultBranch := repo.GetDefaultBranch()
+
+	baseRef, _, err := c.client.Git.GetRef(ctx, c.personasOwner, c.personasRepo, "refs/heads/"+defaultBranch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get base ref: %w", err)
+	}
+	_, _, err = c.client.Git.CreateRef(ctx, c.personasOwner, c.personasRepo, &github.Reference{
+		Ref:    github.String("refs/heads/" + branchName),
+		Object: &github.GitObject{SHA: baseRef.Object.SHA},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create branch: %w", err)
+	}
+
+	filePath := fmt.Sprintf("personas/%s/%s", folderName, models.FactsFile)
+	if err := c.putFile(ctx, filePath, content, branchName, fmt.Sprintf("Regenerate %s for %s", models.FactsFile, personaName)); err != nil {
+		return nil, err
+	}
+
+	prBody := fmt.Sprintf(`This PR regenerates the fact sheet for: **%s**
+
+## 📋 Fact Sheet
+%s/%s was extracted again from the raw AI outputs because synthesized.md changed: %s.
+
+Each fact lists the providers that stated it independently and a confidence level: **high** when most providers agree, **medium** when more than one does, and **low** for single-source claims.
+
+---
+*This is an automated PR created by [Studio](https://github.com/twin2ai/studio)*`,
+		personaName, folderName, models.FactsFile, summary)
+
+	pullRequest, _, err := c.client.PullRequests.Create(ctx, c.personasOwner, c.personasRepo, &github.NewPullRequest{
+		Title: github.String(fmt.Sprintf("Regenerate fact sheet for %s", personaName)),
+		Body:  github.String(prBody),
+		Head:  github.String(branchName),
+		Base:  github.String(defaultBranch),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create PR: %w", err)
+	}
+
+	_, _, err = c.client.Issues.AddLabelsToIssue(ctx, c.personasOwner, c.personasRepo,
+		*pullRequest.Number, []string{"persona", "facts", "studio"})
+	if err != nil {
+		c.logger.Warnf("Failed to add labels to PR: %v", err)
+	}
+
+	return pullRequest, nil
+}
@@ -0,0 +1,50 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/twin2ai/studio/pkg/models"
+)
+
+// folderFiles are the files of a persona folder that models.LoadFolder reads
+var folderFiles = map[string]bool{
+	models.SynthesizedFile: true,
+	models.ProfileFile:     true,
+	models.AssetStatusFile: true,
+	models.ChangelogFile:   true,
+}
+
+// DownloadPersonaFolder copies the files models.LoadFolder reads from a
+// persona folder in the personas repository into dir
+func (c *Client) DownloadPersonaFolder(ctx context.Context, folderName, dir string) error {
+	baseFolder := fmt.Sprintf("personas/%s", folderName)
+	_, entries, _, err := c.client.Repositories.GetContents(
+		ctx, c.personasOwner, c.personasRepo, baseFolder, nil)
+	if err != nil {
+		return fmt.Errorf("failed to list %s: %w", baseFolder, err)
+	}
+
+	found := false
+	for _, entry := range entries {
+		if entry.GetType() != "file" || !folderFiles[entry.GetName()] {
+			continue
+		}
+		content, err := c.GetFileContent(ctx, entry.GetPath())
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, entry.GetName()), []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", entry.GetName(), err)
+		}
+		found = found || entry.GetName() == models.SynthesizedFile
+	}
+	if !found {
+		return fmt.Errorf("no %s in %s", models.SynthesizedFile, baseFolder)
+	}
+
+	c.logger.Debugf("Downloaded persona folder %s to %s", baseFolder, dir)
+	return nil
+}
@@ -0,0 +1,113 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+
+	"github.com/twin2ai/studio/pkg/models"
+)
+
+// RecordVersion snapshots a new synthesized.md into the persona folder's
+// history/ and adds its CHANGELOG.md entry, on branch. Call it before
+// synthesized.md is overwritten: a folder without a changelog has its
+// current synthesized.md kept as the first, untracked version. The
+// version's number, date, path and summary are filled in.
+func (c *Client) RecordVersion(ctx context.Context, folderName, branch, personaName, content string, version models.Version) (*models.Version, error) {
+	baseFolder := fmt.Sprintf("personas/%s", folderName)
+
+	var history []models.Version
+	if changelog, err := c.getFileOnBranch(ctx, path.Join(baseFolder, models.ChangelogFile), branch); err == nil {
+		if history, err = models.ParseChangelog(changelog); err != nil {
+			return nil, fmt.Errorf("failed to parse changelog: %w", err)
+		}
+	}
+
+	var previous *models.Persona
+	if current, err := c.getFileOnBranch(ctx, path.Join(baseFolder, models.SynthesizedFile), branch); err == nil {
+		previous, _ = models.Parse(current)
+		if len(history) == 0 && previous != nil {
+			untracked := models.Version{Number: 1, Trigger: models.TriggerUntracked, Path: models.SnapshotPath(1),
+				Summary: "Version from before history was kept"}
+			if err := c.putFile(ctx, path.Join(baseFolder, untracked.Path), current, branch,
+				fmt.Sprintf("Snapshot %s v1 for persona: %s", models.SynthesizedFile, personaName)); err != nil {
+				return nil, err
+			}
+			history = append(history, untracked)
+		}
+	}
+
+	latest, err := models.Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse new version: %w", err)
+	}
+	version.Number = models.NextVersion(history)
+	version.Path = models.SnapshotPath(version.Number)
+	version.Summary = models.ChangeSummary(previous, latest)
+	if version.Date.IsZero() {
+		version.Date = time.Now()
+	}
+	history = append(history, version)
+
+	message := fmt.Sprintf("Snapshot %s v%d for persona: %s", models.SynthesizedFile, version.Number, personaName)
+	if err := c.putFile(ctx, path.Join(baseFolder, version.Path), content, branch, message); err != nil {
+		return nil, err
+	}
+	if err := c.putFile(ctx, path.Join(baseFolder, models.ChangelogFile), models.RenderChangelog(personaName, history), branch,
+		fmt.Sprintf("Add v%d to changelog for persona: %s", version.Number, personaName)); err != nil {
+		return nil, err
+	}
+
+	c.logger.Infof("Recorded %s v%d (%s): %s", personaName, version.Number, version.Trigger, version.Summary)
+	return &version, nil
+}
+
+// GetPersonaHistory returns the versions in a persona's CHANGELOG.md, oldest first
+func (c *Client) GetPersonaHistory(ctx context.Context, folderName string) ([]models.Version, error) {
+	changelog, err := c.GetFileContent(ctx, fmt.Sprintf("personas/%s/%s", folderName, models.ChangelogFile))
+	if err != nil {
+		return nil, err
+	}
+	return models.ParseChangelog(changelog)
+}
+
+// getFileOnBranch returns a file's content in the personas repo on a branch
+func (c *Client) getFileOnBranch(ctx context.Context, filePath, branch string) (string, error) {
+	fileContent, _, _, err := c.client.Repositories.GetContents(
+		ctx, c.personasOwner, c.personasRepo, filePath,
+		&github.RepositoryContentGetOptions{Ref: branch})
+	if err != nil {
+		return "", fmt.Errorf("failed to get file %s: %w", filePath, err)
+	}
+	if fileContent == nil {
+		return "", fmt.Errorf("file %s not found", filePath)
+	}
+	return fileContent.GetContent()
+}
+
+// putFile creates a file on a branch of the personas repo, or updates it
+// if it exists
+func (c *Client) putFile(ctx context.Context, filePath, content, branch, message string) error {
+	fileOpts := &github.RepositoryContentFileOptions{
+		Message: github.String(message),
+		Content: []byte(content),
+		Branch:  github.String(branch),
+	}
+
+	existingFile, _, _, err := c.client.Repositories.GetContents(
+		ctx, c.personasOwner, c.personasRepo, filePath,
+		&github.RepositoryContentGetOptions{Ref: branch})
+	if err == nil && existingFile != nil {
+		fileOpts.SHA = existingFile.SHA
+		_, _, err = c.client.Repositories.UpdateFile(ctx, c.personasOwner, c.personasRepo, filePath, fileOpts)
+	} else {
+		_, _, err = c.client.Repositories.CreateFile(ctx, c.personasOwner, c.personasRepo, filePath, fileOpts)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", filePath, err)
+	}
+	return nil
+}
@@ -7,25 +7,51 @@ import (
 	"time"
 
 	"github.com/google/go-github/v57/github"
+	"github.com/twin2ai/studio/internal/analysis"
 	"github.com/twin2ai/studio/internal/assets"
+	"github.com/twin2ai/studio/internal/evaluation"
+	"github.com/twin2ai/studio/internal/validation"
+	"github.com/twin2ai/studio/pkg/models"
+	"github.com/twin2ai/studio/pkg/schema"
 )
 
+// RawOutput is a single provider's output, stored as raw/<provider>.md
+type RawOutput struct {
+	Provider string
+	Model    string
+	Content  string
+	Thinking string // Extended thinking, committed as raw/<provider>_thinking.md when set
+}
+
 // PersonaFiles represents all the files that make up a complete persona package
 type PersonaFiles struct {
-	// Raw AI outputs
-	ClaudeRaw string
-	GeminiRaw string
-	GrokRaw   string
-	GPTRaw    string
-	UserRaw   string // Optional user-supplied persona
+	// Raw AI outputs in provider order
+	RawOutputs      []RawOutput
+	UserRaw         string   // Optional user-supplied persona
+	FailedProviders []string // Providers that did not contribute an output
 
 	// Synthesized version
 	FullSynthesis string // The complete synthesized persona
+	PersonaJSON   string // Structured persona.json; empty when not generated
+	Facts         string // facts.json, the consensus-tagged fact sheet; empty when not generated
 
 	// Asset tracking
 	AssetStatus *assets.AssetStatus // Asset generation status
+
+	// Validation is the linter report on FullSynthesis; nil when not run
+	Validation *validation.Report
+
+	// Evaluation is the judge's scores for the raw outputs and FullSynthesis; nil when not run
+	Evaluation *evaluation.Report
+
+	// Disagreements are the claims the raw outputs contradict each other on,
+	// committed as disagreements.md; nil when not run
+	Disagreements *analysis.Report
 }
 
+// NeedsReviewLabel marks PRs whose synthesis scored below the best raw output
+const NeedsReviewLabel = "needs-review"
+
 // CreateStructuredPersonaPR creates a pull request with the new folder structure
 func (c *Client) CreateStructuredPersonaPR(ctx context.Context, issueNumber int, personaName string, files PersonaFiles) (*github.PullRequest, error) {
 	// Create branch name
@@ -69,47 +95,78 @@ func (c *Client) CreateStructuredPersonaPR(ctx context.Context, issueNumber int,
 	baseFolder := fmt.Sprintf("personas/%s", folderName)
 
 	// Track all file operations for the commit
-	fileOperations := []struct {
+	type fileOperation struct {
 		path    string
 		content string
 		message string
-	}{
-		// Raw AI outputs
-		{fmt.Sprintf("%s/raw/claude.md", baseFolder), files.ClaudeRaw, "Claude's raw output"},
-		{fmt.Sprintf("%s/raw/gemini.md", baseFolder), files.GeminiRaw, "Gemini's raw output"},
-		{fmt.Sprintf("%s/raw/grok.md", baseFolder), files.GrokRaw, "Grok's raw output"},
-		{fmt.Sprintf("%s/raw/gpt.md", baseFolder), files.GPTRaw, "GPT-4's raw output"},
+	}
+
+	// Raw AI outputs
+	var fileOperations []fileOperation
+	for _, raw := range files.RawOutputs {
+		fileOperations = append(fileOperations, fileOperation{
+			fmt.Sprintf("%s/raw/%s.md", baseFolder, raw.Provider), raw.Content, fmt.Sprintf("%s's raw output", raw.Provider)})
+		if raw.Thinking != "" {
+			fileOperations = append(fileOperations, fileOperation{
+				fmt.Sprintf("%s/raw/%s_thinking.md", baseFolder, raw.Provider), raw.Thinking, fmt.Sprintf("%s's extended thinking", raw.Provider)})
+		}
+	}
 
+	fileOperations = append(fileOperations,
 		// Main files
-		{fmt.Sprintf("%s/synthesized.md", baseFolder), files.FullSynthesis, "Full synthesized persona"},
+		fileOperation{fmt.Sprintf("%s/synthesized.md", baseFolder), files.FullSynthesis, "Full synthesized persona"},
 
 		// README for the persona folder
-		{fmt.Sprintf("%s/README.md", baseFolder), c.generatePersonaReadme(personaName, issueNumber), "Persona overview"},
+		fileOperation{fmt.Sprintf("%s/README.md", baseFolder), c.generatePersonaReadme(personaName, issueNumber, files.RawOutputs), "Persona overview"},
+	)
+
+	// Add the structured persona next to synthesized.md
+	if files.PersonaJSON != "" {
+		fileOperations = append(fileOperations, fileOperation{
+			fmt.Sprintf("%s/persona.json", baseFolder), files.PersonaJSON, "Structured persona"})
+	}
+
+	// Add the fact sheet the synthesis was guided by
+	if files.Facts != "" {
+		fileOperations = append(fileOperations, fileOperation{
+			fmt.Sprintf("%s/%s", baseFolder, models.FactsFile), files.Facts, "Fact sheet"})
+	}
+
+	// Add the report of claims the providers disagree on
+	if files.Disagreements != nil {
+		fileOperations = append(fileOperations, fileOperation{
+			fmt.Sprintf("%s/%s", baseFolder, analysis.File), files.Disagreements.Document(), "Provider disagreement report"})
 	}
 
 	// Add user-supplied persona if provided
 	if files.UserRaw != "" {
-		fileOperations = append(fileOperations, struct {
-			path    string
-			content string
-			message string
-		}{fmt.Sprintf("%s/raw/user_supplied.md", baseFolder), files.UserRaw, "User-supplied persona"})
+		fileOperations = append(fileOperations, fileOperation{
+			fmt.Sprintf("%s/raw/user_supplied.md", baseFolder), files.UserRaw, "User-supplied persona"})
 	}
 
 	// Add asset status file if provided
 	if files.AssetStatus != nil {
-		statusContent, err := c.generateAssetStatusJSON(files.AssetStatus)
+		statusContent, err := c.AssetStatusJSON(files.AssetStatus)
 		if err != nil {
 			c.logger.Warnf("Failed to generate asset status JSON: %v", err)
 		} else {
-			fileOperations = append(fileOperations, struct {
-				path    string
-				content string
-				message string
-			}{fmt.Sprintf("%s/.assets_status.json", baseFolder), statusContent, "Asset generation status"})
+			fileOperations = append(fileOperations, fileOperation{
+				fmt.Sprintf("%s/.assets_status.json", baseFolder), statusContent, "Asset generation status"})
 		}
 	}
 
+	// Snapshot the new version into history/ and the changelog
+	version := models.Version{Trigger: "batch processing"}
+	if issueNumber > 0 {
+		version.Trigger = fmt.Sprintf("issue #%d", issueNumber)
+	}
+	if files.AssetStatus != nil {
+		version.Provenance = models.ProvenanceFromMetadata(files.AssetStatus.Metadata)
+	}
+	if _, err := c.RecordVersion(ctx, folderName, branchName, personaName, files.FullSynthesis, version); err != nil {
+		c.logger.Warnf("Failed to record version history: %v", err)
+	}
+
 	// Create all files
 	for _, op := range fileOperations {
 		fileOpts := &github.RepositoryContentFileOptions{
@@ -158,24 +215,51 @@ func (c *Client) CreateStructuredPersonaPR(ctx context.Context, issueNumber int,
 		sourceRef = "Created via batch processing"
 	}
 
+	providerSummary := describeProviders(files.RawOutputs)
+
+	var validationReport string
+	if files.Validation != nil {
+		validationReport = fmt.Sprintf("\n## ✅ Validation\n%s", files.Validation.Markdown())
+	}
+	if files.Evaluation != nil {
+		validationReport += fmt.Sprintf("\n## ⚖️ Evaluation\n%s", files.Evaluation.Markdown())
+	}
+	if files.Disagreements != nil {
+		validationReport += fmt.Sprintf("\n## 🔍 Provider Disagreements\n%s", files.Disagreements.Markdown())
+	}
+
+	var structuredFile string
+	if files.PersonaJSON != "" {
+		structuredFile = fmt.Sprintf("- %s/persona.json - Structured persona (%s)\n", baseFolder, schema.PersonaVersion)
+	}
+	if files.Facts != "" {
+		structuredFile += fmt.Sprintf("- %s/%s - Facts with the number of providers that stated each\n", baseFolder, models.FactsFile)
+	}
+	if files.Disagreements != nil {
+		structuredFile += fmt.Sprintf("- %s/%s - Claims the providers disagree on\n", baseFolder, analysis.File)
+	}
+	structuredFile += fmt.Sprintf("- %s/CHANGELOG.md - Version history, with snapshots in history/\n", baseFolder)
+
 	prBody := fmt.Sprintf(`This PR adds a comprehensive persona package for: **%s**
 
 ## 📁 Structure
 This persona includes:
-- **Raw outputs** from all 4 AI providers (Claude, Gemini, Grok, GPT-4)%s
+- **Raw outputs** from %s%s
 - **Synthesized version** combining the best of all outputs
 
+## 🤖 Contributing Providers
+%s%s
 ## 📍 Files
 - %s/raw/ - Individual AI provider outputs
 - %s/synthesized.md - Full synthesized persona
-- %s/README.md - Documentation and overview
+%s- %s/README.md - Documentation and overview
 
 ## 🔗 Source
 %s
 
 ---
 *This is an automated PR created by [Studio](https://github.com/twin2ai/studio)*`,
-		personaName, includesUserPersona, baseFolder, baseFolder, baseFolder, sourceRef)
+		personaName, providerSummary, includesUserPersona, contributorTable(files), validationReport, baseFolder, baseFolder, structuredFile, baseFolder, sourceRef)
 
 	pr := &github.NewPullRequest{
 		Title: github.String(fmt.Sprintf("Add persona package: %s", personaName)),
@@ -191,9 +275,13 @@ This persona includes:
 	}
 
 	// Add labels to PR
+	labels := []string{"persona", "automated", "studio", "structured"}
+	if files.Evaluation != nil && files.Evaluation.Flagged {
+		labels = append(labels, NeedsReviewLabel)
+	}
 	_, _, err = c.client.Issues.AddLabelsToIssue(
 		ctx, c.personasOwner, c.personasRepo,
-		*pullRequest.Number, []string{"persona", "automated", "studio", "structured"})
+		*pullRequest.Number, labels)
 	if err != nil {
 		c.logger.Warnf("Failed to add labels to PR: %v", err)
 	}
@@ -203,13 +291,13 @@ This persona includes:
 	comment := fmt.Sprintf(`✅ Persona package generated successfully!
 
 📦 **Complete persona package created with:**
-- Raw outputs from all 4 AI providers (Claude, Gemini, Grok, GPT-4)
+- Raw outputs from %s
 - Synthesized full persona combining the best elements
 - Documentation and metadata
 
 View the generated persona package: %s
 
-The persona has been created in the [twin2ai/personas](https://github.com/twin2ai/personas) repository.`, prURL)
+The persona has been created in the [twin2ai/personas](https://github.com/twin2ai/personas) repository.`, providerSummary, prURL)
 
 	// Only comment on real issues (not batch processing which uses issue number 0)
 	if issueNumber > 0 {
@@ -224,8 +312,43 @@ The persona has been created in the [twin2ai/personas](https://github.com/twin2a
 	return pullRequest, nil
 }
 
+// describeProviders summarizes the providers that contributed raw outputs
+func describeProviders(rawOutputs []RawOutput) string {
+	names := make([]string, 0, len(rawOutputs))
+	for _, raw := range rawOutputs {
+		names = append(names, raw.Provider)
+	}
+	return fmt.Sprintf("%d AI providers (%s)", len(names), strings.Join(names, ", "))
+}
+
+// contributorTable lists the providers that contributed to the persona and those that failed
+func contributorTable(files PersonaFiles) string {
+	var table strings.Builder
+	table.WriteString("| Provider | Model | Status |\n|----------|-------|--------|\n")
+	for _, raw := range files.RawOutputs {
+		table.WriteString(fmt.Sprintf("| %s | %s | ✅ contributed |\n", raw.Provider, raw.Model))
+	}
+	for _, name := range files.FailedProviders {
+		table.WriteString(fmt.Sprintf("| %s | - | ❌ failed |\n", name))
+	}
+	if files.AssetStatus != nil && files.AssetStatus.Metadata["synthesizer"] != "" {
+		table.WriteString(fmt.Sprintf("\nSynthesized by **%s** (%s).\n",
+			files.AssetStatus.Metadata["synthesizer"], files.AssetStatus.Metadata["synthesizer_model"]))
+	}
+	return table.String()
+}
+
 // generatePersonaReadme creates a README file for the persona folder
-func (c *Client) generatePersonaReadme(personaName string, issueNumber int) string {
+func (c *Client) generatePersonaReadme(personaName string, issueNumber int, rawOutputs []RawOutput) string {
+	var rawList strings.Builder
+	for _, raw := range rawOutputs {
+		rawList.WriteString(fmt.Sprintf("- **%s.md** - %s's interpretation", raw.Provider, raw.Provider))
+		if raw.Model != "" {
+			rawList.WriteString(fmt.Sprintf(" (%s)", raw.Model))
+		}
+		rawList.WriteString("\n")
+	}
+
 	return fmt.Sprintf(`# %s Persona
 
 This folder contains a comprehensive persona package generated by Studio.
@@ -233,14 +356,12 @@ This folder contains a comprehensive persona package generated by Studio.
 ## Contents
 
 ### 📝 Raw Outputs (/raw)
-- **claude.md** - Claude Opus 4's interpretation
-- **gemini.md** - Gemini 2.5 Pro's interpretation
-- **grok.md** - Grok 2's interpretation
-- **gpt.md** - GPT-4 Turbo's interpretation
-- **user_supplied.md** - User-provided persona (if supplied)
+%s- **user_supplied.md** - User-provided persona (if supplied)
 
 ### 🎯 Main Files
 - **synthesized.md** - Full synthesized persona combining best elements from all AI providers
+- **CHANGELOG.md** - Every version of synthesized.md: what triggered it, the providers and models used, and what changed
+- **history/** - Snapshots of each version (v1.md, v2.md, ...)
 
 ## Asset Generation
 
@@ -273,7 +394,7 @@ Generated from issue #%d in the twin2ai/personas repository.
 
 ---
 *Created by [Studio](https://github.com/twin2ai/studio) - Multi-AI Persona Generation Pipeline*`,
-		personaName, generateAssetTriggerExamples(), issueNumber)
+		personaName, rawList.String(), generateAssetTriggerExamples(), issueNumber)
 }
 
 // generateAssetTriggerExamples creates example trigger markers for the README
@@ -297,8 +418,8 @@ func generateAssetTriggerExamples() string {
 <!-- GENERATE:api_endpoint -->`
 }
 
-// generateAssetStatusJSON creates JSON content for the asset status file
-func (c *Client) generateAssetStatusJSON(status *assets.AssetStatus) (string, error) {
+// AssetStatusJSON creates JSON content for the .assets_status.json file
+func (c *Client) AssetStatusJSON(status *assets.AssetStatus) (string, error) {
 	// Ensure timestamp is set
 	if status.LastSynthesizedUpdate.IsZero() {
 		status.LastSynthesizedUpdate = time.Now()
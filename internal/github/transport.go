@@ -0,0 +1,27 @@
+package github
+
+import (
+	"net/http"
+	"sync"
+)
+
+var (
+	transportMu sync.RWMutex
+	transport   http.RoundTripper
+)
+
+// SetTransport routes the API traffic of GitHub clients created after the
+// call through rt, e.g. to record writes in a dry run. Passing nil restores
+// the default transport.
+func SetTransport(rt http.RoundTripper) {
+	transportMu.Lock()
+	defer transportMu.Unlock()
+	transport = rt
+}
+
+// currentTransport returns the transport installed with SetTransport, if any
+func currentTransport() http.RoundTripper {
+	transportMu.RLock()
+	defer transportMu.RUnlock()
+	return transport
+}
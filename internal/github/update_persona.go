@@ -7,10 +7,13 @@ import (
 	"time"
 
 	"github.com/google/go-github/v57/github"
+
+	"github.com/twin2ai/studio/pkg/models"
 )
 
-// UpdatePersonaWithUserInput creates a PR to update an existing persona with user-provided content
-func (c *Client) UpdatePersonaWithUserInput(ctx context.Context, personaName string, existingPersona string, userPersona string, synthesizedPersona string) (*github.PullRequest, error) {
+// UpdatePersonaWithUserInput creates a PR to update an existing persona with user-provided content.
+// synthesizer and model name the provider that merged the two versions.
+func (c *Client) UpdatePersonaWithUserInput(ctx context.Context, personaName string, existingPersona string, userPersona string, synthesizedPersona string, synthesizer, model string) (*github.PullRequest, error) {
 	// Create branch name for update
 	sanitizedName := strings.ToLower(strings.ReplaceAll(personaName, " ", "-"))
 	sanitizedName = strings.ReplaceAll(sanitizedName, "/", "-")
@@ -58,6 +61,13 @@ func (c *Client) UpdatePersonaWithUserInput(ctx context.Context, personaName str
 	if err != nil {
 		// Fallback to flat structure
 		filePath = fmt.Sprintf("personas/%s.md", fileName)
+	} else {
+		// Snapshot the update into history/ and the changelog
+		version := models.Version{Trigger: models.TriggerUpdate,
+			Provenance: models.Provenance{Synthesizer: synthesizer, SynthesizerModel: model}}
+		if _, err := c.RecordVersion(ctx, fileName, branchName, personaName, synthesizedPersona, version); err != nil {
+			c.logger.Warnf("Failed to record version history: %v", err)
+		}
 	}
 
 	// Update the file
@@ -95,16 +105,17 @@ User-provided persona synthesized with existing version
 
 ## 🔄 Changes
 - Synthesized user-provided content with existing persona
-- Updated using Gemini synthesis with temperature 0.3
+- Synthesized by %s
+- Previous version kept in history/ and recorded in CHANGELOG.md
 
 ## 💡 Process
 1. Existing persona retrieved from repository
 2. User-provided persona submitted for synthesis
-3. Gemini combined both versions into improved synthesis
+3. The synthesizer combined both versions into improved synthesis
 4. Only the synthesized version is updated (no raw files or adaptations)
 
 ---
-*This is an automated PR created by [Studio](https://github.com/twin2ai/studio)*`, personaName)
+*This is an automated PR created by [Studio](https://github.com/twin2ai/studio)*`, personaName, synthesizer)
 
 	pr := &github.NewPullRequest{
 		Title: github.String(fmt.Sprintf("Update persona: %s", personaName)),
@@ -0,0 +1,344 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// BreakerState is the state of a provider's circuit breaker
+type BreakerState string
+
+const (
+	BreakerClosed   BreakerState = "closed"    // Requests flow normally
+	BreakerOpen     BreakerState = "open"      // Requests fail fast until the cool-down ends
+	BreakerHalfOpen BreakerState = "half-open" // One probe request decides whether to close
+)
+
+// BreakerPolicy decides when a breaker opens and how long it stays open
+type BreakerPolicy struct {
+	FailureThreshold int           // Consecutive failed calls that open the breaker; 0 disables it
+	Cooldown         time.Duration // Time an open breaker waits before letting a probe through
+}
+
+// DefaultBreakerPolicy opens after three failed calls and probes again after five minutes
+func DefaultBreakerPolicy() BreakerPolicy {
+	return BreakerPolicy{
+		FailureThreshold: 3,
+		Cooldown:         5 * time.Minute,
+	}
+}
+
+// CircuitOpenError is returned without contacting a provider whose breaker is open
+type CircuitOpenError struct {
+	Provider string
+	RetryAt  time.Time
+	LastErr  string
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit breaker open for %s until %s (last error: %s)",
+		e.Provider, e.RetryAt.Format(time.RFC3339), e.LastErr)
+}
+
+// BreakerStatus is a snapshot of one provider's breaker
+type BreakerStatus struct {
+	Provider  string
+	State     BreakerState
+	Failures  int       // Consecutive failed calls
+	OpenedAt  time.Time // Zero unless open or half-open
+	RetryAt   time.Time // When an open breaker lets the next probe through
+	LastError string
+}
+
+// Breaker tracks the health of one provider. Calls that fail with a
+// transient error (429, 5xx, timeouts, connection failures) count towards
+// opening it; any other outcome shows the provider is reachable.
+type Breaker struct {
+	name   string
+	logger *logrus.Logger
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	lastErr  string
+	probing  bool
+	file     stateFile // Where the breaker is persisted; empty when it is not
+}
+
+var (
+	breakersMu    sync.Mutex
+	breakers      = make(map[string]*Breaker)
+	breakerPolicy = DefaultBreakerPolicy()
+	breakerState  stateFile // Empty when breakers are not persisted
+)
+
+// breakerRecord is one provider's entry in the breaker state file
+type breakerRecord struct {
+	State     BreakerState `json:"state"`
+	Failures  int          `json:"failures"`
+	OpenedAt  time.Time    `json:"opened_at,omitempty"`
+	OpenUntil time.Time    `json:"open_until,omitempty"`
+	LastError string       `json:"last_error,omitempty"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+type skipBreakerKey struct{}
+
+// WithoutBreaker returns a context whose calls neither wait for nor update
+// the provider's breaker, for connection tests that only report on it
+func WithoutBreaker(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipBreakerKey{}, true)
+}
+
+func skipsBreaker(ctx context.Context) bool {
+	skip, _ := ctx.Value(skipBreakerKey{}).(bool)
+	return skip
+}
+
+// SetBreakerPolicy replaces the policy used by every provider's breaker
+func SetBreakerPolicy(policy BreakerPolicy) {
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+	breakerPolicy = policy
+}
+
+func currentBreakerPolicy() BreakerPolicy {
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+	return breakerPolicy
+}
+
+// SetBreakerStateFile persists every breaker to path, a JSON file shared by
+// all studio processes using the same data directory. Breakers created
+// afterwards start from the state recorded there, so a provider another
+// process found down stays skipped, and `studio providers status` can
+// report it. An empty path turns persistence off.
+func SetBreakerStateFile(path string) {
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+	breakerState = stateFile(path)
+}
+
+// ReadBreakerStates returns the breakers recorded in a state file, by provider
+func ReadBreakerStates(path string) (map[string]BreakerStatus, error) {
+	records := make(map[string]breakerRecord)
+	if err := stateFile(path).read(&records); err != nil {
+		return nil, err
+	}
+	statuses := make(map[string]BreakerStatus, len(records))
+	for name, r := range records {
+		statuses[name] = BreakerStatus{
+			Provider:  name,
+			State:     r.State,
+			Failures:  r.Failures,
+			OpenedAt:  r.OpenedAt,
+			RetryAt:   r.OpenUntil,
+			LastError: r.LastError,
+		}
+	}
+	return statuses, nil
+}
+
+// BreakerFor returns the process-wide breaker for a provider, creating it
+// on first use. Senders sharing a provider name share its breaker.
+func BreakerFor(name string, logger *logrus.Logger) *Breaker {
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+	b, ok := breakers[name]
+	if !ok {
+		b = &Breaker{name: name, logger: logger, state: BreakerClosed, file: breakerState}
+		b.load()
+		breakers[name] = b
+	}
+	return b
+}
+
+// load starts the breaker from its entry in the state file
+func (b *Breaker) load() {
+	if b.file == "" {
+		return
+	}
+	records := make(map[string]breakerRecord)
+	if err := b.file.read(&records); err != nil {
+		b.logger.Warnf("[%s] Ignoring breaker state: %v", b.name, err)
+		return
+	}
+	r, ok := records[b.name]
+	if !ok {
+		return
+	}
+	b.failures, b.openedAt, b.lastErr = r.Failures, r.OpenedAt, r.LastError
+	if r.State != BreakerClosed {
+		// A probe in flight in another process does not carry over
+		b.state = BreakerOpen
+		b.logger.Infof("[%s] Circuit breaker open since %s, as recorded by an earlier run", b.name, r.OpenedAt.Format(time.RFC3339))
+	}
+}
+
+// save records the breaker in the state file; the caller holds b.mu
+func (b *Breaker) save(policy BreakerPolicy) {
+	if b.file == "" {
+		return
+	}
+	record := breakerRecord{
+		State:     b.state,
+		Failures:  b.failures,
+		OpenedAt:  b.openedAt,
+		LastError: b.lastErr,
+		UpdatedAt: time.Now().UTC(),
+	}
+	if b.state != BreakerClosed {
+		record.OpenUntil = b.openedAt.Add(policy.Cooldown)
+	}
+	records := make(map[string]breakerRecord)
+	if err := b.file.update(&records, func() bool {
+		records[b.name] = record
+		return true
+	}); err != nil {
+		b.logger.Warnf("[%s] Failed to save breaker state: %v", b.name, err)
+	}
+}
+
+// Breakers returns the status of every breaker, sorted by provider name
+func Breakers() []BreakerStatus {
+	breakersMu.Lock()
+	list := make([]*Breaker, 0, len(breakers))
+	for _, b := range breakers {
+		list = append(list, b)
+	}
+	breakersMu.Unlock()
+
+	statuses := make([]BreakerStatus, 0, len(list))
+	for _, b := range list {
+		statuses = append(statuses, b.Status())
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Provider < statuses[j].Provider })
+	return statuses
+}
+
+// Status returns a snapshot of the breaker
+func (b *Breaker) Status() BreakerStatus {
+	policy := currentBreakerPolicy()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := BreakerStatus{
+		Provider:  b.name,
+		State:     b.state,
+		Failures:  b.failures,
+		OpenedAt:  b.openedAt,
+		LastError: b.lastErr,
+	}
+	if b.state != BreakerClosed {
+		status.RetryAt = b.openedAt.Add(policy.Cooldown)
+	}
+	return status
+}
+
+// ProbeDue reports whether the breaker is open and its cool-down has ended
+func (b *Breaker) ProbeDue() bool {
+	policy := currentBreakerPolicy()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state == BreakerOpen && !time.Now().Before(b.openedAt.Add(policy.Cooldown))
+}
+
+// Allow reports whether a call may go out. Once the cool-down of an open
+// breaker has ended, a single probe call is let through.
+func (b *Breaker) Allow() error {
+	policy := currentBreakerPolicy()
+	if policy.FailureThreshold <= 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	retryAt := b.openedAt.Add(policy.Cooldown)
+	switch b.state {
+	case BreakerOpen:
+		if time.Now().Before(retryAt) {
+			return &CircuitOpenError{Provider: b.name, RetryAt: retryAt, LastErr: b.lastErr}
+		}
+		b.state = BreakerHalfOpen
+		b.probing = true
+		b.logger.Infof("[%s] Circuit breaker half-open, probing provider", b.name)
+		return nil
+	case BreakerHalfOpen:
+		if b.probing {
+			return &CircuitOpenError{Provider: b.name, RetryAt: retryAt, LastErr: b.lastErr}
+		}
+		b.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// Record updates the breaker with the outcome of a call that Allow let through.
+// ctx is the caller's context, so a call it cancelled is not held against the provider.
+func (b *Breaker) Record(ctx context.Context, err error) {
+	policy := currentBreakerPolicy()
+	if policy.FailureThreshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+
+	// A call abandoned by the caller says nothing about the provider
+	if err != nil && ctx.Err() != nil {
+		if b.state == BreakerHalfOpen {
+			b.state = BreakerOpen
+		}
+		return
+	}
+
+	if !isOutage(err) {
+		changed := b.state != BreakerClosed || b.failures > 0
+		if b.state != BreakerClosed {
+			b.logger.Infof("[%s] Circuit breaker closed, provider is responding again", b.name)
+		}
+		b.state = BreakerClosed
+		b.failures = 0
+		b.openedAt = time.Time{}
+		if changed {
+			b.save(policy)
+		}
+		return
+	}
+
+	b.failures++
+	b.lastErr = err.Error()
+	if b.state == BreakerHalfOpen || b.failures >= policy.FailureThreshold {
+		if b.state != BreakerOpen {
+			b.logger.Warnf("[%s] Circuit breaker open after %d failed calls; skipping provider for %v (last error: %v)",
+				b.name, b.failures, policy.Cooldown, err)
+		}
+		b.state = BreakerOpen
+		b.openedAt = time.Now()
+	}
+	b.save(policy)
+}
+
+// isOutage reports whether an error suggests the provider is unavailable:
+// transient failures and request timeouts, as opposed to rejected requests
+func isOutage(err error) bool {
+	if err == nil {
+		return false
+	}
+	if IsRetryable(err) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
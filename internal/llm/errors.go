@@ -0,0 +1,120 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// APIError is returned when a provider answers with a non-200 status
+type APIError struct {
+	StatusCode int
+	Body       string
+	RetryAfter time.Duration // Parsed Retry-After header, zero if absent
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API request failed with status %d: %s", e.StatusCode, e.Body)
+}
+
+// Retryable reports whether the request may succeed if sent again
+func (e *APIError) Retryable() bool {
+	switch {
+	case e.StatusCode == http.StatusTooManyRequests,
+		e.StatusCode == http.StatusRequestTimeout,
+		e.StatusCode >= 500: // includes Anthropic's 529 overloaded
+		return true
+	case strings.Contains(strings.ToLower(e.Body), "overloaded"):
+		return true
+	default:
+		// 400, 401, 403, 404, ... will fail the same way every time
+		return false
+	}
+}
+
+// SafetyError reports content blocked by a provider's safety filter.
+// Resending the same prompt produces the same result, so it is permanent.
+type SafetyError struct {
+	Reason string
+}
+
+func (e *SafetyError) Error() string {
+	return fmt.Sprintf("content blocked by safety filter: %s", e.Reason)
+}
+
+// IsRetryable classifies an error from a provider call as transient or permanent
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	// http.Client reports its own timeout as context.DeadlineExceeded too: a
+	// provider that used the whole per-attempt timeout is likely to do so
+	// again, so timeouts are not retried
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var safetyErr *SafetyError
+	if errors.As(err, &safetyErr) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Retryable()
+	}
+
+	if errors.Is(err, ErrStreamIdle) {
+		return true
+	}
+
+	// A host that does not resolve will not start resolving on a retry
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return false
+	}
+
+	// Transport failures: connection reset/refused, timeouts, truncated bodies
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return true
+	}
+
+	return strings.Contains(strings.ToLower(err.Error()), "connection reset")
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	var seconds int
+	if _, err := fmt.Sscanf(value, "%d", &seconds); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if when, err := http.ParseTime(value); err == nil {
+		if d := when.Sub(now); d > 0 {
+			return d
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,59 @@
+package llm
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Call describes one completed provider call
+type Call struct {
+	Provider string
+	Model    string
+	Usage    Usage
+	Latency  time.Duration
+	Err      error
+}
+
+// Observer is notified after every provider call, e.g. to record token usage
+type Observer func(ctx context.Context, call Call)
+
+var (
+	observersMu sync.RWMutex
+	observers   []Observer
+)
+
+// AddObserver registers a process-wide observer for all provider calls
+func AddObserver(observer Observer) {
+	observersMu.Lock()
+	defer observersMu.Unlock()
+	observers = append(observers, observer)
+}
+
+// Observe reports a finished call made through this sender to all observers.
+// Clients call it once per request after the response has been parsed, since
+// only they know the reported token counts.
+func (s *Sender) Observe(ctx context.Context, model string, result *Result, started time.Time, err error) {
+	call := Call{
+		Provider: s.name,
+		Model:    model,
+		Latency:  time.Since(started),
+		Err:      err,
+	}
+	if result != nil {
+		call.Usage = result.Usage
+		if result.Model != "" {
+			call.Model = result.Model
+		}
+		// Output tokens count towards the rate limit once they are known
+		if l := limiterFor(s.name); l != nil {
+			l.charge(result.Usage.OutputTokens, s.logger)
+		}
+	}
+
+	observersMu.RLock()
+	defer observersMu.RUnlock()
+	for _, observer := range observers {
+		observer(ctx, call)
+	}
+}
@@ -0,0 +1,77 @@
+package llm
+
+// Stage identifies the pipeline step a request belongs to
+type Stage string
+
+const (
+	StageGeneration Stage = "generation"
+	StageSynthesis  Stage = "synthesis"
+	StageFeedback   Stage = "feedback"
+	StagePrompt     Stage = "prompt"
+	StageEvaluation Stage = "evaluation"
+	StageAnalysis   Stage = "analysis"
+)
+
+// Stages lists every stage that can carry its own parameters
+var Stages = []Stage{StageGeneration, StageSynthesis, StageFeedback, StagePrompt, StageEvaluation, StageAnalysis}
+
+// Params are the generation settings sent with a request. Nil and zero
+// fields leave the client's default in place.
+type Params struct {
+	Temperature    *float64
+	TopP           *float64
+	MaxTokens      int
+	Seed           *int
+	StopSequences  []string
+	ThinkingBudget int // Extended thinking tokens (Claude, Gemini 2.5); 0 disables thinking
+}
+
+// Merge returns p with every field that is set in override replaced
+func (p Params) Merge(override Params) Params {
+	if override.Temperature != nil {
+		p.Temperature = override.Temperature
+	}
+	if override.TopP != nil {
+		p.TopP = override.TopP
+	}
+	if override.MaxTokens > 0 {
+		p.MaxTokens = override.MaxTokens
+	}
+	if override.Seed != nil {
+		p.Seed = override.Seed
+	}
+	if len(override.StopSequences) > 0 {
+		p.StopSequences = override.StopSequences
+	}
+	if override.ThinkingBudget > 0 {
+		p.ThinkingBudget = override.ThinkingBudget
+	}
+	return p
+}
+
+// ParamSet holds parameters per stage
+type ParamSet map[Stage]Params
+
+// For returns the parameters for stage. Stages without an entry, such as
+// feedback regeneration, use the generation parameters.
+func (s ParamSet) For(stage Stage) Params {
+	if p, ok := s[stage]; ok {
+		return p
+	}
+	return s[StageGeneration]
+}
+
+// Resolve layers the overrides for stage on top of the defaults for stage
+func (s ParamSet) Resolve(stage Stage, overrides ParamSet) Params {
+	return s.For(stage).Merge(overrides.For(stage))
+}
+
+// Float returns a pointer to v, for optional Params fields
+func Float(v float64) *float64 {
+	return &v
+}
+
+// Int returns a pointer to v, for optional Params fields
+func Int(v int) *int {
+	return &v
+}
@@ -0,0 +1,231 @@
+package llm
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RateLimit caps how fast calls are sent to one provider. Zero disables a limit.
+type RateLimit struct {
+	RequestsPerMinute int
+	TokensPerMinute   int // Estimated prompt tokens plus reported output tokens
+}
+
+// limiter is a pair of token buckets, one for requests and one for tokens.
+// Callers reserve capacity up front and wait out any deficit, so concurrent
+// callers queue in arrival order. With a state file, the bucket levels are
+// kept there and every process using it draws from the same buckets.
+type limiter struct {
+	provider string
+	limit    RateLimit
+	file     stateFile
+	mu       sync.Mutex
+	requests *bucket
+	tokens   *bucket
+}
+
+// limiterRecord is one provider's entry in the rate limit state file
+type limiterRecord struct {
+	Requests *bucketRecord `json:"requests,omitempty"`
+	Tokens   *bucketRecord `json:"tokens,omitempty"`
+}
+
+// bucketRecord is a bucket's level at a point in time
+type bucketRecord struct {
+	Level float64   `json:"level"`
+	Last  time.Time `json:"last"`
+}
+
+// bucket refills at rate units per second up to capacity
+type bucket struct {
+	capacity float64
+	rate     float64
+	level    float64
+	last     time.Time
+}
+
+var (
+	limitersMu     sync.Mutex
+	limiters       = make(map[string]*limiter)
+	rateLimitState stateFile // Empty when the buckets are local to the process
+)
+
+// SetRateLimitStateFile keeps the rate limit buckets in path, a JSON file
+// shared by all studio processes using the same data directory, so the
+// poller and a concurrent `studio batch` run stay within one quota. An
+// empty path keeps the buckets in the process.
+func SetRateLimitStateFile(path string) {
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+	rateLimitState = stateFile(path)
+	for _, l := range limiters {
+		l.mu.Lock()
+		l.file = rateLimitState
+		l.mu.Unlock()
+	}
+}
+
+// SetRateLimit sets the rate limit for a provider. Every Sender with that
+// provider name shares it, so the pipeline, synthesis and prompt generation
+// draw from the same quota; with SetRateLimitStateFile, so do other
+// processes.
+func SetRateLimit(provider string, limit RateLimit) {
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+
+	if limit.RequestsPerMinute <= 0 && limit.TokensPerMinute <= 0 {
+		delete(limiters, provider)
+		return
+	}
+
+	// Keep the current buckets when a provider is registered again unchanged
+	if existing, ok := limiters[provider]; ok && existing.limit == limit {
+		return
+	}
+
+	l := &limiter{
+		provider: provider,
+		limit:    limit,
+		file:     rateLimitState,
+		requests: newBucket(limit.RequestsPerMinute),
+		tokens:   newBucket(limit.TokensPerMinute),
+	}
+	limiters[provider] = l
+}
+
+func limiterFor(provider string) *limiter {
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+	return limiters[provider]
+}
+
+// newBucket creates a full bucket allowing perMinute units per minute, or nil for no limit
+func newBucket(perMinute int) *bucket {
+	if perMinute <= 0 {
+		return nil
+	}
+	return &bucket{
+		capacity: float64(perMinute),
+		rate:     float64(perMinute) / 60,
+		level:    float64(perMinute),
+		last:     time.Now(),
+	}
+}
+
+// reserve takes n units and returns how long the caller must wait for them
+func (b *bucket) reserve(n float64, now time.Time) time.Duration {
+	if b == nil {
+		return 0
+	}
+	b.level += now.Sub(b.last).Seconds() * b.rate
+	if b.level > b.capacity {
+		b.level = b.capacity
+	}
+	b.last = now
+
+	b.level -= n
+	if b.level >= 0 {
+		return 0
+	}
+	return time.Duration(-b.level / b.rate * float64(time.Second))
+}
+
+// restore sets the bucket to a level recorded by any process
+func (b *bucket) restore(r *bucketRecord) {
+	if b == nil || r == nil {
+		return
+	}
+	b.level = r.Level
+	if b.level > b.capacity {
+		b.level = b.capacity
+	}
+	b.last = r.Last
+}
+
+// record returns the bucket's level for the state file
+func (b *bucket) record() *bucketRecord {
+	if b == nil {
+		return nil
+	}
+	return &bucketRecord{Level: b.level, Last: b.last}
+}
+
+// take reserves requests and tokens and returns how long the caller must
+// wait for them. With a state file, the buckets are read from it and
+// written back under its lock; if the file cannot be used, the process's
+// own buckets are.
+func (l *limiter) take(requests, tokens float64, logger *logrus.Logger) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	reserve := func() time.Duration {
+		now := time.Now()
+		delay := l.requests.reserve(requests, now)
+		if tokenDelay := l.tokens.reserve(tokens, now); tokenDelay > delay {
+			delay = tokenDelay
+		}
+		return delay
+	}
+	if l.file == "" {
+		return reserve()
+	}
+
+	var delay time.Duration
+	taken := false
+	records := make(map[string]limiterRecord)
+	err := l.file.update(&records, func() bool {
+		record := records[l.provider]
+		l.requests.restore(record.Requests)
+		l.tokens.restore(record.Tokens)
+		delay, taken = reserve(), true
+		records[l.provider] = limiterRecord{Requests: l.requests.record(), Tokens: l.tokens.record()}
+		return true
+	})
+	if err != nil {
+		logger.Warnf("[%s] Using this process's rate limit only: %v", l.provider, err)
+		if !taken {
+			delay = reserve()
+		}
+	}
+	return delay
+}
+
+// wait reserves one request and the given tokens, then blocks until both are available
+func (l *limiter) wait(ctx context.Context, name string, tokens int, logger *logrus.Logger) error {
+	delay := l.take(1, float64(tokens), logger)
+	if delay <= 0 {
+		return nil
+	}
+	if delay >= time.Second {
+		logger.Infof("[%s] Rate limit reached, waiting %v", name, delay.Round(100*time.Millisecond))
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// charge takes tokens that were only known after a call, such as its output
+func (l *limiter) charge(tokens int, logger *logrus.Logger) {
+	if l.tokens == nil || tokens <= 0 {
+		return
+	}
+	l.take(0, float64(tokens), logger)
+}
+
+// estimateRequestTokens approximates the prompt tokens of a request body
+// (about four bytes per token)
+func estimateRequestTokens(contentLength int64) int {
+	if contentLength <= 0 {
+		return 0
+	}
+	return int(contentLength / 4)
+}
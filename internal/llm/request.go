@@ -0,0 +1,57 @@
+package llm
+
+import "encoding/json"
+
+// Role identifies who wrote a conversation message
+type Role string
+
+const (
+	RoleUser      Role = "user"
+	RoleAssistant Role = "assistant"
+)
+
+// Message is one turn of a conversation
+type Message struct {
+	Role    Role
+	Content string
+}
+
+// Request is a provider-neutral conversation: an optional system prompt
+// followed by the messages in order. Each client maps it onto its own API
+// (Anthropic's system field, Gemini's systemInstruction, the OpenAI system
+// role).
+type Request struct {
+	System   string
+	Messages []Message
+
+	// Schema, if set, asks for a JSON reply matching this JSON Schema using
+	// the provider's JSON or structured output mode. The prompt should still
+	// describe the expected document, as not every mode enforces the schema.
+	Schema json.RawMessage
+}
+
+// Prompt returns a request holding a single user message
+func Prompt(text string) Request {
+	return Request{Messages: []Message{{Role: RoleUser, Content: text}}}
+}
+
+// User appends a user message to the conversation
+func (r Request) User(content string) Request {
+	r.Messages = append(r.Messages[:len(r.Messages):len(r.Messages)], Message{Role: RoleUser, Content: content})
+	return r
+}
+
+// Assistant appends an assistant message to the conversation
+func (r Request) Assistant(content string) Request {
+	r.Messages = append(r.Messages[:len(r.Messages):len(r.Messages)], Message{Role: RoleAssistant, Content: content})
+	return r
+}
+
+// Len returns the number of characters in the system prompt and messages
+func (r Request) Len() int {
+	n := len(r.System)
+	for _, m := range r.Messages {
+		n += len(m.Content)
+	}
+	return n
+}
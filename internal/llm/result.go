@@ -0,0 +1,42 @@
+package llm
+
+// StopReason is a provider-independent reason for why generation ended
+type StopReason string
+
+const (
+	StopEnd       StopReason = "end"           // Model finished on its own
+	StopMaxTokens StopReason = "max_tokens"    // Output hit the token limit and is cut off
+	StopSequence  StopReason = "stop_sequence" // A configured stop sequence was produced
+	StopSafety    StopReason = "safety"        // Output was blocked or cut by a safety filter
+	StopInterrupt StopReason = "interrupted"   // Stream broke off after delivering partial output
+	StopUnknown   StopReason = "unknown"       // Provider reported something we do not map
+)
+
+// Truncated reports whether the output was cut off and can be continued
+func (r StopReason) Truncated() bool {
+	return r == StopMaxTokens || r == StopInterrupt
+}
+
+// Usage holds the token counts reported by a provider
+type Usage struct {
+	InputTokens  int
+	OutputTokens int
+}
+
+// Add accumulates another call's usage, e.g. for continuation requests
+func (u *Usage) Add(other Usage) {
+	u.InputTokens += other.InputTokens
+	u.OutputTokens += other.OutputTokens
+}
+
+// Result is the outcome of a single generation call
+type Result struct {
+	Text       string
+	StopReason StopReason
+	Usage      Usage
+	Model      string
+
+	// Thinking holds the model's reasoning when extended thinking is enabled
+	// and the provider returns it; it is not part of Text
+	Thinking string
+}
@@ -0,0 +1,137 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy controls how often and how long a Sender waits between attempts
+type RetryPolicy struct {
+	MaxRetries    int           // Retries after the first attempt; 0 disables retrying
+	BaseDelay     time.Duration // Delay before the first retry
+	MaxDelay      time.Duration // Upper bound for the computed backoff
+	MaxRetryAfter time.Duration // Upper bound for server-requested Retry-After delays
+	MaxElapsed    time.Duration // No retry starts after this much time; 0 means no limit
+}
+
+// DefaultRetryPolicy returns the policy used when a provider configures nothing
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries:    3,
+		BaseDelay:     2 * time.Second,
+		MaxDelay:      60 * time.Second,
+		MaxRetryAfter: 5 * time.Minute,
+		MaxElapsed:    10 * time.Minute,
+	}
+}
+
+// Backoff returns the wait before retry number attempt (starting at 1).
+// It grows exponentially with equal jitter and honours Retry-After when the
+// server asks for a longer pause.
+func (p RetryPolicy) Backoff(attempt int, retryAfter time.Duration) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	// Equal jitter: half fixed, half random, so parallel callers spread out
+	if half := int64(delay / 2); half > 0 {
+		delay = time.Duration(half + rand.Int63n(half+1))
+	}
+
+	if retryAfter > delay {
+		delay = retryAfter
+		if p.MaxRetryAfter > 0 && delay > p.MaxRetryAfter {
+			delay = p.MaxRetryAfter
+		}
+	}
+
+	return delay
+}
+
+// exhausted reports whether waiting delay before the next attempt would
+// start it after the overall retry deadline
+func (p RetryPolicy) exhausted(elapsed, delay time.Duration) bool {
+	return p.MaxElapsed > 0 && elapsed+delay > p.MaxElapsed
+}
+
+// guard runs call under the provider's circuit breaker: it fails fast with
+// *CircuitOpenError while the breaker is open, unless ctx comes from
+// WithoutBreaker, and records the outcome
+func (s *Sender) guard(ctx context.Context, call func() error) error {
+	if skipsBreaker(ctx) {
+		return call()
+	}
+	breaker := BreakerFor(s.name, s.logger)
+	if err := breaker.Allow(); err != nil {
+		return err
+	}
+	err := call()
+	breaker.Record(ctx, err)
+	return err
+}
+
+// retry runs attempt until it succeeds or fails permanently, backing off
+// between transient failures, honouring Retry-After and giving up after
+// MaxRetries retries or once a retry would start past MaxElapsed
+func (s *Sender) retry(ctx context.Context, attempt func(ctx context.Context) error) error {
+	attempts := s.policy.MaxRetries + 1
+	start := time.Now()
+
+	var lastErr error
+	for n := 1; n <= attempts; n++ {
+		err := attempt(ctx)
+		if err == nil {
+			if n > 1 {
+				s.logger.Infof("[%s] Request succeeded on attempt %d/%d", s.name, n, attempts)
+			}
+			return nil
+		}
+		lastErr = err
+
+		if ctx.Err() != nil {
+			return err
+		}
+
+		if !IsRetryable(err) {
+			s.logger.Debugf("[%s] Attempt %d/%d failed with permanent error: %v", s.name, n, attempts, err)
+			return err
+		}
+
+		if n == attempts {
+			break
+		}
+
+		var retryAfter time.Duration
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			retryAfter = apiErr.RetryAfter
+		}
+
+		delay := s.policy.Backoff(n, retryAfter)
+		if elapsed := time.Since(start); s.policy.exhausted(elapsed, delay) {
+			s.logger.Warnf("[%s] Attempt %d/%d failed: %v; not retrying after %v", s.name, n, attempts, err, elapsed.Round(time.Second))
+			return fmt.Errorf("giving up after %d attempts in %v: %w", n, elapsed.Round(time.Second), err)
+		}
+		s.logger.Warnf("[%s] Attempt %d/%d failed: %v; retrying in %v", s.name, n, attempts, err, delay.Round(time.Millisecond))
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	if attempts > 1 {
+		return fmt.Errorf("giving up after %d attempts: %w", attempts, lastErr)
+	}
+	return lastErr
+}
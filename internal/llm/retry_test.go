@@ -0,0 +1,169 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestBackoffBounds(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: time.Second, MaxDelay: 8 * time.Second, MaxRetryAfter: time.Minute}
+
+	tests := []struct {
+		attempt    int
+		retryAfter time.Duration
+		min, max   time.Duration
+	}{
+		{attempt: 1, min: 500 * time.Millisecond, max: time.Second},
+		{attempt: 2, min: time.Second, max: 2 * time.Second},
+		{attempt: 3, min: 2 * time.Second, max: 4 * time.Second},
+		{attempt: 4, min: 4 * time.Second, max: 8 * time.Second},
+		{attempt: 10, min: 4 * time.Second, max: 8 * time.Second}, // Capped at MaxDelay
+		{attempt: 1, retryAfter: 30 * time.Second, min: 30 * time.Second, max: 30 * time.Second},
+		{attempt: 1, retryAfter: 5 * time.Minute, min: time.Minute, max: time.Minute}, // Capped at MaxRetryAfter
+		{attempt: 4, retryAfter: 100 * time.Millisecond, min: 4 * time.Second, max: 8 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("attempt %d retry-after %v", tt.attempt, tt.retryAfter), func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				delay := policy.Backoff(tt.attempt, tt.retryAfter)
+				if delay < tt.min || delay > tt.max {
+					t.Fatalf("Backoff() = %v, want between %v and %v", delay, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestBackoffJitter(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: time.Second, MaxDelay: time.Minute}
+
+	seen := make(map[time.Duration]bool)
+	for i := 0; i < 100; i++ {
+		seen[policy.Backoff(3, 0)] = true
+	}
+	if len(seen) < 10 {
+		t.Errorf("Backoff() gave %d distinct delays in 100 calls, want jitter", len(seen))
+	}
+
+	if delay := (RetryPolicy{}).Backoff(1, 0); delay != 0 {
+		t.Errorf("Backoff() with no base delay = %v, want 0", delay)
+	}
+}
+
+func TestRetryPolicyExhausted(t *testing.T) {
+	policy := RetryPolicy{MaxElapsed: time.Minute}
+	if policy.exhausted(30*time.Second, 20*time.Second) {
+		t.Error("exhausted() = true before the deadline")
+	}
+	if !policy.exhausted(50*time.Second, 20*time.Second) {
+		t.Error("exhausted() = false for a retry past the deadline")
+	}
+	if (RetryPolicy{}).exhausted(time.Hour, time.Hour) {
+		t.Error("exhausted() = true without MaxElapsed")
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"rate limited", &APIError{StatusCode: http.StatusTooManyRequests}, true},
+		{"server error", &APIError{StatusCode: http.StatusBadGateway}, true},
+		{"overloaded", &APIError{StatusCode: http.StatusBadRequest, Body: `{"error":"Overloaded"}`}, true},
+		{"bad request", &APIError{StatusCode: http.StatusBadRequest}, false},
+		{"unauthorized", &APIError{StatusCode: http.StatusUnauthorized}, false},
+		{"safety", &SafetyError{Reason: "SAFETY"}, false},
+		{"canceled", fmt.Errorf("failed to send request: %w", context.Canceled), false},
+		{"deadline", fmt.Errorf("failed to send request: %w", context.DeadlineExceeded), false},
+		{"connection reset", fmt.Errorf("failed to send request: %w", syscall.ECONNRESET), true},
+		{"unknown host", fmt.Errorf("failed to send request: %w", &net.OpError{Op: "dial", Err: &net.DNSError{Err: "no such host", Name: "api.example.invalid", IsNotFound: true}}), false},
+		{"DNS timeout", fmt.Errorf("failed to send request: %w", &net.OpError{Op: "dial", Err: &net.DNSError{Err: "i/o timeout", Name: "api.example.com", IsTimeout: true}}), true},
+		{"truncated body", fmt.Errorf("failed to read response body: %w", io.ErrUnexpectedEOF), true},
+		{"stream idle", fmt.Errorf("failed to read stream: %w", ErrStreamIdle), true},
+		{"other", errors.New("invalid JSON"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.want {
+				t.Errorf("IsRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSenderDoesNotRetryClientTimeout(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	sender := newTestSender(50 * time.Millisecond)
+	_, err := sender.do(context.Background(), func() (*http.Request, error) {
+		return http.NewRequest(http.MethodPost, server.URL, nil)
+	})
+	if err == nil {
+		t.Fatal("do() succeeded, want a timeout")
+	}
+	if IsRetryable(err) {
+		t.Errorf("IsRetryable(%v) = true for a client timeout", err)
+	}
+	if n := hits.Load(); n != 1 {
+		t.Errorf("server saw %d requests, want 1", n)
+	}
+}
+
+func TestSenderStopsRetryingAtMaxElapsed(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	sender := newTestSender(time.Second)
+	sender.SetRetryPolicy(RetryPolicy{
+		MaxRetries: 10,
+		BaseDelay:  40 * time.Millisecond,
+		MaxDelay:   40 * time.Millisecond,
+		MaxElapsed: 100 * time.Millisecond,
+	})
+	_, err := sender.do(context.Background(), func() (*http.Request, error) {
+		return http.NewRequest(http.MethodPost, server.URL, nil)
+	})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("do() error = %v, want the last *APIError", err)
+	}
+	if n := hits.Load(); n < 2 || n > 5 {
+		t.Errorf("server saw %d requests, want retries to stop after about 100ms", n)
+	}
+}
+
+func newTestSender(timeout time.Duration) *Sender {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	sender := NewSender("retry-test", timeout, logger)
+	sender.SetRetryPolicy(RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+	return sender
+}
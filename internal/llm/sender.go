@@ -0,0 +1,144 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Sender is the single place where AI clients perform HTTP calls. It applies
+// the retry policy and turns non-200 responses into *APIError.
+type Sender struct {
+	name         string
+	httpClient   *http.Client
+	streamClient *http.Client // No overall timeout; streams use idleTimeout instead
+	idleTimeout  time.Duration
+	policy       RetryPolicy
+	logger       *logrus.Logger
+}
+
+// NewSender creates a sender for the named provider with the default retry policy
+func NewSender(name string, timeout time.Duration, logger *logrus.Logger) *Sender {
+	return &Sender{
+		name: name,
+		httpClient: &http.Client{
+			Timeout:   timeout,
+			Transport: sharedTransport{},
+		},
+		streamClient: &http.Client{
+			Transport: sharedTransport{},
+		},
+		idleTimeout: DefaultStreamIdleTimeout,
+		policy:      DefaultRetryPolicy(),
+		logger:      logger,
+	}
+}
+
+// Name returns the provider name used in log messages
+func (s *Sender) Name() string {
+	return s.name
+}
+
+// SetName changes the provider name, e.g. when one client type is
+// registered more than once
+func (s *Sender) SetName(name string) {
+	s.name = name
+}
+
+// SetRetryPolicy replaces the retry policy
+func (s *Sender) SetRetryPolicy(policy RetryPolicy) {
+	s.policy = policy
+}
+
+// RetryPolicy returns the current retry policy
+func (s *Sender) RetryPolicy() RetryPolicy {
+	return s.policy
+}
+
+// Do sends the request built by newRequest and returns the body of a 200
+// response. newRequest is called once per attempt so the body can be re-read.
+// It fails fast with *CircuitOpenError while the provider's breaker is open,
+// unless ctx comes from WithoutBreaker.
+func (s *Sender) Do(ctx context.Context, newRequest func() (*http.Request, error)) ([]byte, error) {
+	var body []byte
+	err := s.guard(ctx, func() error {
+		var err error
+		body, err = s.do(ctx, newRequest)
+		return err
+	})
+	return body, err
+}
+
+// do sends the request, retrying transient failures
+func (s *Sender) do(ctx context.Context, newRequest func() (*http.Request, error)) ([]byte, error) {
+	var body []byte
+	err := s.retry(ctx, func(ctx context.Context) error {
+		var err error
+		body, err = s.attempt(ctx, newRequest)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// attempt performs a single HTTP round trip
+func (s *Sender) attempt(ctx context.Context, newRequest func() (*http.Request, error)) ([]byte, error) {
+	req, err := newRequest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if err := s.waitForRateLimit(ctx, req); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", redactURL(err))
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &APIError{
+			StatusCode: resp.StatusCode,
+			Body:       string(body),
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
+	}
+
+	return body, nil
+}
+
+// waitForRateLimit blocks until the provider's rate limit allows the request
+func (s *Sender) waitForRateLimit(ctx context.Context, req *http.Request) error {
+	l := limiterFor(s.name)
+	if l == nil {
+		return nil
+	}
+	return l.wait(ctx, s.name, estimateRequestTokens(req.ContentLength), s.logger)
+}
+
+// redactURL strips query parameters (Gemini passes its API key there) from
+// transport errors before they are logged or returned
+func redactURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		if u, parseErr := url.Parse(urlErr.URL); parseErr == nil && u.RawQuery != "" {
+			u.RawQuery = ""
+			urlErr.URL = u.String()
+		}
+	}
+	return err
+}
@@ -0,0 +1,97 @@
+package llm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Lock file timings: how long to wait for another process to finish its
+// update, and when a lock is old enough to have been left by a crash
+const (
+	stateLockTimeout = 5 * time.Second
+	stateLockStale   = 30 * time.Second
+)
+
+// stateFile is a JSON file shared by every studio process using the same
+// data directory, such as the poller and a concurrent batch run. Updates
+// hold a lock file so processes do not overwrite each other's changes.
+type stateFile string
+
+// read decodes the file into v; a missing file leaves v unchanged
+func (f stateFile) read(v interface{}) error {
+	data, err := os.ReadFile(string(f))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", f, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", f, err)
+	}
+	return nil
+}
+
+// update locks the file, decodes it into v, runs change and writes v back
+// if change reports that it modified it
+func (f stateFile) update(v interface{}, change func() bool) error {
+	if err := os.MkdirAll(filepath.Dir(string(f)), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", f, err)
+	}
+	unlock, err := f.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := f.read(v); err != nil {
+		return err
+	}
+	if !change() {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", f, err)
+	}
+	// Write a temporary file and rename it so readers never see a partial file
+	tmp := string(f) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", f, err)
+	}
+	if err := os.Rename(tmp, string(f)); err != nil {
+		return fmt.Errorf("failed to write %s: %w", f, err)
+	}
+	return nil
+}
+
+// lock creates the file's lock file, waiting while another process holds
+// it, and returns the function that releases it
+func (f stateFile) lock() (func(), error) {
+	path := string(f) + ".lock"
+	deadline := time.Now().Add(stateLockTimeout)
+	for {
+		lock, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			lock.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, fmt.Errorf("failed to lock %s: %w", f, err)
+		}
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > stateLockStale {
+			os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for lock %s", path)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
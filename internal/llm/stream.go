@@ -0,0 +1,118 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrStreamIdle is returned when a stream delivers no data within the idle timeout
+var ErrStreamIdle = errors.New("stream idle timeout")
+
+// DefaultStreamIdleTimeout is how long a stream may stay silent before it is aborted
+const DefaultStreamIdleTimeout = 2 * time.Minute
+
+// Stream sends the request built by newRequest and hands the body of a 200
+// response to handle. There is no overall timeout; instead the stream is
+// aborted when no data arrives within the idle timeout.
+//
+// Attempts are retried only while nothing has reached the caller: handle must
+// return an error only if it has not delivered any output yet. Once output
+// has been delivered, handle is responsible for reporting an interruption.
+// Like Do, it fails fast while the provider's breaker is open, unless ctx
+// comes from WithoutBreaker.
+func (s *Sender) Stream(ctx context.Context, newRequest func(ctx context.Context) (*http.Request, error), handle func(body io.Reader) error) error {
+	return s.guard(ctx, func() error {
+		return s.stream(ctx, newRequest, handle)
+	})
+}
+
+// stream sends the streaming request, retrying transient failures
+func (s *Sender) stream(ctx context.Context, newRequest func(ctx context.Context) (*http.Request, error), handle func(body io.Reader) error) error {
+	return s.retry(ctx, func(ctx context.Context) error {
+		return s.streamAttempt(ctx, newRequest, handle)
+	})
+}
+
+// streamAttempt performs a single streaming round trip
+func (s *Sender) streamAttempt(ctx context.Context, newRequest func(ctx context.Context) (*http.Request, error), handle func(body io.Reader) error) error {
+	attemptCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	req, err := newRequest(attemptCtx)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if err := s.waitForRateLimit(ctx, req); err != nil {
+		return err
+	}
+
+	resp, err := s.streamClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", redactURL(err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return &APIError{
+			StatusCode: resp.StatusCode,
+			Body:       string(body),
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
+	}
+
+	body := newIdleReader(resp.Body, s.idleTimeout, cancel)
+	defer body.stop()
+
+	return handle(body)
+}
+
+// SetStreamIdleTimeout changes how long a stream may stay silent
+func (s *Sender) SetStreamIdleTimeout(timeout time.Duration) {
+	s.idleTimeout = timeout
+}
+
+// idleReader cancels the request when no data arrives within the timeout
+type idleReader struct {
+	r       io.Reader
+	timeout time.Duration
+	timer   *time.Timer
+
+	mu    sync.Mutex
+	fired bool
+}
+
+func newIdleReader(r io.Reader, timeout time.Duration, cancel context.CancelFunc) *idleReader {
+	ir := &idleReader{r: r, timeout: timeout}
+	ir.timer = time.AfterFunc(timeout, func() {
+		ir.mu.Lock()
+		ir.fired = true
+		ir.mu.Unlock()
+		cancel()
+	})
+	return ir
+}
+
+func (ir *idleReader) Read(p []byte) (int, error) {
+	n, err := ir.r.Read(p)
+	if n > 0 {
+		ir.timer.Reset(ir.timeout)
+	}
+
+	ir.mu.Lock()
+	fired := ir.fired
+	ir.mu.Unlock()
+	if err != nil && fired {
+		return n, fmt.Errorf("no data for %v: %w", ir.timeout, ErrStreamIdle)
+	}
+	return n, err
+}
+
+func (ir *idleReader) stop() {
+	ir.timer.Stop()
+}
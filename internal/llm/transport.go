@@ -0,0 +1,30 @@
+package llm
+
+import (
+	"net/http"
+	"sync"
+)
+
+var (
+	transportMu sync.RWMutex
+	transport   http.RoundTripper = http.DefaultTransport
+)
+
+// SetTransport replaces the round tripper used by every Sender in the
+// process, e.g. to record and replay provider calls. Senders created before
+// the call pick it up too.
+func SetTransport(rt http.RoundTripper) {
+	transportMu.Lock()
+	defer transportMu.Unlock()
+	transport = rt
+}
+
+// sharedTransport forwards to the process-wide transport at request time
+type sharedTransport struct{}
+
+func (sharedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transportMu.RLock()
+	rt := transport
+	transportMu.RUnlock()
+	return rt.RoundTrip(req)
+}
@@ -0,0 +1,74 @@
+package multiprovider
+
+import (
+	"context"
+
+	"github.com/twin2ai/studio/internal/analysis"
+	"github.com/twin2ai/studio/internal/facts"
+	"github.com/twin2ai/studio/internal/usage"
+)
+
+// SetAnalyzer compares the claims of the raw outputs and synthesis before
+// the PR is opened; nil turns the disagreement report off
+func (g *Generator) SetAnalyzer(analyzer *analysis.Analyzer) {
+	g.analyzer = analyzer
+}
+
+// analyze finds the claims the raw outputs disagree on and keeps the report
+// with the other artifacts. It returns nil when no analyzer is set or when
+// fewer than two providers contributed.
+func (g *Generator) analyze(ctx context.Context, issueNumber int, personaName string, responses []ProviderResponse, combined *combination) *analysis.Report {
+	if g.analyzer == nil {
+		return nil
+	}
+
+	var raw []analysis.Source
+	for _, resp := range responses {
+		if resp.Error == nil && resp.Content != "" {
+			raw = append(raw, analysis.Source{Name: resp.Provider, Model: resp.Model, Content: resp.Content,
+				Claims: claimsFromFacts(combined.Facts, resp.Provider)})
+		}
+	}
+	if len(raw) < 2 {
+		return nil
+	}
+	synthesis := analysis.Source{Name: analysis.SynthesisSource, Model: combined.Model, Content: combined.Text}
+
+	report := g.analyzer.Analyze(usage.WithStage(ctx, usage.StageAnalysis), personaName, raw, synthesis)
+	if len(report.Disagreements) > 0 {
+		g.logger.Warnf("Providers disagree on %s: %s", personaName, report.Summary())
+	} else {
+		g.logger.Infof("Analyzed %s: %s", personaName, report.Summary())
+	}
+
+	if err := g.storeReport(issueNumber, "disagreements", report); err != nil {
+		g.logger.Warnf("Failed to store disagreement report: %v", err)
+	}
+	return report
+}
+
+// claimsFromFacts lists the facts a provider stated in the fact sheet, so
+// the analyzer compares them instead of extracting the provider's claims a
+// second time. It returns nil, leaving extraction to the analyzer, when
+// there is no sheet or the provider did not contribute to it.
+func claimsFromFacts(sheet *facts.Sheet, provider string) []analysis.Claim {
+	if sheet == nil || !contains(sheet.Providers, provider) {
+		return nil
+	}
+	claims := []analysis.Claim{}
+	for _, f := range sheet.Facts {
+		if contains(f.Sources, provider) {
+			claims = append(claims, analysis.Claim{Category: f.Category, Claim: f.Statement})
+		}
+	}
+	return claims
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package multiprovider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/twin2ai/studio/internal/completeness"
+)
+
+// SetCompleteness sets how incomplete syntheses are detected and repaired
+func (g *Generator) SetCompleteness(policy completeness.Policy) {
+	g.completeness = policy
+}
+
+// completer checks and repairs syntheses with the generator's chain,
+// linter and completeness policy
+func (g *Generator) completer() *completeness.Synthesizer {
+	return completeness.NewSynthesizer(g.synthesis, g.linter, g.completeness, g.logger)
+}
+
+// sources returns the successful responses a synthesis is built from
+func sources(responses []ProviderResponse) []completeness.Source {
+	var result []completeness.Source
+	for _, resp := range responses {
+		if resp.Error == nil {
+			result = append(result, completeness.Source{Name: resp.Provider, Content: resp.Content, StopReason: resp.StopReason})
+		}
+	}
+	return result
+}
+
+// usable reports whether a single raw output can be used as the persona
+// without a synthesis: it must not have stopped early or been cut off
+func (g *Generator) usable(resp ProviderResponse) bool {
+	problems := g.completer().Problems(completeness.Source{Name: resp.Provider, Content: resp.Content, StopReason: resp.StopReason})
+	if len(problems) > 0 {
+		g.logger.Warnf("Only response, from %s, is incomplete (%s); synthesizing from it instead of using it directly",
+			resp.Provider, strings.Join(problems, "; "))
+		return false
+	}
+	return true
+}
+
+// synthesize runs a combination prompt through the synthesis chain,
+// repairing an incomplete result, and records how it was produced
+func (g *Generator) synthesize(ctx context.Context, prompt, promptFile string, responses []ProviderResponse, sc completeness.Context) (*combination, error) {
+	result, err := g.completer().Synthesize(ctx, prompt, sources(responses), sc)
+	if err != nil {
+		return nil, err
+	}
+	if result.BySection {
+		return &combination{Synthesis: result.Synthesis, Method: methodBySection}, nil
+	}
+	return &combination{Synthesis: result.Synthesis, Method: methodSynthesized, Prompt: promptFile}, nil
+}
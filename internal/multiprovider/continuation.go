@@ -0,0 +1,98 @@
+package multiprovider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/twin2ai/studio/internal/llm"
+	"github.com/twin2ai/studio/internal/provider"
+)
+
+// maxOverlapScan bounds how much text is compared when looking for content
+// a continuation repeated from the end of the previous chunk
+const maxOverlapScan = 2000
+
+// minOverlap is the shortest repeated run treated as an overlap rather than
+// a coincidental match
+const minOverlap = 20
+
+// generateWithContinuation asks the provider for a persona and, while the
+// output stops on the token limit, requests continuations and stitches them
+// onto the text so far. Streamed continuations reach the partial artifact
+// with the same overlap trimmed. It returns the combined result and the
+// number of continuation requests made.
+func (g *Generator) generateWithContinuation(ctx context.Context, p provider.Provider, req provider.Request, partial *partialArtifact) (*llm.Result, int, error) {
+	result, err := p.Generate(ctx, req)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	text := result.Text
+	continuations := 0
+	for result.StopReason.Truncated() && continuations < g.maxContinuations {
+		continuations++
+		g.logger.Warnf("%s output truncated at %d characters, requesting continuation %d/%d",
+			p.Name(), len(text), continuations, g.maxContinuations)
+
+		req.Partial = text
+		partial.continueFrom(text)
+		next, err := p.Generate(ctx, req)
+		if err != nil {
+			// Keep what we have; the output is still marked truncated
+			g.logger.Warnf("%s continuation %d failed: %v", p.Name(), continuations, err)
+			break
+		}
+
+		result.Usage.Add(next.Usage)
+		result.StopReason = next.StopReason
+		if next.Thinking != "" {
+			result.Thinking = strings.TrimSpace(result.Thinking + "\n\n" + next.Thinking)
+		}
+		if strings.TrimSpace(next.Text) == "" {
+			g.logger.Warnf("%s continuation %d returned no text", p.Name(), continuations)
+			break
+		}
+		text = stitchContinuation(text, next.Text)
+	}
+
+	if result.StopReason.Truncated() {
+		g.logger.Warnf("%s output is still truncated after %d continuations (%d characters)",
+			p.Name(), continuations, len(text))
+	} else if continuations > 0 {
+		g.logger.Infof("%s output completed after %d continuations (%d characters)",
+			p.Name(), continuations, len(text))
+	}
+
+	result.Text = text
+	return result, continuations, nil
+}
+
+// stitchContinuation appends a continuation to the previous text. Models often
+// repeat the tail of what they already wrote before carrying on, so the
+// longest suffix of previous that prefixes next is dropped from next.
+func stitchContinuation(previous, next string) string {
+	limit := maxOverlapScan
+	if len(previous) < limit {
+		limit = len(previous)
+	}
+	if len(next) < limit {
+		limit = len(next)
+	}
+
+	for n := limit; n >= minOverlap; n-- {
+		if strings.HasSuffix(previous, next[:n]) {
+			return previous + next[n:]
+		}
+	}
+
+	// The model may restart the line that was cut off mid-way
+	if idx := strings.LastIndex(previous, "\n"); idx >= 0 {
+		lastLine := strings.TrimSpace(previous[idx+1:])
+		trimmed := strings.TrimLeft(next, " \t\n")
+		if len(lastLine) >= minOverlap && strings.HasPrefix(trimmed, lastLine) {
+			return previous[:idx+1] + trimmed
+		}
+	}
+
+	return previous + next
+}
@@ -0,0 +1,74 @@
+package multiprovider
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStitchContinuation(t *testing.T) {
+	long := strings.Repeat("abcdefghij", 250) // Longer than maxOverlapScan
+
+	tests := []struct {
+		name     string
+		previous string
+		next     string
+		want     string
+	}{
+		{
+			name:     "no overlap",
+			previous: "The first part ends here.",
+			next:     " And the second part follows.",
+			want:     "The first part ends here. And the second part follows.",
+		},
+		{
+			name:     "overlap at the minimum",
+			previous: "Intro. She studied analytical engines",
+			next:     "studied analytical engines in depth.",
+			want:     "Intro. She studied analytical engines in depth.",
+		},
+		{
+			name:     "overlap below the minimum is kept",
+			previous: "She wrote the notes",
+			next:     "the notes were long.",
+			want:     "She wrote the notesthe notes were long.",
+		},
+		{
+			name:     "whole previous text repeated",
+			previous: "## 1. Voice\n\nMeasured and precise,",
+			next:     "## 1. Voice\n\nMeasured and precise, with dry humour.",
+			want:     "## 1. Voice\n\nMeasured and precise, with dry humour.",
+		},
+		{
+			name:     "longest overlap wins",
+			previous: "x " + strings.Repeat("ab", 20),
+			next:     strings.Repeat("ab", 20) + " done",
+			want:     "x " + strings.Repeat("ab", 20) + " done",
+		},
+		{
+			name:     "overlap capped at the scan limit",
+			previous: "start " + long,
+			next:     long[len(long)-maxOverlapScan:] + " end",
+			want:     "start " + long + " end",
+		},
+		{
+			name:     "restarted line",
+			previous: "## 2. Style\n\nShe favours long sentences that bui",
+			next:     "She favours long sentences that build to a point.",
+			want:     "## 2. Style\n\nShe favours long sentences that build to a point.",
+		},
+		{
+			name:     "restarted short line is not trimmed",
+			previous: "## 2. Style\n\nShort li",
+			next:     "Short line follows.",
+			want:     "## 2. Style\n\nShort liShort line follows.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stitchContinuation(tt.previous, tt.next); got != tt.want {
+				t.Errorf("stitchContinuation() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
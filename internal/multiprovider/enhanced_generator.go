@@ -5,11 +5,15 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"time"
 
 	"github.com/google/go-github/v57/github"
 	"github.com/twin2ai/studio/internal/assets"
 	gh "github.com/twin2ai/studio/internal/github"
+	"github.com/twin2ai/studio/internal/llm"
+	"github.com/twin2ai/studio/internal/provider"
+	"github.com/twin2ai/studio/internal/usage"
 	"github.com/twin2ai/studio/pkg/models"
 )
 
@@ -21,6 +25,7 @@ func (g *Generator) ProcessIssueWithStructure(ctx context.Context, issue *github
 // ProcessIssueWithStructureAndUser processes an issue with optional user-supplied persona
 func (g *Generator) ProcessIssueWithStructureAndUser(ctx context.Context, issue *github.Issue, userPersona string) (*models.Persona, *gh.PersonaFiles, error) {
 	g.logger.Infof("Processing issue #%d with structured multi-provider generation: %s", *issue.Number, *issue.Title)
+	ctx = usage.WithLabels(ctx, usage.Labels{Issue: *issue.Number, Persona: *issue.Title, Stage: usage.StageGeneration})
 	if userPersona != "" {
 		g.logger.Info("User-supplied persona detected, will include in synthesis")
 	}
@@ -38,7 +43,7 @@ func (g *Generator) ProcessIssueWithStructureAndUser(ctx context.Context, issue
 	}
 
 	// Generate personas from all providers in parallel
-	responses, err := g.generateFromAllProviders(ctx, issueContent, template)
+	responses, err := g.generateFromAllProviders(ctx, *issue.Number, "", g.newRequest(issueContent, template))
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to generate personas from providers: %w", err)
 	}
@@ -49,27 +54,14 @@ func (g *Generator) ProcessIssueWithStructureAndUser(ctx context.Context, issue
 	}
 
 	// Extract individual provider contents
-	var claudeRaw, geminiRaw, grokRaw, gptRaw string
-	for _, resp := range responses {
-		if resp.Error == nil {
-			switch resp.Provider {
-			case "claude":
-				claudeRaw = resp.Content
-			case "gemini":
-				geminiRaw = resp.Content
-			case "grok":
-				grokRaw = resp.Content
-			case "gpt":
-				gptRaw = resp.Content
-			}
-		}
-	}
+	rawOutputs := g.collectRawOutputs(responses)
 
 	// Combine all responses into final persona (including user persona if provided)
-	fullSynthesis, err := g.combinePersonasWithUser(ctx, responses, userPersona)
+	combined, err := g.combinePersonasWithUser(usage.WithStage(ctx, usage.StageSynthesis), responses, userPersona)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to combine personas: %w", err)
 	}
+	fullSynthesis := combined.Text
 
 	// Store combined result
 	if err := g.storeCombinedPersona(*issue.Number, fullSynthesis); err != nil {
@@ -92,21 +84,31 @@ func (g *Generator) ProcessIssueWithStructureAndUser(ctx context.Context, issue
 
 	// Create PersonaFiles structure
 	files := &gh.PersonaFiles{
-		ClaudeRaw:     claudeRaw,
-		GeminiRaw:     geminiRaw,
-		GrokRaw:       grokRaw,
-		GPTRaw:        gptRaw,
-		UserRaw:       userPersona,
-		FullSynthesis: fullSynthesis,
-		AssetStatus:   assetStatus,
+		RawOutputs:      rawOutputs,
+		UserRaw:         userPersona,
+		FailedProviders: failedProviders(responses),
+		FullSynthesis:   fullSynthesis,
+		PersonaJSON:     g.structurePersona(usage.WithStage(ctx, usage.StageSynthesis), *issue.Number, personaName, fullSynthesis),
+		Facts:           g.factSheet(combined, assetStatus),
+		AssetStatus:     assetStatus,
+		Validation:      g.validatePersona(*issue.Number, personaName, fullSynthesis),
+		Evaluation:      g.evaluate(ctx, *issue.Number, personaName, responses, combined),
+		Disagreements:   g.analyze(ctx, *issue.Number, personaName, responses, combined),
+	}
+	if files.Validation != nil {
+		assetStatus.Metadata[models.MetadataCompleteness] = strconv.Itoa(files.Validation.Score)
+	}
+	if files.Evaluation != nil {
+		files.Evaluation.Record(assetStatus.Metadata)
+	}
+	if files.Disagreements != nil {
+		files.Disagreements.Record(assetStatus.Metadata)
 	}
 
 	// Create Persona model
-	persona := &models.Persona{
-		Name:        personaName,
-		Content:     fullSynthesis,
-		IssueNumber: *issue.Number,
-	}
+	persona := g.newPersona(*issue.Number, personaName, responses, combined, assetStatus.Metadata)
+	persona.Assets = assetStatus
+	persona.Profile = profileFrom(files.PersonaJSON)
 
 	return persona, files, nil
 }
@@ -121,24 +123,15 @@ func (g *Generator) loadPromptFromFile(filename string) (string, error) {
 }
 
 // UpdatePersonaWithUserInput updates an existing persona with user-provided content
-func (g *Generator) UpdatePersonaWithUserInput(ctx context.Context, personaName string, existingPersona string, userPersona string) (string, error) {
+func (g *Generator) UpdatePersonaWithUserInput(ctx context.Context, personaName string, existingPersona string, userPersona string) (*provider.Synthesis, error) {
 	g.logger.Infof("Updating persona '%s' with user input", personaName)
+	ctx = usage.WithLabels(ctx, usage.Labels{Persona: personaName, Stage: usage.StageSynthesis})
 
-	// Combine existing and user personas for synthesis
-	combinationPrompt := fmt.Sprintf(`You are tasked with creating an improved persona by synthesizing an existing persona with a user-provided update.
+	// The instructions go in the system prompt, the two personas in the message
+	combination := llm.Request{System: `You are tasked with creating an improved persona by synthesizing an existing persona with a user-provided update.
 
 The user has provided their own version of the persona that should be intelligently merged with the existing version to create a superior, comprehensive persona that incorporates the best elements of both.
 
-EXISTING PERSONA:
-<<<
-%s
->>>
-
-USER-PROVIDED UPDATE:
-<<<
-%s
->>>
-
 Please create a synthesized version that:
 1. Incorporates new information from the user's version
 2. Preserves valuable details from the existing persona
@@ -146,12 +139,19 @@ Please create a synthesized version that:
 4. Maintains consistency and coherence throughout
 5. Results in a richer, more complete persona
 
-Start your response immediately with the synthesized persona content using proper Markdown formatting. Do NOT include any preambles or meta-commentary.`, existingPersona, userPersona)
+Start your response immediately with the synthesized persona content using proper Markdown formatting. Do NOT include any preambles or meta-commentary.`}.User(fmt.Sprintf(`EXISTING PERSONA:
+<<<
+%s
+>>>
 
-	// Use Gemini with lower temperature for synthesis
-	synthesized, err := g.gemini.GenerateSynthesis(ctx, combinationPrompt)
+USER-PROVIDED UPDATE:
+<<<
+%s
+>>>`, existingPersona, userPersona))
+
+	synthesized, err := g.synthesis.Complete(ctx, combination, llm.StageSynthesis)
 	if err != nil {
-		return "", fmt.Errorf("failed to synthesize personas: %w", err)
+		return nil, fmt.Errorf("failed to synthesize personas: %w", err)
 	}
 
 	return synthesized, nil
@@ -160,6 +160,7 @@ Start your response immediately with the synthesized persona content using prope
 // RegeneratePersonaWithStructuredFeedback regenerates a complete persona package with feedback
 func (g *Generator) RegeneratePersonaWithStructuredFeedback(ctx context.Context, issue *github.Issue, existingPersona string, feedback []string) (*models.Persona, *gh.PersonaFiles, error) {
 	g.logger.Infof("Regenerating structured persona for issue #%d with feedback", *issue.Number)
+	ctx = usage.WithLabels(ctx, usage.Labels{Issue: *issue.Number, Persona: *issue.Title, Stage: usage.StageFeedback})
 
 	// Combine issue title and body for context
 	issueContent := fmt.Sprintf("Title: %s\n\nDescription:\n%s",
@@ -173,22 +174,11 @@ func (g *Generator) RegeneratePersonaWithStructuredFeedback(ctx context.Context,
 		template = ""
 	}
 
-	// Create enhanced prompt with feedback
-	feedbackSection := g.formatFeedback(feedback)
-	enhancedPrompt := fmt.Sprintf(`%s
-
-IMPORTANT: Please address the following feedback and regenerate the persona:
-
-%s
-
-Previous persona version:
-%s
-
-Please create an improved version that addresses all the feedback points above.`,
-		issueContent, feedbackSection, existingPersona)
+	// Continue the generation conversation with the previous version and the feedback
+	req := g.withFeedback(g.newRequest(issueContent, template), existingPersona, feedback)
 
 	// Generate from all providers with feedback
-	responses, err := g.generateFromAllProviders(ctx, enhancedPrompt, template)
+	responses, err := g.generateFromAllProviders(ctx, *issue.Number, "feedback", req)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to regenerate personas from providers: %w", err)
 	}
@@ -202,27 +192,14 @@ Please create an improved version that addresses all the feedback points above.`
 	// ... (rest of the implementation follows the same pattern)
 
 	// Extract individual provider contents
-	var claudeRaw, geminiRaw, grokRaw, gptRaw string
-	for _, resp := range responses {
-		if resp.Error == nil {
-			switch resp.Provider {
-			case "claude":
-				claudeRaw = resp.Content
-			case "gemini":
-				geminiRaw = resp.Content
-			case "grok":
-				grokRaw = resp.Content
-			case "gpt":
-				gptRaw = resp.Content
-			}
-		}
-	}
+	rawOutputs := g.collectRawOutputs(responses)
 
 	// Combine all responses into final persona
-	fullSynthesis, err := g.combinePersonas(ctx, responses)
+	combined, err := g.combinePersonas(ctx, responses)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to combine feedback personas: %w", err)
 	}
+	fullSynthesis := combined.Text
 
 	// Store combined result with feedback suffix
 	if err := g.storeCombinedPersonaWithSuffix(*issue.Number, fullSynthesis, "feedback"); err != nil {
@@ -245,21 +222,61 @@ Please create an improved version that addresses all the feedback points above.`
 
 	// Create PersonaFiles structure
 	files := &gh.PersonaFiles{
-		ClaudeRaw:     claudeRaw,
-		GeminiRaw:     geminiRaw,
-		GrokRaw:       grokRaw,
-		GPTRaw:        gptRaw,
-		UserRaw:       "", // No user persona in regeneration
-		FullSynthesis: fullSynthesis,
-		AssetStatus:   assetStatus,
+		RawOutputs:      rawOutputs,
+		UserRaw:         "", // No user persona in regeneration
+		FailedProviders: failedProviders(responses),
+		FullSynthesis:   fullSynthesis,
+		PersonaJSON:     g.structurePersona(usage.WithStage(ctx, usage.StageSynthesis), *issue.Number, personaName, fullSynthesis),
+		Facts:           g.factSheet(combined, assetStatus),
+		AssetStatus:     assetStatus,
+		Validation:      g.validatePersona(*issue.Number, personaName, fullSynthesis),
+		Evaluation:      g.evaluate(ctx, *issue.Number, personaName, responses, combined),
+		Disagreements:   g.analyze(ctx, *issue.Number, personaName, responses, combined),
+	}
+	if files.Validation != nil {
+		assetStatus.Metadata[models.MetadataCompleteness] = strconv.Itoa(files.Validation.Score)
+	}
+	if files.Evaluation != nil {
+		files.Evaluation.Record(assetStatus.Metadata)
+	}
+	if files.Disagreements != nil {
+		files.Disagreements.Record(assetStatus.Metadata)
 	}
 
 	// Create Persona model
-	persona := &models.Persona{
-		Name:        personaName,
-		Content:     fullSynthesis,
-		IssueNumber: *issue.Number,
-	}
+	persona := g.newPersona(*issue.Number, personaName, responses, combined, assetStatus.Metadata)
+	persona.Assets = assetStatus
+	persona.Profile = profileFrom(files.PersonaJSON)
 
 	return persona, files, nil
 }
+
+// collectRawOutputs extracts successful provider responses in provider order
+func (g *Generator) collectRawOutputs(responses []ProviderResponse) []gh.RawOutput {
+	var rawOutputs []gh.RawOutput
+	for _, resp := range responses {
+		if resp.Error == nil {
+			raw := gh.RawOutput{
+				Provider: resp.Provider,
+				Model:    resp.Model,
+				Content:  resp.Content,
+			}
+			if g.publishThinking {
+				raw.Thinking = resp.Thinking
+			}
+			rawOutputs = append(rawOutputs, raw)
+		}
+	}
+	return rawOutputs
+}
+
+// failedProviders returns the providers whose generation failed
+func failedProviders(responses []ProviderResponse) []string {
+	var failed []string
+	for _, resp := range responses {
+		if resp.Error != nil {
+			failed = append(failed, resp.Provider)
+		}
+	}
+	return failed
+}
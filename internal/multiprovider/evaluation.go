@@ -0,0 +1,43 @@
+package multiprovider
+
+import (
+	"context"
+
+	"github.com/twin2ai/studio/internal/evaluation"
+	"github.com/twin2ai/studio/internal/usage"
+)
+
+// SetJudge scores every raw output and synthesis with a judge model before
+// the PR is opened; nil turns evaluation off
+func (g *Generator) SetJudge(judge *evaluation.Judge) {
+	g.judge = judge
+}
+
+// evaluate scores the raw outputs and the synthesis built from them and
+// keeps the report with the other artifacts. It returns nil when no judge
+// is set or when there was nothing synthesized to compare.
+func (g *Generator) evaluate(ctx context.Context, issueNumber int, personaName string, responses []ProviderResponse, combined *combination) *evaluation.Report {
+	if g.judge == nil || (combined.Method != methodSynthesized && combined.Method != methodBySection) {
+		return nil
+	}
+
+	var raw []evaluation.Output
+	for _, resp := range responses {
+		if resp.Error == nil && resp.Content != "" {
+			raw = append(raw, evaluation.Output{Name: resp.Provider, Model: resp.Model, Content: resp.Content})
+		}
+	}
+	synthesis := evaluation.Output{Name: evaluation.SynthesisOutput, Model: combined.Model, Content: combined.Text}
+
+	report := g.judge.Evaluate(usage.WithStage(ctx, usage.StageEvaluation), personaName, raw, synthesis)
+	if report.Flagged {
+		g.logger.Warnf("Evaluation of %s: %s; the synthesis scored below the best provider", personaName, report.Summary())
+	} else {
+		g.logger.Infof("Evaluated %s: %s", personaName, report.Summary())
+	}
+
+	if err := g.storeReport(issueNumber, "evaluation", report); err != nil {
+		g.logger.Warnf("Failed to store evaluation report: %v", err)
+	}
+	return report
+}
@@ -0,0 +1,60 @@
+package multiprovider
+
+import (
+	"context"
+
+	"github.com/twin2ai/studio/internal/assets"
+	"github.com/twin2ai/studio/internal/facts"
+	"github.com/twin2ai/studio/internal/usage"
+)
+
+// SetFactExtractor builds a fact sheet from the raw outputs before each
+// synthesis, adds its consensus to the combination prompt and commits it as
+// facts.json; nil turns fact sheets off
+func (g *Generator) SetFactExtractor(extractor *facts.Extractor) {
+	g.factExtractor = extractor
+}
+
+// extractFacts builds the fact sheet of the successful responses. It
+// returns nil when no extractor is set or the extraction fails, leaving the
+// combination prompt unchanged.
+func (g *Generator) extractFacts(ctx context.Context, responses []ProviderResponse) *facts.Sheet {
+	if g.factExtractor == nil {
+		return nil
+	}
+
+	var outputs []facts.Output
+	for _, resp := range responses {
+		if resp.Error == nil && resp.Content != "" {
+			outputs = append(outputs, facts.Output{Provider: resp.Provider, Content: resp.Content})
+		}
+	}
+
+	personaName := usage.LabelsFrom(ctx).Persona
+	sheet, err := g.factExtractor.Extract(usage.WithStage(ctx, usage.StageAnalysis), personaName, outputs)
+	if err != nil {
+		g.logger.Warnf("Failed to extract fact sheet, synthesizing without it: %v", err)
+		return nil
+	}
+	g.logger.Infof("Extracted fact sheet for %s: %s", personaName, sheet.Summary())
+	return sheet
+}
+
+// factSheet encodes the combination's fact sheet as facts.json and marks
+// the asset generated. It returns "" when there is no sheet.
+func (g *Generator) factSheet(combined *combination, status *assets.AssetStatus) string {
+	if combined.Facts == nil {
+		return ""
+	}
+	content, err := combined.Facts.JSON()
+	if err != nil {
+		g.logger.Warnf("Failed to encode fact sheet: %v", err)
+		return ""
+	}
+
+	asset := string(assets.AssetTypeFacts)
+	status.GeneratedAssets = append(status.GeneratedAssets, asset)
+	status.AssetGenerationFlags[asset] = true
+	combined.Facts.Record(status.Metadata)
+	return content
+}
@@ -13,41 +13,104 @@ import (
 	"github.com/google/go-github/v57/github"
 	"github.com/sirupsen/logrus"
 
-	"github.com/twin2ai/studio/internal/claude"
-	"github.com/twin2ai/studio/internal/gemini"
-	"github.com/twin2ai/studio/internal/gpt"
-	"github.com/twin2ai/studio/internal/grok"
+	"github.com/twin2ai/studio/internal/analysis"
+	"github.com/twin2ai/studio/internal/completeness"
+	"github.com/twin2ai/studio/internal/evaluation"
+	"github.com/twin2ai/studio/internal/facts"
+	"github.com/twin2ai/studio/internal/llm"
+	"github.com/twin2ai/studio/internal/provider"
+	"github.com/twin2ai/studio/internal/usage"
+	"github.com/twin2ai/studio/internal/validation"
 	"github.com/twin2ai/studio/pkg/models"
 )
 
 type Generator struct {
-	claude  *claude.Client
-	gemini  *gemini.Client
-	grok    *grok.Client
-	gpt     *gpt.Client
-	logger  *logrus.Logger
-	baseDir string
+	providers        *provider.Registry
+	synthesis        *provider.SynthesisChain
+	logger           *logrus.Logger
+	baseDir          string
+	maxContinuations int
+	quorum           Quorum
+	publishThinking  bool
+	structuredOutput bool
+	linter           *validation.Linter
+	completeness     completeness.Policy
+	judge            *evaluation.Judge
+	analyzer         *analysis.Analyzer
+	factExtractor    *facts.Extractor
 }
 
 type ProviderResponse struct {
-	Provider string
-	Content  string
-	Error    error
+	Provider      string
+	Model         string
+	Content       string
+	Thinking      string
+	StopReason    llm.StopReason
+	Usage         llm.Usage
+	Continuations int
+	Error         error
 }
 
-func NewGenerator(claudeClient *claude.Client, geminiClient *gemini.Client, grokClient *grok.Client, gptClient *gpt.Client, logger *logrus.Logger) *Generator {
+// Synthesis methods recorded in persona metadata
+const (
+	methodSynthesized    = "synthesized"
+	methodSingleResponse = "single_response"
+	methodBestResponse   = "best_response"
+	methodBySection      = "section_synthesis" // Rebuilt one template section at a time
+)
+
+// combination is a combined persona and how it was produced
+type combination struct {
+	provider.Synthesis
+	Method string
+	Prompt string       // Synthesis prompt file; empty when no synthesis ran
+	Facts  *facts.Sheet // Fact sheet added to the synthesis prompt; nil when none was
+}
+
+func NewGenerator(providers *provider.Registry, synthesis *provider.SynthesisChain, logger *logrus.Logger) *Generator {
 	return &Generator{
-		claude:  claudeClient,
-		gemini:  geminiClient,
-		grok:    grokClient,
-		gpt:     gptClient,
-		logger:  logger,
-		baseDir: "artifacts",
+		providers:        providers,
+		synthesis:        synthesis,
+		logger:           logger,
+		baseDir:          "artifacts",
+		maxContinuations: 3,
+		quorum:           Quorum{MinProviders: 1},
+		completeness:     completeness.DefaultPolicy,
+	}
+}
+
+// SetArtifactsDir changes where provider and combined artifacts are written
+func (g *Generator) SetArtifactsDir(dir string) {
+	g.baseDir = dir
+}
+
+// SetQuorum sets the providers that must succeed before synthesis
+func (g *Generator) SetQuorum(q Quorum) {
+	for _, name := range q.Required {
+		if _, ok := g.providers.Get(name); !ok {
+			g.logger.Warnf("Required provider %s is not configured; the quorum can never be met", name)
+		}
+	}
+	if q.MinProviders > len(g.providers.Providers()) {
+		g.logger.Warnf("Quorum needs %d providers but only %d are configured", q.MinProviders, len(g.providers.Providers()))
 	}
+	g.quorum = q
+}
+
+// SetPublishThinking includes extended thinking in the persona folder
+// alongside each provider's raw output
+func (g *Generator) SetPublishThinking(publish bool) {
+	g.publishThinking = publish
+}
+
+// SetMaxContinuations caps the continuation requests made for a truncated output
+func (g *Generator) SetMaxContinuations(n int) {
+	g.maxContinuations = n
 }
 
 func (g *Generator) ProcessIssue(ctx context.Context, issue *github.Issue) (*models.Persona, error) {
 	g.logger.Infof("Processing issue #%d with multi-provider generation: %s", *issue.Number, *issue.Title)
+	ctx = usage.WithLabels(ctx, usage.Labels{Issue: *issue.Number, Persona: *issue.Title, Stage: usage.StageGeneration})
 
 	// Combine issue title and body for context
 	issueContent := fmt.Sprintf("Title: %s\n\nDescription:\n%s",
@@ -62,7 +125,7 @@ func (g *Generator) ProcessIssue(ctx context.Context, issue *github.Issue) (*mod
 	}
 
 	// Generate personas from all providers in parallel
-	responses, err := g.generateFromAllProviders(ctx, issueContent, template)
+	responses, err := g.generateFromAllProviders(ctx, *issue.Number, "", g.newRequest(issueContent, template))
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate personas from providers: %w", err)
 	}
@@ -73,10 +136,11 @@ func (g *Generator) ProcessIssue(ctx context.Context, issue *github.Issue) (*mod
 	}
 
 	// Combine all responses into final persona
-	finalPersona, err := g.combinePersonas(ctx, responses)
+	combined, err := g.combinePersonas(usage.WithStage(ctx, usage.StageSynthesis), responses)
 	if err != nil {
 		return nil, fmt.Errorf("failed to combine personas: %w", err)
 	}
+	finalPersona := combined.Text
 
 	// Store combined result
 	if err := g.storeCombinedPersona(*issue.Number, finalPersona); err != nil {
@@ -92,15 +156,12 @@ func (g *Generator) ProcessIssue(ctx context.Context, issue *github.Issue) (*mod
 		personaName = g.extractPersonaName(finalPersona)
 	}
 
-	return &models.Persona{
-		Name:        personaName,
-		Content:     finalPersona,
-		IssueNumber: *issue.Number,
-	}, nil
+	return g.newPersona(*issue.Number, personaName, responses, combined, make(map[string]string)), nil
 }
 
 func (g *Generator) RegeneratePersonaWithFeedback(ctx context.Context, issue *github.Issue, existingPersona string, feedback []string) (*models.Persona, error) {
 	g.logger.Infof("Regenerating persona for issue #%d with feedback using all providers", *issue.Number)
+	ctx = usage.WithLabels(ctx, usage.Labels{Issue: *issue.Number, Persona: getStringValue(issue.Title), Stage: usage.StageFeedback})
 
 	// Combine issue title and body for context
 	issueContent := fmt.Sprintf("Title: %s\n\nDescription:\n%s",
@@ -114,22 +175,11 @@ func (g *Generator) RegeneratePersonaWithFeedback(ctx context.Context, issue *gi
 		template = ""
 	}
 
-	// Create enhanced prompt with feedback
-	feedbackSection := g.formatFeedback(feedback)
-	enhancedPrompt := fmt.Sprintf(`%s
-
-IMPORTANT: Please address the following feedback and regenerate the persona:
-
-%s
-
-Previous persona version:
-%s
-
-Please create an improved version that addresses all the feedback points above.`,
-		issueContent, feedbackSection, existingPersona)
+	// Continue the generation conversation with the previous version and the feedback
+	req := g.withFeedback(g.newRequest(issueContent, template), existingPersona, feedback)
 
 	// Generate from all providers with feedback
-	responses, err := g.generateFromAllProviders(ctx, enhancedPrompt, template)
+	responses, err := g.generateFromAllProviders(ctx, *issue.Number, "feedback", req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to regenerate personas from providers: %w", err)
 	}
@@ -140,10 +190,11 @@ Please create an improved version that addresses all the feedback points above.`
 	}
 
 	// Combine all responses with feedback prompt
-	finalPersona, err := g.combinePersonasWithFeedback(ctx, responses, feedback)
+	combined, err := g.combinePersonasWithFeedback(ctx, responses, feedback)
 	if err != nil {
 		return nil, fmt.Errorf("failed to combine feedback personas: %w", err)
 	}
+	finalPersona := combined.Text
 
 	// Store combined result with feedback suffix
 	if err := g.storeCombinedPersonaWithSuffix(*issue.Number, finalPersona, "feedback"); err != nil {
@@ -159,118 +210,109 @@ Please create an improved version that addresses all the feedback points above.`
 		personaName = g.extractPersonaName(finalPersona)
 	}
 
-	return &models.Persona{
-		Name:        personaName,
-		Content:     finalPersona,
-		IssueNumber: *issue.Number,
-	}, nil
+	return g.newPersona(*issue.Number, personaName, responses, combined, make(map[string]string)), nil
 }
 
-func (g *Generator) generateFromAllProviders(ctx context.Context, issueContent, template string) ([]ProviderResponse, error) {
-	// Prepare the full prompt for providers that can handle it
-	var fullPrompt string
-	if template != "" {
-		fullPrompt = fmt.Sprintf("%s\n\nUse this template as a guide:\n%s", issueContent, template)
-	} else {
-		fullPrompt = issueContent
-	}
-
-	// Create a shorter prompt for GPT-4 to avoid context length issues
-	shortPrompt := fmt.Sprintf(`%s
+func (g *Generator) generateFromAllProviders(ctx context.Context, issueNumber int, suffix string, req provider.Request) ([]ProviderResponse, error) {
+	req.Stage = usage.LabelsFrom(ctx).Stage
 
-Create a detailed user persona based on the above information. Include:
-1. Name and demographics
-2. Background and goals
-3. Pain points and challenges
-4. Technical proficiency
-5. Behavioral patterns
-6. Success criteria
+	// Give providers whose circuit breaker has cooled down a chance to recover
+	g.providers.ProbeOpen(ctx, g.logger)
 
-Format as a well-structured Markdown document.`, issueContent)
-
-	// Create channels for responses
-	responses := make(chan ProviderResponse, 4)
-	var wg sync.WaitGroup
+	providers := g.providers.Providers()
+	responses := g.generateFrom(ctx, providers, issueNumber, suffix, req)
 
-	// Generate from Claude - uses its own template handling
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		content, err := g.claude.GeneratePersona(ctx, issueContent, template)
-		responses <- ProviderResponse{
-			Provider: "claude",
-			Content:  content,
-			Error:    err,
+	// Retry the failed providers while the quorum is missed
+	for attempt := 1; attempt <= g.quorum.Retries && ctx.Err() == nil; attempt++ {
+		qerr := g.quorum.check(responses)
+		if qerr == nil {
+			break
 		}
-	}()
-
-	// Generate from Gemini - can handle full prompt
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		content, err := g.gemini.GeneratePersona(ctx, fullPrompt)
-		responses <- ProviderResponse{
-			Provider: "gemini",
-			Content:  content,
-			Error:    err,
-		}
-	}()
-
-	// Generate from Grok - can handle full prompt
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		content, err := g.grok.GeneratePersona(ctx, fullPrompt)
-		responses <- ProviderResponse{
-			Provider: "grok",
-			Content:  content,
-			Error:    err,
+
+		failed := failedIndexes(responses)
+		if len(failed) == 0 {
+			break
 		}
-	}()
-
-	// Generate from GPT - use shorter prompt to avoid context length issues
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		content, err := g.gpt.GeneratePersona(ctx, shortPrompt)
-		responses <- ProviderResponse{
-			Provider: "gpt",
-			Content:  content,
-			Error:    err,
+		retry := make([]provider.Provider, len(failed))
+		for i, idx := range failed {
+			retry[i] = providers[idx]
 		}
-	}()
+		g.logger.Warnf("%v; retrying %d failed providers (attempt %d/%d)", qerr, len(retry), attempt, g.quorum.Retries)
 
-	// Wait for all to complete
-	wg.Wait()
-	close(responses)
+		for i, resp := range g.generateFrom(ctx, retry, issueNumber, suffix, req) {
+			responses[failed[i]] = resp
+		}
+	}
 
 	// Collect results
 	var results []ProviderResponse
 	successCount := 0
-	for resp := range responses {
+	for _, resp := range responses {
 		results = append(results, resp)
 		if resp.Error == nil {
 			successCount++
-			g.logger.Infof("Successfully generated persona from %s", resp.Provider)
+			g.logger.Infof("Successfully generated persona from %s (stop reason: %s, continuations: %d)",
+				resp.Provider, resp.StopReason, resp.Continuations)
 		} else {
 			g.logger.Errorf("Failed to generate persona from %s: %v", resp.Provider, resp.Error)
 		}
 	}
 
-	if successCount == 0 {
-		return nil, fmt.Errorf("all providers failed to generate personas")
+	if qerr := g.quorum.check(results); qerr != nil {
+		return nil, qerr
 	}
 
 	g.logger.Infof("Generated personas from %d/%d providers", successCount, len(results))
 	return results, nil
 }
 
-func (g *Generator) combinePersonas(ctx context.Context, responses []ProviderResponse) (string, error) {
+// generateFrom queries the given providers in parallel, keeping their order
+func (g *Generator) generateFrom(ctx context.Context, providers []provider.Provider, issueNumber int, suffix string, req provider.Request) []ProviderResponse {
+	responses := make([]ProviderResponse, len(providers))
+	var wg sync.WaitGroup
+
+	for i, p := range providers {
+		wg.Add(1)
+		go func(i int, p provider.Provider) {
+			defer wg.Done()
+
+			// Streaming providers write their output to a partial artifact as it arrives
+			partial := g.newPartialArtifact(issueNumber, p.Name(), suffix)
+			req := req
+			req.OnDelta = partial.write
+
+			result, continuations, err := g.generateWithContinuation(ctx, p, req, partial)
+			partial.finish(err != nil)
+			resp := ProviderResponse{
+				Provider:      p.Name(),
+				Model:         p.Model(),
+				Continuations: continuations,
+				Error:         err,
+			}
+			if err == nil {
+				resp.Content = result.Text
+				resp.Thinking = result.Thinking
+				resp.StopReason = result.StopReason
+				resp.Usage = result.Usage
+				if result.Model != "" {
+					resp.Model = result.Model
+				}
+			}
+			responses[i] = resp
+		}(i, p)
+	}
+
+	// Wait for all to complete
+	wg.Wait()
+	return responses
+}
+
+func (g *Generator) combinePersonas(ctx context.Context, responses []ProviderResponse) (*combination, error) {
 	return g.combinePersonasWithUser(ctx, responses, "")
 }
 
-func (g *Generator) combinePersonasWithUser(ctx context.Context, responses []ProviderResponse, userPersona string) (string, error) {
-	g.logger.Info("Combining personas using Gemini")
+func (g *Generator) combinePersonasWithUser(ctx context.Context, responses []ProviderResponse, userPersona string) (*combination, error) {
+	g.logger.Infof("Combining personas using %s", strings.Join(g.synthesis.Names(), " → "))
 	if userPersona != "" {
 		g.logger.Info("Including user-supplied persona in synthesis")
 	}
@@ -284,12 +326,12 @@ func (g *Generator) combinePersonasWithUser(ctx context.Context, responses []Pro
 	}
 
 	if len(successfulResponses) == 0 {
-		return "", fmt.Errorf("no successful persona responses to combine")
+		return nil, fmt.Errorf("no successful persona responses to combine")
 	}
 
-	if len(successfulResponses) == 1 && userPersona == "" {
+	if len(successfulResponses) == 1 && userPersona == "" && g.usable(successfulResponses[0]) {
 		g.logger.Info("Only one successful response and no user persona, using it directly")
-		return successfulResponses[0].Content, nil
+		return fromResponse(successfulResponses[0], methodSingleResponse), nil
 	}
 
 	// Load combination prompt template
@@ -307,7 +349,9 @@ func (g *Generator) combinePersonasWithUser(ctx context.Context, responses []Pro
 			1)
 	}
 
-	finalPrompt := combinationPrompt
+	// Tell the synthesizer which facts the providers agree on
+	sheet := g.extractFacts(ctx, successfulResponses)
+	finalPrompt := facts.Apply(combinationPrompt, sheet)
 
 	// Build the personas section
 	var personaCount int
@@ -328,10 +372,12 @@ func (g *Generator) combinePersonasWithUser(ctx context.Context, responses []Pro
 
 	finalPrompt = strings.ReplaceAll(finalPrompt, "{{PERSONAS}}", strings.Join(personas, "\n\n"))
 
-	// Use Gemini with lower temperature for synthesis
-	combinedPersona, err := g.gemini.GenerateSynthesis(ctx, finalPrompt)
+	// Run the synthesis chain, falling back along it as needed, and repair
+	// an incomplete result
+	combined, err := g.synthesize(ctx, finalPrompt, combinationPromptFile, successfulResponses,
+		completeness.Context{UserPersona: userPersona, Facts: sheet})
 	if err != nil {
-		g.logger.Warnf("Failed to combine with Gemini, using best individual response: %v", err)
+		g.logger.Warnf("Failed to combine personas, using best individual response: %v", err)
 		// Fallback to the longest response as it's likely most complete
 		bestResponse := successfulResponses[0]
 		for _, resp := range successfulResponses[1:] {
@@ -339,10 +385,22 @@ func (g *Generator) combinePersonasWithUser(ctx context.Context, responses []Pro
 				bestResponse = resp
 			}
 		}
-		return bestResponse.Content, nil
+		best := fromResponse(bestResponse, methodBestResponse)
+		best.Facts = sheet
+		return best, nil
 	}
 
-	return combinedPersona, nil
+	g.logger.Infof("Personas combined by %s (%s)", combined.Provider, combined.Model)
+	combined.Facts = sheet
+	return combined, nil
+}
+
+// fromResponse uses a single provider response as the combined persona
+func fromResponse(resp ProviderResponse, method string) *combination {
+	return &combination{
+		Synthesis: provider.Synthesis{Text: resp.Content, Provider: resp.Provider, Model: resp.Model, StopReason: resp.StopReason},
+		Method:    method,
+	}
 }
 
 func (g *Generator) storeArtifacts(issueNumber int, responses []ProviderResponse) error {
@@ -364,6 +422,14 @@ func (g *Generator) storeArtifacts(issueNumber int, responses []ProviderResponse
 		}
 
 		g.logger.Infof("Stored %s persona artifact: %s", resp.Provider, filePath)
+
+		if resp.Thinking != "" {
+			thinkingPath := strings.TrimSuffix(filePath, ".md") + "_thinking.md"
+			if err := os.WriteFile(thinkingPath, []byte(resp.Thinking), 0644); err != nil {
+				return fmt.Errorf("failed to write %s thinking artifact: %w", resp.Provider, err)
+			}
+			g.logger.Infof("Stored %s thinking artifact: %s", resp.Provider, thinkingPath)
+		}
 	}
 	return nil
 }
@@ -387,6 +453,14 @@ func (g *Generator) storeArtifactsWithSuffix(issueNumber int, responses []Provid
 		}
 
 		g.logger.Infof("Stored %s %s persona artifact: %s", resp.Provider, suffix, filePath)
+
+		if resp.Thinking != "" {
+			thinkingPath := strings.TrimSuffix(filePath, ".md") + "_thinking.md"
+			if err := os.WriteFile(thinkingPath, []byte(resp.Thinking), 0644); err != nil {
+				return fmt.Errorf("failed to write %s thinking artifact: %w", resp.Provider, err)
+			}
+			g.logger.Infof("Stored %s %s thinking artifact: %s", resp.Provider, suffix, thinkingPath)
+		}
 	}
 	return nil
 }
@@ -445,8 +519,48 @@ func (g *Generator) extractPersonaName(content string) string {
 	return "AI-Generated Persona"
 }
 
+// newRequest builds the provider request for an issue, with the generation
+// instructions as the system prompt
+func (g *Generator) newRequest(issueContent, template string) provider.Request {
+	return provider.Request{
+		IssueContent: issueContent,
+		Template:     template,
+		System:       g.loadSystemPrompt(),
+	}
+}
+
+// withFeedback continues the generation conversation with the previous
+// persona and a request to revise it
+func (g *Generator) withFeedback(req provider.Request, existingPersona string, feedback []string) provider.Request {
+	req.Turns = []llm.Message{
+		{Role: llm.RoleAssistant, Content: existingPersona},
+		{Role: llm.RoleUser, Content: fmt.Sprintf(`IMPORTANT: Please address the following feedback and regenerate the persona:
+
+%s
+Please create an improved version of your previous persona that addresses all the feedback points above.`, g.formatFeedback(feedback))},
+	}
+	return req
+}
+
+// loadSystemPrompt returns the generation instructions from
+// prompts/persona_system.txt, or a built-in default
+func (g *Generator) loadSystemPrompt() string {
+	prompt, err := g.loadPromptFromFile(filepath.Base(systemPromptFile))
+	if err != nil {
+		g.logger.Warnf("Failed to load system prompt, using default: %v", err)
+		return g.getDefaultSystemPrompt()
+	}
+	return strings.TrimSpace(prompt)
+}
+
+func (g *Generator) getDefaultSystemPrompt() string {
+	return `You are an expert persona writer. Build a detailed persona profile of the requested person, following the template you are given.
+
+Start your response immediately with the persona content using proper Markdown formatting. Do NOT include any preambles or meta-commentary.`
+}
+
 func (g *Generator) loadTemplate() (string, error) {
-	data, err := os.ReadFile("templates/persona_template.md")
+	data, err := os.ReadFile(templateFile)
 	if err != nil {
 		return "", err
 	}
@@ -454,7 +568,7 @@ func (g *Generator) loadTemplate() (string, error) {
 }
 
 func (g *Generator) loadCombinationPrompt() (string, error) {
-	data, err := os.ReadFile("prompts/persona_combination.txt")
+	data, err := os.ReadFile(combinationPromptFile)
 	if err != nil {
 		return "", err
 	}
@@ -485,8 +599,8 @@ func (g *Generator) formatFeedback(feedback []string) string {
 	return formatted
 }
 
-func (g *Generator) combinePersonasWithFeedback(ctx context.Context, responses []ProviderResponse, feedback []string) (string, error) {
-	g.logger.Info("Combining personas with feedback using Gemini")
+func (g *Generator) combinePersonasWithFeedback(ctx context.Context, responses []ProviderResponse, feedback []string) (*combination, error) {
+	g.logger.Infof("Combining personas with feedback using %s", strings.Join(g.synthesis.Names(), " → "))
 
 	// Filter successful responses
 	var successfulResponses []ProviderResponse
@@ -497,12 +611,12 @@ func (g *Generator) combinePersonasWithFeedback(ctx context.Context, responses [
 	}
 
 	if len(successfulResponses) == 0 {
-		return "", fmt.Errorf("no successful persona responses to combine")
+		return nil, fmt.Errorf("no successful persona responses to combine")
 	}
 
-	if len(successfulResponses) == 1 {
+	if len(successfulResponses) == 1 && g.usable(successfulResponses[0]) {
 		g.logger.Info("Only one successful response, using it directly")
-		return successfulResponses[0].Content, nil
+		return fromResponse(successfulResponses[0], methodSingleResponse), nil
 	}
 
 	// Load feedback combination prompt template
@@ -516,6 +630,10 @@ func (g *Generator) combinePersonasWithFeedback(ctx context.Context, responses [
 	feedbackSection := g.formatFeedback(feedback)
 	feedbackPrompt = strings.Replace(feedbackPrompt, "{{FEEDBACK}}", feedbackSection, 1)
 
+	// Tell the synthesizer which facts the providers agree on
+	sheet := g.extractFacts(ctx, successfulResponses)
+	feedbackPrompt = facts.Apply(feedbackPrompt, sheet)
+
 	// Build the personas section
 	var personaCount int
 	var personas []string
@@ -529,10 +647,12 @@ func (g *Generator) combinePersonasWithFeedback(ctx context.Context, responses [
 
 	finalPrompt := strings.ReplaceAll(feedbackPrompt, "{{PERSONAS}}", strings.Join(personas, "\n\n"))
 
-	// Use Gemini with lower temperature for synthesis
-	combinedPersona, err := g.gemini.GenerateSynthesis(ctx, finalPrompt)
+	// Run the synthesis chain, falling back along it as needed, and repair
+	// an incomplete result
+	combined, err := g.synthesize(ctx, finalPrompt, feedbackCombinationPromptFile, successfulResponses,
+		completeness.Context{Feedback: feedbackSection, Facts: sheet})
 	if err != nil {
-		g.logger.Warnf("Failed to combine with Gemini, using best individual response: %v", err)
+		g.logger.Warnf("Failed to combine personas, using best individual response: %v", err)
 		// Fallback to the longest response as it's likely most complete
 		bestResponse := successfulResponses[0]
 		for _, resp := range successfulResponses[1:] {
@@ -540,14 +660,18 @@ func (g *Generator) combinePersonasWithFeedback(ctx context.Context, responses [
 				bestResponse = resp
 			}
 		}
-		return bestResponse.Content, nil
+		best := fromResponse(bestResponse, methodBestResponse)
+		best.Facts = sheet
+		return best, nil
 	}
 
-	return combinedPersona, nil
+	g.logger.Infof("Personas combined by %s (%s)", combined.Provider, combined.Model)
+	combined.Facts = sheet
+	return combined, nil
 }
 
 func (g *Generator) loadFeedbackCombinationPrompt() (string, error) {
-	data, err := os.ReadFile("prompts/persona_combination_feedback.txt")
+	data, err := os.ReadFile(feedbackCombinationPromptFile)
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,149 @@
+package multiprovider
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// partialArtifact appends streamed text to
+// artifacts/<provider>/issue-<n>-<provider>[-suffix]-partial.md so progress is
+// observable while a provider is still generating, and a failed or timed-out
+// generation leaves its output on disk
+type partialArtifact struct {
+	path   string
+	logger *logrus.Logger
+
+	mu     sync.Mutex
+	file   *os.File
+	failed bool
+
+	// While a continuation streams, previous holds the text it continues and
+	// pending holds its first chunks until the overlap can be trimmed
+	previous string
+	pending  strings.Builder
+}
+
+func (g *Generator) newPartialArtifact(issueNumber int, providerName, suffix string) *partialArtifact {
+	name := fmt.Sprintf("issue-%d-%s-partial.md", issueNumber, providerName)
+	if suffix != "" {
+		name = fmt.Sprintf("issue-%d-%s-%s-partial.md", issueNumber, providerName, suffix)
+	}
+	return &partialArtifact{
+		path:   filepath.Join(g.baseDir, providerName, name),
+		logger: g.logger,
+	}
+}
+
+// write appends a streamed chunk, creating the file on the first chunk
+func (a *partialArtifact) write(text string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.failed || !a.open() {
+		return
+	}
+
+	if a.previous != "" {
+		a.pending.WriteString(text)
+		if a.pending.Len() >= maxOverlapScan {
+			a.flushContinuation()
+		}
+		return
+	}
+	a.append(text)
+}
+
+// continueFrom marks the start of a continuation of previous, the text
+// assembled so far. Its chunks are held back until enough has arrived to
+// trim the overlap with previous the way stitchContinuation does.
+func (a *partialArtifact) continueFrom(previous string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.flushContinuation()
+	a.previous = previous
+}
+
+// flushContinuation writes the held back continuation text without the part
+// that repeats previous. When the model restarted a cut-off line, the file
+// is rewritten with the stitched text.
+func (a *partialArtifact) flushContinuation() {
+	previous, next := a.previous, a.pending.String()
+	a.previous = ""
+	a.pending.Reset()
+	if a.failed || a.file == nil || next == "" {
+		return
+	}
+
+	stitched := stitchContinuation(previous, next)
+	if strings.HasPrefix(stitched, previous) {
+		a.append(stitched[len(previous):])
+		return
+	}
+	if err := a.file.Truncate(0); err != nil {
+		a.logger.Warnf("Failed to rewrite partial artifact %s: %v", a.path, err)
+		a.failed = true
+		return
+	}
+	if _, err := a.file.Seek(0, 0); err != nil {
+		a.logger.Warnf("Failed to rewrite partial artifact %s: %v", a.path, err)
+		a.failed = true
+		return
+	}
+	a.append(stitched)
+}
+
+// open creates the file if needed and reports whether it can be written
+func (a *partialArtifact) open() bool {
+	if a.file == nil {
+		if err := os.MkdirAll(filepath.Dir(a.path), 0755); err != nil {
+			a.logger.Warnf("Failed to create partial artifact directory: %v", err)
+			a.failed = true
+			return false
+		}
+		file, err := os.Create(a.path)
+		if err != nil {
+			a.logger.Warnf("Failed to create partial artifact %s: %v", a.path, err)
+			a.failed = true
+			return false
+		}
+		a.file = file
+		a.logger.Infof("Streaming partial output to %s", a.path)
+	}
+	return true
+}
+
+// append writes text to the end of the file
+func (a *partialArtifact) append(text string) {
+	if _, err := a.file.WriteString(text); err != nil {
+		a.logger.Warnf("Failed to write partial artifact %s: %v", a.path, err)
+		a.failed = true
+	}
+}
+
+// finish closes the file. Once the complete artifact is stored the partial
+// copy is removed; after a failure it is kept for inspection.
+func (a *partialArtifact) finish(keep bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.flushContinuation()
+	if a.file == nil {
+		return
+	}
+	a.file.Close()
+	a.file = nil
+
+	if keep {
+		a.logger.Warnf("Partial output kept at %s", a.path)
+		return
+	}
+	if err := os.Remove(a.path); err != nil {
+		a.logger.Debugf("Failed to remove partial artifact %s: %v", a.path, err)
+	}
+}
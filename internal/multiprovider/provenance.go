@@ -0,0 +1,73 @@
+package multiprovider
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/twin2ai/studio/pkg/models"
+)
+
+// Prompt and template files read during generation and synthesis
+const (
+	systemPromptFile              = "prompts/persona_system.txt"
+	generationPromptFile          = "prompts/persona_generation.txt"
+	combinationPromptFile         = "prompts/persona_combination.txt"
+	feedbackCombinationPromptFile = "prompts/persona_combination_feedback.txt"
+	templateFile                  = "templates/persona_template.md"
+)
+
+// newPersona builds the persona model for a combined result: the parsed
+// document with its provenance, which is also recorded in metadata
+func (g *Generator) newPersona(issueNumber int, name string, responses []ProviderResponse, combined *combination, metadata map[string]string) *models.Persona {
+	persona, err := models.Parse(combined.Text)
+	if err != nil {
+		g.logger.Warnf("Failed to parse combined persona: %v", err)
+		persona = &models.Persona{Content: combined.Text}
+	}
+	persona.Name = name
+	persona.IssueNumber = issueNumber
+	persona.Metadata = metadata
+	persona.Provenance = g.provenance(responses, combined)
+	persona.Provenance.Record(metadata)
+	return persona
+}
+
+// provenance records the providers, synthesizer and prompt versions behind
+// a combined persona
+func (g *Generator) provenance(responses []ProviderResponse, combined *combination) models.Provenance {
+	p := models.Provenance{
+		Synthesizer:      combined.Provider,
+		SynthesizerModel: combined.Model,
+		Method:           combined.Method,
+		PromptVersions:   make(map[string]string),
+		GeneratedAt:      time.Now(),
+	}
+	for _, resp := range responses {
+		if resp.Error == nil {
+			p.Providers = append(p.Providers, models.ProviderOutput{Provider: resp.Provider, Model: resp.Model})
+		}
+	}
+
+	files := []string{systemPromptFile, generationPromptFile, templateFile}
+	if combined.Prompt != "" {
+		files = append(files, combined.Prompt)
+	}
+	for _, file := range files {
+		p.PromptVersions[filepath.Base(file)] = promptVersion(file)
+	}
+	return p
+}
+
+// promptVersion identifies the contents of a prompt file by a short hash;
+// "builtin" means the file was missing and the built-in default was used
+func promptVersion(file string) string {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "builtin"
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:4])
+}
@@ -0,0 +1,72 @@
+package multiprovider
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Quorum decides when enough providers have succeeded to synthesize a persona
+type Quorum struct {
+	MinProviders int      // Minimum number of successful providers
+	Required     []string // Providers that must succeed, by name
+	Retries      int      // Extra attempts for the failed providers when the quorum is missed
+}
+
+// QuorumError reports a generation run that did not reach the quorum
+type QuorumError struct {
+	Quorum          Quorum
+	Succeeded       []string
+	Failed          []ProviderResponse
+	MissingRequired []string
+}
+
+func (e *QuorumError) Error() string {
+	if len(e.Succeeded) == 0 {
+		return "all providers failed to generate personas"
+	}
+	msg := fmt.Sprintf("provider quorum not met: %d/%d providers succeeded (need %d)",
+		len(e.Succeeded), len(e.Succeeded)+len(e.Failed), e.Quorum.MinProviders)
+	if len(e.MissingRequired) > 0 {
+		msg += fmt.Sprintf(", required providers failed: %s", strings.Join(e.MissingRequired, ", "))
+	}
+	return msg
+}
+
+// check returns a QuorumError when the responses do not satisfy the quorum
+func (q Quorum) check(responses []ProviderResponse) *QuorumError {
+	result := &QuorumError{Quorum: q}
+	succeeded := make(map[string]bool)
+	for _, resp := range responses {
+		if resp.Error == nil {
+			succeeded[resp.Provider] = true
+			result.Succeeded = append(result.Succeeded, resp.Provider)
+		} else {
+			result.Failed = append(result.Failed, resp)
+		}
+	}
+	for _, name := range q.Required {
+		if !succeeded[name] {
+			result.MissingRequired = append(result.MissingRequired, name)
+		}
+	}
+
+	minProviders := q.MinProviders
+	if minProviders < 1 {
+		minProviders = 1
+	}
+	if len(result.Succeeded) < minProviders || len(result.MissingRequired) > 0 {
+		return result
+	}
+	return nil
+}
+
+// failedIndexes returns the positions of the failed responses
+func failedIndexes(responses []ProviderResponse) []int {
+	var indexes []int
+	for i, resp := range responses {
+		if resp.Error != nil {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
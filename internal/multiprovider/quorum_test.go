@@ -0,0 +1,59 @@
+package multiprovider
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestQuorumCheck(t *testing.T) {
+	failed := errors.New("timeout")
+	responses := []ProviderResponse{
+		{Provider: "claude"},
+		{Provider: "gemini"},
+		{Provider: "grok", Error: failed},
+		{Provider: "gpt", Error: failed},
+	}
+
+	tests := []struct {
+		name      string
+		quorum    Quorum
+		responses []ProviderResponse
+		met       bool
+		missing   []string
+	}{
+		{name: "minimum met", quorum: Quorum{MinProviders: 2}, responses: responses, met: true},
+		{name: "minimum not met", quorum: Quorum{MinProviders: 3}, responses: responses},
+		{name: "zero minimum needs one", quorum: Quorum{}, responses: responses[2:]},
+		{name: "required succeeded", quorum: Quorum{MinProviders: 1, Required: []string{"claude"}}, responses: responses, met: true},
+		{name: "required failed", quorum: Quorum{MinProviders: 1, Required: []string{"claude", "gpt"}}, responses: responses, missing: []string{"gpt"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qerr := tt.quorum.check(tt.responses)
+			if (qerr == nil) != tt.met {
+				t.Fatalf("check() = %v, want met %v", qerr, tt.met)
+			}
+			if qerr != nil && !reflect.DeepEqual(qerr.MissingRequired, tt.missing) {
+				t.Errorf("check() missing required = %v, want %v", qerr.MissingRequired, tt.missing)
+			}
+		})
+	}
+}
+
+func TestQuorumErrorMessage(t *testing.T) {
+	qerr := Quorum{MinProviders: 3, Required: []string{"gpt"}}.check([]ProviderResponse{
+		{Provider: "claude"},
+		{Provider: "gpt", Error: errors.New("timeout")},
+	})
+	want := "provider quorum not met: 1/2 providers succeeded (need 3), required providers failed: gpt"
+	if qerr == nil || qerr.Error() != want {
+		t.Errorf("Error() = %v, want %q", qerr, want)
+	}
+
+	allFailed := Quorum{MinProviders: 1}.check([]ProviderResponse{{Provider: "gpt", Error: errors.New("timeout")}})
+	if allFailed == nil || allFailed.Error() != "all providers failed to generate personas" {
+		t.Errorf("Error() = %v, want all providers failed", allFailed)
+	}
+}
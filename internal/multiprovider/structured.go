@@ -0,0 +1,91 @@
+package multiprovider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/twin2ai/studio/internal/llm"
+	"github.com/twin2ai/studio/pkg/models"
+	"github.com/twin2ai/studio/pkg/schema"
+)
+
+// structuredAttempts caps the requests made to get a persona.json that
+// matches the schema, including the ones correcting validation errors
+const structuredAttempts = 2
+
+// SetStructuredOutput turns on persona.json generation after synthesis
+func (g *Generator) SetStructuredOutput(enabled bool) {
+	g.structuredOutput = enabled
+}
+
+// structurePersona converts a synthesized persona into persona.json using
+// the synthesis chain in JSON mode. Validation errors are sent back once so
+// the model can correct its document. It returns "" when structured output
+// is disabled or no valid document was produced.
+func (g *Generator) structurePersona(ctx context.Context, issueNumber int, personaName, persona string) string {
+	if !g.structuredOutput {
+		return ""
+	}
+	g.logger.Infof("Generating structured %s for %s", schema.PersonaVersion, personaName)
+
+	req := llm.Request{
+		System: fmt.Sprintf(`You convert persona profiles into JSON. Reply with a single JSON object that matches this JSON Schema and nothing else:
+
+%s
+
+Set schema_version to %q. Only use information found in the persona; leave optional fields out and arrays empty rather than inventing details.`, schema.PersonaV1, schema.PersonaVersion),
+		Schema: schema.PersonaV1,
+	}.User(fmt.Sprintf("PERSONA: %s\n<<<\n%s\n>>>", personaName, persona))
+
+	var raw json.RawMessage
+	synthesis, err := g.synthesis.CompleteJSON(ctx, req, llm.StageSynthesis, structuredAttempts, &raw, nil)
+	if err != nil {
+		g.logger.Warnf("Failed to generate persona.json: %v", err)
+		return ""
+	}
+
+	var document bytes.Buffer
+	if err := json.Indent(&document, raw, "", "  "); err != nil {
+		g.logger.Warnf("Failed to format persona.json: %v", err)
+		return ""
+	}
+	document.WriteByte('\n')
+
+	g.logger.Infof("Generated persona.json with %s (%d bytes)", synthesis.Provider, document.Len())
+	if err := g.storeStructuredPersona(issueNumber, document.Bytes()); err != nil {
+		g.logger.Warnf("Failed to store persona.json: %v", err)
+	}
+	return document.String()
+}
+
+// profileFrom decodes a persona.json document; it returns nil for an empty one
+func profileFrom(document string) *models.Profile {
+	if document == "" {
+		return nil
+	}
+	var profile models.Profile
+	if err := json.Unmarshal([]byte(document), &profile); err != nil {
+		return nil
+	}
+	return &profile
+}
+
+// storeStructuredPersona keeps a local copy of persona.json with the other artifacts
+func (g *Generator) storeStructuredPersona(issueNumber int, document []byte) error {
+	timestamp := time.Now().Format("20060102-150405")
+	filePath := filepath.Join(g.baseDir, "combined", fmt.Sprintf("issue-%d-persona-%s.json", issueNumber, timestamp))
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return fmt.Errorf("failed to create combined directory: %w", err)
+	}
+	if err := os.WriteFile(filePath, document, 0644); err != nil {
+		return fmt.Errorf("failed to write persona.json: %w", err)
+	}
+	g.logger.Infof("Stored structured persona: %s", filePath)
+	return nil
+}
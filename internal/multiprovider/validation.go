@@ -0,0 +1,56 @@
+package multiprovider
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/twin2ai/studio/internal/validation"
+)
+
+// SetLinter runs the persona linter on every synthesized persona before
+// the PR is opened; nil turns validation off
+func (g *Generator) SetLinter(linter *validation.Linter) {
+	g.linter = linter
+}
+
+// validatePersona lints a synthesized persona and keeps the report with
+// the other artifacts. It returns nil when no linter is set.
+func (g *Generator) validatePersona(issueNumber int, personaName, persona string) *validation.Report {
+	if g.linter == nil {
+		return nil
+	}
+
+	report := g.linter.Lint(personaName, persona)
+	if report.Passed {
+		g.logger.Infof("Validated %s: %s", personaName, report.Summary())
+	} else {
+		g.logger.Warnf("Validation of %s: %s", personaName, report.Summary())
+	}
+
+	if err := g.storeReport(issueNumber, "validation", report); err != nil {
+		g.logger.Warnf("Failed to store validation report: %v", err)
+	}
+	return report
+}
+
+// storeReport writes a report as JSON next to the combined persona, e.g.
+// combined/issue-12-validation-20250101-120000.json
+func (g *Generator) storeReport(issueNumber int, kind string, report interface{}) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s report: %w", kind, err)
+	}
+
+	timestamp := time.Now().Format("20060102-150405")
+	filePath := filepath.Join(g.baseDir, "combined", fmt.Sprintf("issue-%d-%s-%s.json", issueNumber, kind, timestamp))
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return fmt.Errorf("failed to create combined directory: %w", err)
+	}
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s report: %w", kind, err)
+	}
+	return nil
+}
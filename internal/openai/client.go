@@ -0,0 +1,255 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/twin2ai/studio/internal/llm"
+)
+
+// DefaultBaseURL is the OpenAI API endpoint used when no base URL is configured
+const DefaultBaseURL = "https://api.openai.com/v1"
+
+// Options configures a client for any OpenAI-compatible chat-completions API
+// (OpenAI, xAI, vLLM, Ollama, LM Studio, ...)
+type Options struct {
+	Name        string // Provider name used in log and error messages
+	BaseURL     string // API root, e.g. http://localhost:11434/v1
+	APIKey      string // Optional for servers without authentication
+	Model       string
+	Headers     map[string]string // Extra headers sent with every request
+	AuthHeader  string            // Header carrying the API key (default Authorization)
+	AuthScheme  string            // Scheme prefixed to the API key (default Bearer, "none" for the raw key)
+	MaxTokens   int               // Default max tokens, overridden per stage by SetParams
+	Temperature float64           // Default temperature, overridden per stage by SetParams
+}
+
+type Client struct {
+	opts   Options
+	params llm.ParamSet
+	sender *llm.Sender
+	logger *logrus.Logger
+}
+
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type Request struct {
+	Model       string    `json:"model"`
+	Messages    []Message `json:"messages"`
+	MaxTokens   int       `json:"max_tokens,omitempty"`
+	Temperature *float64  `json:"temperature,omitempty"`
+	TopP        *float64  `json:"top_p,omitempty"`
+	Seed        *int      `json:"seed,omitempty"`
+	Stop        []string  `json:"stop,omitempty"`
+
+	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
+}
+
+// ResponseFormat selects JSON output; json_schema asks the server to follow a schema
+type ResponseFormat struct {
+	Type       string      `json:"type"`
+	JSONSchema *JSONSchema `json:"json_schema,omitempty"`
+}
+
+// JSONSchema names the schema a json_schema response must follow
+type JSONSchema struct {
+	Name   string          `json:"name"`
+	Schema json.RawMessage `json:"schema"`
+	Strict bool            `json:"strict"`
+}
+
+type Response struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message      Message `json:"message"`
+		FinishReason string  `json:"finish_reason"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+func NewClient(opts Options, logger *logrus.Logger) *Client {
+	if opts.Name == "" {
+		opts.Name = "openai"
+	}
+	if opts.BaseURL == "" {
+		opts.BaseURL = DefaultBaseURL
+	}
+	opts.BaseURL = strings.TrimRight(opts.BaseURL, "/")
+	if opts.AuthHeader == "" {
+		opts.AuthHeader = "Authorization"
+	}
+	if opts.AuthScheme == "" {
+		opts.AuthScheme = "Bearer"
+	}
+
+	return &Client{
+		opts:   opts,
+		sender: llm.NewSender(opts.Name, 600*time.Second, logger),
+		logger: logger,
+	}
+}
+
+// Sender returns the HTTP sender so callers can adjust its retry policy
+func (c *Client) Sender() *llm.Sender {
+	return c.sender
+}
+
+// SetParams overrides the default generation settings per stage
+func (c *Client) SetParams(params llm.ParamSet) {
+	c.params = params
+}
+
+// resolveParams returns the settings for stage on top of the option defaults
+func (c *Client) resolveParams(stage llm.Stage) llm.Params {
+	defaults := llm.Params{MaxTokens: c.opts.MaxTokens}
+	if c.opts.Temperature != 0 {
+		defaults.Temperature = llm.Float(c.opts.Temperature)
+	}
+	params := defaults.Merge(c.params.For(stage))
+	if params.ThinkingBudget > 0 {
+		c.logger.Debugf("%s does not support a thinking budget, ignoring it", c.opts.Name)
+	}
+	return params
+}
+
+func (c *Client) GeneratePersona(ctx context.Context, prompt string) (string, error) {
+	result, err := c.Generate(ctx, prompt, llm.StageGeneration)
+	if err != nil {
+		return "", err
+	}
+	return result.Text, nil
+}
+
+// Generate sends a single prompt with the settings for stage and reports the
+// text with its stop reason
+func (c *Client) Generate(ctx context.Context, prompt string, stage llm.Stage) (*llm.Result, error) {
+	return c.Send(ctx, llm.Prompt(prompt), stage)
+}
+
+// Send sends a conversation with the settings for stage and reports the
+// text with its stop reason
+func (c *Client) Send(ctx context.Context, req llm.Request, stage llm.Stage) (*llm.Result, error) {
+	started := time.Now()
+	result, err := c.send(ctx, req, stage)
+	c.sender.Observe(ctx, c.opts.Model, result, started, err)
+	return result, err
+}
+
+// send performs the request for Send
+func (c *Client) send(ctx context.Context, req llm.Request, stage llm.Stage) (*llm.Result, error) {
+	params := c.resolveParams(stage)
+	request := Request{
+		Model:       c.opts.Model,
+		Messages:    make([]Message, 0, len(req.Messages)+1),
+		MaxTokens:   params.MaxTokens,
+		Temperature: params.Temperature,
+		TopP:        params.TopP,
+		Seed:        params.Seed,
+		Stop:        params.StopSequences,
+	}
+	if req.System != "" {
+		request.Messages = append(request.Messages, Message{Role: "system", Content: req.System})
+	}
+	for _, m := range req.Messages {
+		request.Messages = append(request.Messages, Message{Role: string(m.Role), Content: m.Content})
+	}
+	if len(req.Schema) > 0 {
+		// Not strict: strict mode only accepts schemas where every property is required
+		request.ResponseFormat = &ResponseFormat{
+			Type:       "json_schema",
+			JSONSchema: &JSONSchema{Name: "structured_output", Schema: req.Schema},
+		}
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	responseBody, err := c.sender.Do(ctx, func() (*http.Request, error) {
+		return c.newHTTPRequest(ctx, "POST", "/chat/completions", body)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var response Response
+	if err := json.Unmarshal(responseBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if len(response.Choices) == 0 {
+		return nil, fmt.Errorf("empty response from %s API", c.opts.Name)
+	}
+
+	choice := response.Choices[0]
+	result := &llm.Result{
+		Text:       choice.Message.Content,
+		StopReason: stopReason(choice.FinishReason),
+		Usage: llm.Usage{
+			InputTokens:  response.Usage.PromptTokens,
+			OutputTokens: response.Usage.CompletionTokens,
+		},
+		Model: response.Model,
+	}
+	if result.StopReason.Truncated() {
+		c.logger.Warnf("%s response hit the max_tokens limit (%d output tokens)", c.opts.Name, response.Usage.CompletionTokens)
+	}
+
+	return result, nil
+}
+
+// stopReason maps the OpenAI finish_reason onto llm.StopReason
+func stopReason(reason string) llm.StopReason {
+	switch reason {
+	case "stop":
+		return llm.StopEnd
+	case "length":
+		return llm.StopMaxTokens
+	case "content_filter":
+		return llm.StopSafety
+	default:
+		return llm.StopUnknown
+	}
+}
+
+// newHTTPRequest creates an authenticated request for path below the base URL
+func (c *Client) newHTTPRequest(ctx context.Context, method, path string, body []byte) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.opts.BaseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	c.setAuth(req)
+	for key, value := range c.opts.Headers {
+		req.Header.Set(key, value)
+	}
+	return req, nil
+}
+
+// setAuth adds the API key header; local servers often need no key at all
+func (c *Client) setAuth(req *http.Request) {
+	if c.opts.APIKey == "" {
+		return
+	}
+	if strings.EqualFold(c.opts.AuthScheme, "none") {
+		req.Header.Set(c.opts.AuthHeader, c.opts.APIKey)
+		return
+	}
+	req.Header.Set(c.opts.AuthHeader, c.opts.AuthScheme+" "+c.opts.APIKey)
+}
@@ -0,0 +1,54 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// TestConnection sends a minimal chat completion to confirm the endpoint,
+// API key and model work
+func (c *Client) TestConnection(ctx context.Context) error {
+	body, err := json.Marshal(Request{
+		Model:     c.opts.Model,
+		Messages:  []Message{{Role: "user", Content: "Say 'Hello, this is a test' to confirm the API is working."}},
+		MaxTokens: 10,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	_, err = c.sender.Do(ctx, func() (*http.Request, error) {
+		return c.newHTTPRequest(ctx, "POST", "/chat/completions", body)
+	})
+	if err != nil {
+		return fmt.Errorf("API connection test failed: %w", err)
+	}
+	return nil
+}
+
+// ListAvailableModels returns the model IDs served by the endpoint
+func (c *Client) ListAvailableModels(ctx context.Context) ([]string, error) {
+	responseBody, err := c.sender.Do(ctx, func() (*http.Request, error) {
+		return c.newHTTPRequest(ctx, "GET", "/models", nil)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list models: %w", err)
+	}
+
+	var response struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(responseBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to decode models: %w", err)
+	}
+
+	models := make([]string, 0, len(response.Data))
+	for _, model := range response.Data {
+		models = append(models, model.ID)
+	}
+	return models, nil
+}
@@ -0,0 +1,19 @@
+package pipeline
+
+import (
+	"github.com/sirupsen/logrus"
+
+	"github.com/twin2ai/studio/internal/analysis"
+	"github.com/twin2ai/studio/internal/config"
+	"github.com/twin2ai/studio/internal/provider"
+)
+
+// AnalyzerFromConfig creates the analyzer that reports where the providers
+// disagree, running on the synthesis providers. It returns nil, turning the
+// report off, when analysis is disabled.
+func AnalyzerFromConfig(cfg config.PipelineConfig, synthesis *provider.SynthesisChain, logger *logrus.Logger) *analysis.Analyzer {
+	if !cfg.Analysis {
+		return nil
+	}
+	return analysis.NewAnalyzer(synthesis, logger)
+}
@@ -7,7 +7,6 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
-	"time"
 
 	"github.com/google/go-github/v57/github"
 	"github.com/sirupsen/logrus"
@@ -142,11 +141,6 @@ func (bp *BatchPipeline) ProcessFile(ctx context.Context, filePath string) error
 
 		successCount++
 		bp.logger.Infof("  → Successfully generated persona")
-
-		// Add a small delay between requests to avoid rate limiting
-		if i < len(personaNames)-1 {
-			time.Sleep(2 * time.Second)
-		}
 	}
 
 	// Summary
@@ -308,8 +302,8 @@ func (bp *BatchPipeline) sanitizeForPath(name string) string {
 }
 
 func (bp *BatchPipeline) loadProcessedNames() error {
-	// Load from data/batch_processed_names.txt
-	filePath := filepath.Join("data", "batch_processed_names.txt")
+	// Load from <data dir>/batch_processed_names.txt
+	filePath := filepath.Join(bp.config.Pipeline.DataDir, "batch_processed_names.txt")
 
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -345,12 +339,12 @@ func (bp *BatchPipeline) loadProcessedNames() error {
 
 func (bp *BatchPipeline) saveProcessedName(personaName *PersonaName) error {
 	// Ensure data directory exists
-	if err := os.MkdirAll("data", 0755); err != nil {
+	if err := os.MkdirAll(bp.config.Pipeline.DataDir, 0755); err != nil {
 		return err
 	}
 
-	// Append to data/batch_processed_names.txt
-	filePath := filepath.Join("data", "batch_processed_names.txt")
+	// Append to <data dir>/batch_processed_names.txt
+	filePath := filepath.Join(bp.config.Pipeline.DataDir, "batch_processed_names.txt")
 	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
 	if err != nil {
 		return err
@@ -0,0 +1,31 @@
+package pipeline
+
+import (
+	"github.com/sirupsen/logrus"
+
+	"github.com/twin2ai/studio/internal/config"
+	"github.com/twin2ai/studio/internal/evaluation"
+	"github.com/twin2ai/studio/internal/provider"
+)
+
+// JudgeFromConfig creates the judge that scores raw outputs and syntheses.
+// It returns nil, turning evaluation off, when evaluation is disabled or
+// the judge providers or rubric cannot be loaded.
+func JudgeFromConfig(cfg *config.Config, logger *logrus.Logger) *evaluation.Judge {
+	if !cfg.Pipeline.Evaluation {
+		return nil
+	}
+
+	judges, err := provider.NewSynthesisChain(cfg.AI.Judges, logger)
+	if err != nil {
+		logger.Warnf("Evaluation disabled: failed to create judge providers: %v", err)
+		return nil
+	}
+
+	rubric, err := evaluation.LoadRubric(cfg.Pipeline.EvaluationRubric)
+	if err != nil {
+		logger.Warnf("Using the default evaluation rubric: %v", err)
+		rubric = evaluation.DefaultRubric()
+	}
+	return evaluation.NewJudge(judges, rubric, logger)
+}
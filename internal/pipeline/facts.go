@@ -0,0 +1,19 @@
+package pipeline
+
+import (
+	"github.com/sirupsen/logrus"
+
+	"github.com/twin2ai/studio/internal/config"
+	"github.com/twin2ai/studio/internal/facts"
+	"github.com/twin2ai/studio/internal/provider"
+)
+
+// FactExtractorFromConfig creates the extractor that builds facts.json,
+// running on the synthesis providers. It returns nil, turning fact sheets
+// off, when they are disabled.
+func FactExtractorFromConfig(cfg config.PipelineConfig, synthesis *provider.SynthesisChain, logger *logrus.Logger) *facts.Extractor {
+	if !cfg.FactSheet {
+		return nil
+	}
+	return facts.NewExtractor(synthesis, logger)
+}
@@ -14,14 +14,14 @@ import (
 	"github.com/google/go-github/v57/github"
 	"github.com/sirupsen/logrus"
 
+	"github.com/twin2ai/studio/internal/assets"
 	"github.com/twin2ai/studio/internal/claude"
 	"github.com/twin2ai/studio/internal/config"
-	"github.com/twin2ai/studio/internal/gemini"
+	"github.com/twin2ai/studio/internal/facts"
 	githubclient "github.com/twin2ai/studio/internal/github"
-	"github.com/twin2ai/studio/internal/gpt"
-	"github.com/twin2ai/studio/internal/grok"
 	"github.com/twin2ai/studio/internal/multiprovider"
 	"github.com/twin2ai/studio/internal/persona"
+	"github.com/twin2ai/studio/internal/provider"
 )
 
 type Pipeline struct {
@@ -49,17 +49,44 @@ func New(cfg *config.Config, logger *logrus.Logger) (*Pipeline, error) {
 
 	// Create AI clients
 	claudeClient := claude.NewClient(cfg.AI.Claude.APIKey, cfg.AI.Claude.Model, logger)
-	geminiClient := gemini.NewClient(cfg.AI.Gemini.APIKey, cfg.AI.Gemini.Model, logger)
-	grokClient := grok.NewClient(cfg.AI.Grok.APIKey, cfg.AI.Grok.Model, logger)
-	gptClient := gpt.NewClient(cfg.AI.GPT.APIKey, cfg.AI.GPT.Model, logger)
+	claudeClient.SetParams(cfg.AI.Claude.Params)
+
+	// Create the configured generation providers
+	providers, err := provider.NewRegistry(cfg.AI.Providers, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create provider registry: %w", err)
+	}
+
+	// Create the synthesis providers in fallback order
+	synthesis, err := provider.NewSynthesisChain(cfg.AI.Synthesizers, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create synthesis chain: %w", err)
+	}
 
 	// Create persona generators
 	generator := persona.NewGenerator(claudeClient, logger)
-	multiGenerator := multiprovider.NewGenerator(claudeClient, geminiClient, grokClient, gptClient, logger)
-
-	// Create prompt integration (enable if Gemini API key is available)
-	promptEnabled := cfg.AI.Gemini.APIKey != ""
-	promptIntegration := NewPromptPipelineIntegration(geminiClient, githubClient, logger, ".", promptEnabled)
+	multiGenerator := multiprovider.NewGenerator(providers, synthesis, logger)
+	multiGenerator.SetMaxContinuations(cfg.AI.MaxContinuations)
+	multiGenerator.SetQuorum(QuorumFromConfig(cfg.AI.Quorum))
+	multiGenerator.SetCompleteness(CompletenessFromConfig(cfg.AI.Completeness))
+	multiGenerator.SetArtifactsDir(cfg.Pipeline.ArtifactsDir)
+	multiGenerator.SetPublishThinking(cfg.Pipeline.PublishThinking)
+	multiGenerator.SetStructuredOutput(cfg.Pipeline.StructuredOutput)
+	multiGenerator.SetLinter(LinterFromConfig(cfg.Pipeline, logger))
+	multiGenerator.SetJudge(JudgeFromConfig(cfg, logger))
+	multiGenerator.SetAnalyzer(AnalyzerFromConfig(cfg.Pipeline, synthesis, logger))
+	factExtractor := FactExtractorFromConfig(cfg.Pipeline, synthesis, logger)
+	multiGenerator.SetFactExtractor(factExtractor)
+
+	// Create prompt integration (enable if a synthesis provider has an API key)
+	promptEnabled := cfg.AI.HasSynthesizerKey()
+	promptIntegration := NewPromptPipelineIntegration(synthesis, githubClient, logger, ".", promptEnabled)
+
+	// Regenerate the fact sheet along with the prompts when synthesized.md changes
+	if factExtractor != nil {
+		factService := facts.NewService(factExtractor, githubClient, cfg.AI.ProviderNames(), logger)
+		promptIntegration.RegisterAssetCallback(assets.AssetTypeFacts, factService.Generate)
+	}
 
 	p := &Pipeline{
 		config:            cfg,
@@ -111,6 +138,11 @@ func (p *Pipeline) Start(ctx context.Context) error {
 	return nil
 }
 
+// RunOnce runs a single pipeline iteration without starting the scheduler
+func (p *Pipeline) RunOnce(ctx context.Context) error {
+	return p.run(ctx)
+}
+
 func (p *Pipeline) run(ctx context.Context) error {
 	p.logger.Info("Running pipeline iteration")
 
@@ -262,12 +294,17 @@ func (p *Pipeline) processNewIssues(ctx context.Context) error {
 			p.logger.Infof("Issue #%d already processed, skipping", *issue.Number)
 			continue
 		}
+		if p.isPaused(issue) {
+			p.logger.Infof("Issue #%d is paused, skipping", *issue.Number)
+			continue
+		}
 
 		// Generate persona using multi-provider approach
 		persona, err := p.multiGenerator.ProcessIssue(ctx, issue)
 		if err != nil {
 			p.logger.Errorf("Failed to generate persona for issue #%d: %v",
 				*issue.Number, err)
+			p.handleQuorumFailure(ctx, *issue.Number, err)
 			continue
 		}
 
@@ -5,9 +5,9 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"github.com/twin2ai/studio/internal/assets"
-	"github.com/twin2ai/studio/internal/gemini"
 	"github.com/twin2ai/studio/internal/github"
 	"github.com/twin2ai/studio/internal/prompts"
+	"github.com/twin2ai/studio/internal/provider"
 )
 
 // PromptPipelineIntegration handles integration of prompt generation with the main pipeline
@@ -19,7 +19,7 @@ type PromptPipelineIntegration struct {
 }
 
 // NewPromptPipelineIntegration creates a new prompt pipeline integration
-func NewPromptPipelineIntegration(geminiClient *gemini.Client, githubClient *github.Client, logger *logrus.Logger, baseDir string, enabled bool) *PromptPipelineIntegration {
+func NewPromptPipelineIntegration(synthesis *provider.SynthesisChain, githubClient *github.Client, logger *logrus.Logger, baseDir string, enabled bool) *PromptPipelineIntegration {
 	if !enabled {
 		return &PromptPipelineIntegration{
 			enabled: false,
@@ -27,7 +27,7 @@ func NewPromptPipelineIntegration(geminiClient *gemini.Client, githubClient *git
 		}
 	}
 
-	githubService := prompts.NewGitHubService(geminiClient, githubClient, logger, baseDir)
+	githubService := prompts.NewGitHubService(synthesis, githubClient, logger, baseDir)
 
 	// Create monitor with GitHub integration for repository-wide monitoring
 	monitor := assets.NewMonitorWithGitHub(baseDir, logger, githubClient)
@@ -43,6 +43,14 @@ func NewPromptPipelineIntegration(geminiClient *gemini.Client, githubClient *git
 	}
 }
 
+// RegisterAssetCallback has the monitor generate another asset type, such
+// as the fact sheet, when it detects a change
+func (ppi *PromptPipelineIntegration) RegisterAssetCallback(assetType assets.AssetType, callback assets.AssetCallback) {
+	if ppi.enabled {
+		ppi.monitor.RegisterCallback(assetType, callback)
+	}
+}
+
 // ProcessPromptGeneration checks for and processes prompt generation triggers
 func (ppi *PromptPipelineIntegration) ProcessPromptGeneration(ctx context.Context) error {
 	if !ppi.enabled {
@@ -0,0 +1,186 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v57/github"
+
+	"github.com/twin2ai/studio/internal/config"
+	"github.com/twin2ai/studio/internal/multiprovider"
+)
+
+// QuorumFromConfig converts the configured quorum policy for the generator
+func QuorumFromConfig(cfg config.QuorumConfig) multiprovider.Quorum {
+	return multiprovider.Quorum{
+		MinProviders: cfg.MinProviders,
+		Required:     cfg.Required,
+		Retries:      cfg.Retries,
+	}
+}
+
+// isPaused reports whether an issue carries the quorum pause label
+func (p *Pipeline) isPaused(issue *github.Issue) bool {
+	for _, label := range issue.Labels {
+		if label.GetName() == p.config.AI.Quorum.PauseLabel {
+			return true
+		}
+	}
+	return false
+}
+
+// Hidden markers in the comments that record quorum misses and pauses, so
+// later runs can count the misses since the issue was last resumed
+const (
+	quorumMissMarker  = "<!-- studio:quorum-miss -->"
+	quorumPauseMarker = "<!-- studio:quorum-pause -->"
+)
+
+// handleQuorumFailure pauses an issue whose generation missed the provider
+// quorum when the pause policy is configured. Under the retry policy the
+// issue is left for the next run, with a comment counting the miss, until
+// it has missed the quorum MaxAttempts times; then it is paused too.
+func (p *Pipeline) handleQuorumFailure(ctx context.Context, issueNumber int, err error) {
+	var qerr *multiprovider.QuorumError
+	if !errors.As(err, &qerr) {
+		return
+	}
+
+	quorum := p.config.AI.Quorum
+	misses := 0
+	if quorum.OnFailure != "pause" && quorum.MaxAttempts > 0 {
+		var err error
+		misses, err = p.quorumMisses(ctx, issueNumber)
+		if err != nil {
+			p.logger.Warnf("Failed to count quorum misses of issue #%d: %v", issueNumber, err)
+			return
+		}
+	}
+
+	action, attempts := nextQuorumAction(quorum, misses)
+	switch action {
+	case quorumIgnore:
+		return
+	case quorumRetry:
+		p.comment(ctx, issueNumber, fmt.Sprintf("%s\n🔁 Not enough AI providers succeeded (attempt %d/%d): %v. Studio will try again on its next run.",
+			quorumMissMarker, attempts, quorum.MaxAttempts, qerr))
+		p.logger.Warnf("Issue #%d missed the provider quorum (attempt %d/%d), retrying next run", issueNumber, attempts, quorum.MaxAttempts)
+		return
+	}
+
+	_, _, labelErr := p.github.GetClient().Issues.AddLabelsToIssue(
+		ctx, p.config.GitHub.Owner, p.config.GitHub.Repo, issueNumber, []string{quorum.PauseLabel})
+	if labelErr != nil {
+		p.logger.Warnf("Failed to pause issue #%d: %v", issueNumber, labelErr)
+		return
+	}
+	p.comment(ctx, issueNumber, quorumComment(qerr, quorum.PauseLabel, attempts))
+
+	p.logger.Warnf("Paused issue #%d: %v", issueNumber, qerr)
+}
+
+// quorumAction is how an issue that missed the quorum is handled
+type quorumAction int
+
+const (
+	quorumIgnore quorumAction = iota // Leave the issue for the next run without counting
+	quorumRetry                      // Count the miss and leave the issue for the next run
+	quorumPause                      // Label the issue so runs skip it until it is resumed
+)
+
+// nextQuorumAction decides how to handle a quorum miss, given the misses
+// recorded since the issue was last paused. It also returns the attempt
+// this miss counts as, or 0 when the policy pauses on the first miss.
+func nextQuorumAction(quorum config.QuorumConfig, misses int) (quorumAction, int) {
+	if quorum.OnFailure == "pause" {
+		return quorumPause, 0
+	}
+	if quorum.MaxAttempts <= 0 {
+		return quorumIgnore, 0
+	}
+	attempts := misses + 1
+	if attempts < quorum.MaxAttempts {
+		return quorumRetry, attempts
+	}
+	return quorumPause, attempts
+}
+
+// countQuorumMisses adds the quorum misses in comments, oldest first, to
+// misses; a pause comment starts the count again
+func countQuorumMisses(misses int, comments []*github.IssueComment) int {
+	for _, comment := range comments {
+		switch {
+		case strings.Contains(comment.GetBody(), quorumPauseMarker):
+			misses = 0
+		case strings.Contains(comment.GetBody(), quorumMissMarker):
+			misses++
+		}
+	}
+	return misses
+}
+
+// quorumMisses counts the quorum misses recorded on an issue since it was
+// last paused
+func (p *Pipeline) quorumMisses(ctx context.Context, issueNumber int) (int, error) {
+	opts := &github.IssueListCommentsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	misses := 0
+	for {
+		comments, resp, err := p.github.GetClient().Issues.ListComments(
+			ctx, p.config.GitHub.Owner, p.config.GitHub.Repo, issueNumber, opts)
+		if err != nil {
+			return 0, fmt.Errorf("failed to list comments: %w", err)
+		}
+		misses = countQuorumMisses(misses, comments)
+		if resp.NextPage == 0 {
+			return misses, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// comment posts a comment on an issue, logging failures
+func (p *Pipeline) comment(ctx context.Context, issueNumber int, body string) {
+	_, _, err := p.github.GetClient().Issues.CreateComment(
+		ctx, p.config.GitHub.Owner, p.config.GitHub.Repo, issueNumber,
+		&github.IssueComment{Body: github.String(body)})
+	if err != nil {
+		p.logger.Warnf("Failed to comment on issue #%d: %v", issueNumber, err)
+	}
+}
+
+// quorumComment explains why an issue was paused; attempts is the number
+// of runs that missed the quorum, or 0 when it pauses on the first miss
+func quorumComment(qerr *multiprovider.QuorumError, label string, attempts int) string {
+	var failures strings.Builder
+	for _, resp := range qerr.Failed {
+		failures.WriteString(fmt.Sprintf("- **%s**: %v\n", resp.Provider, resp.Error))
+	}
+
+	succeeded := "none"
+	if len(qerr.Succeeded) > 0 {
+		succeeded = strings.Join(qerr.Succeeded, ", ")
+	}
+
+	requirements := fmt.Sprintf("at least %d successful providers", qerr.Quorum.MinProviders)
+	if len(qerr.Quorum.Required) > 0 {
+		requirements += fmt.Sprintf(", including %s", strings.Join(qerr.Quorum.Required, ", "))
+	}
+
+	missed := "Not enough AI providers succeeded"
+	if attempts > 0 {
+		missed = fmt.Sprintf("Not enough AI providers succeeded in %d attempts", attempts)
+	}
+
+	return fmt.Sprintf(`%s
+⏸️ Persona generation paused
+
+%s to synthesize a reliable persona. The quorum requires %s.
+
+**Succeeded:** %s
+
+**Failed:**
+%s
+Remove the `+"`%s`"+` label to try again.`, quorumPauseMarker, missed, requirements, succeeded, failures.String(), label)
+}
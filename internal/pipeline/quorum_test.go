@@ -0,0 +1,69 @@
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v57/github"
+
+	"github.com/twin2ai/studio/internal/config"
+)
+
+func TestNextQuorumAction(t *testing.T) {
+	retry := config.QuorumConfig{OnFailure: "retry", MaxAttempts: 3}
+
+	tests := []struct {
+		name     string
+		quorum   config.QuorumConfig
+		misses   int
+		action   quorumAction
+		attempts int
+	}{
+		{name: "pause policy pauses at once", quorum: config.QuorumConfig{OnFailure: "pause", MaxAttempts: 3}, action: quorumPause},
+		{name: "first miss is retried", quorum: retry, misses: 0, action: quorumRetry, attempts: 1},
+		{name: "second miss is retried", quorum: retry, misses: 1, action: quorumRetry, attempts: 2},
+		{name: "last attempt pauses", quorum: retry, misses: 2, action: quorumPause, attempts: 3},
+		{name: "misses past the limit pause", quorum: retry, misses: 7, action: quorumPause, attempts: 8},
+		{name: "no limit retries forever", quorum: config.QuorumConfig{OnFailure: "retry"}, misses: 50, action: quorumIgnore},
+		{name: "single attempt pauses on the first miss", quorum: config.QuorumConfig{OnFailure: "retry", MaxAttempts: 1}, action: quorumPause, attempts: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			action, attempts := nextQuorumAction(tt.quorum, tt.misses)
+			if action != tt.action || attempts != tt.attempts {
+				t.Errorf("nextQuorumAction() = (%v, %d), want (%v, %d)", action, attempts, tt.action, tt.attempts)
+			}
+		})
+	}
+}
+
+func TestCountQuorumMisses(t *testing.T) {
+	comment := func(body string) *github.IssueComment {
+		return &github.IssueComment{Body: github.String(body)}
+	}
+	miss := comment(quorumMissMarker + "\n🔁 Not enough AI providers succeeded")
+	pause := comment(quorumPauseMarker + "\n⏸️ Persona generation paused")
+	other := comment("Please add more detail on her early life")
+
+	tests := []struct {
+		name     string
+		misses   int
+		comments []*github.IssueComment
+		want     int
+	}{
+		{name: "no comments", want: 0},
+		{name: "misses counted", comments: []*github.IssueComment{miss, other, miss}, want: 2},
+		{name: "pause resets the count", comments: []*github.IssueComment{miss, miss, pause, miss}, want: 1},
+		{name: "resumed after a pause", comments: []*github.IssueComment{miss, pause}, want: 0},
+		{name: "carried over from the previous page", misses: 2, comments: []*github.IssueComment{other, miss}, want: 3},
+		{name: "pause on a later page", misses: 4, comments: []*github.IssueComment{pause}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := countQuorumMisses(tt.misses, tt.comments); got != tt.want {
+				t.Errorf("countQuorumMisses() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
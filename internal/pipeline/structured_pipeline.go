@@ -6,8 +6,10 @@ import (
 	"strings"
 
 	"github.com/google/go-github/v57/github"
+	"github.com/twin2ai/studio/internal/analysis"
 	githubclient "github.com/twin2ai/studio/internal/github"
 	"github.com/twin2ai/studio/internal/parser"
+	"github.com/twin2ai/studio/internal/review"
 	"github.com/twin2ai/studio/pkg/models"
 )
 
@@ -29,6 +31,10 @@ func (p *Pipeline) processNewIssuesWithStructure(ctx context.Context) error {
 			p.logger.Infof("Issue #%d already processed, skipping", *issue.Number)
 			continue
 		}
+		if p.isPaused(issue) {
+			p.logger.Infof("Issue #%d is paused, skipping", *issue.Number)
+			continue
+		}
 
 		// Parse the issue using the new template format
 		parsedIssue, parseErr := parser.ParsePersonaIssue(issue)
@@ -67,6 +73,7 @@ func (p *Pipeline) processNewIssuesWithStructure(ctx context.Context) error {
 		if err != nil {
 			p.logger.Errorf("Failed to generate persona package for issue #%d: %v",
 				*issue.Number, err)
+			p.handleQuorumFailure(ctx, *issue.Number, err)
 			continue
 		}
 
@@ -186,7 +193,7 @@ func (p *Pipeline) processPRCommentsWithStructure(ctx context.Context) error {
 		}
 
 		// Update all files in the PR
-		err = p.updateStructuredPR(ctx, pr, updatedPersona, updatedFiles)
+		err = p.updateStructuredPR(ctx, pr, existingPersona, updatedPersona, updatedFiles, unprocessedFeedback)
 		if err != nil {
 			p.logger.Errorf("Failed to update structured PR #%d: %v", *pr.Number, err)
 			continue
@@ -245,8 +252,9 @@ func (p *Pipeline) getExistingStructuredPersonaContent(ctx context.Context, pr *
 	return content, nil
 }
 
-// updateStructuredPR updates all files in a structured PR
-func (p *Pipeline) updateStructuredPR(ctx context.Context, pr *github.PullRequest, persona *models.Persona, files *githubclient.PersonaFiles) error {
+// updateStructuredPR updates all files in a structured PR and comments on
+// what changed since previous, the synthesized persona it replaces
+func (p *Pipeline) updateStructuredPR(ctx context.Context, pr *github.PullRequest, previous string, persona *models.Persona, files *githubclient.PersonaFiles, feedback []string) error {
 	if pr.Head == nil || pr.Head.Ref == nil {
 		return fmt.Errorf("PR head branch information is missing")
 	}
@@ -269,22 +277,59 @@ func (p *Pipeline) updateStructuredPR(ctx context.Context, pr *github.PullReques
 	baseFolder := fmt.Sprintf("personas/%s", folderName)
 
 	// Define all files to update
-	fileUpdates := []struct {
+	type fileUpdate struct {
 		path    string
 		content string
 		message string
-	}{
-		// Raw AI outputs
-		{fmt.Sprintf("%s/raw/claude.md", baseFolder), files.ClaudeRaw, "Update Claude's raw output"},
-		{fmt.Sprintf("%s/raw/gemini.md", baseFolder), files.GeminiRaw, "Update Gemini's raw output"},
-		{fmt.Sprintf("%s/raw/grok.md", baseFolder), files.GrokRaw, "Update Grok's raw output"},
-		{fmt.Sprintf("%s/raw/gpt.md", baseFolder), files.GPTRaw, "Update GPT-4's raw output"},
+	}
 
-		// Main files
-		{fmt.Sprintf("%s/synthesized.md", baseFolder), files.FullSynthesis, "Update synthesized persona"},
+	// Raw AI outputs
+	var fileUpdates []fileUpdate
+	var providerNames []string
+	for _, raw := range files.RawOutputs {
+		fileUpdates = append(fileUpdates, fileUpdate{
+			fmt.Sprintf("%s/raw/%s.md", baseFolder, raw.Provider), raw.Content, fmt.Sprintf("Update %s's raw output", raw.Provider)})
+		if raw.Thinking != "" {
+			fileUpdates = append(fileUpdates, fileUpdate{
+				fmt.Sprintf("%s/raw/%s_thinking.md", baseFolder, raw.Provider), raw.Thinking, fmt.Sprintf("Update %s's extended thinking", raw.Provider)})
+		}
+		providerNames = append(providerNames, raw.Provider)
 	}
 
-	// Update each file
+	// Main files
+	fileUpdates = append(fileUpdates, fileUpdate{
+		fmt.Sprintf("%s/synthesized.md", baseFolder), files.FullSynthesis, "Update synthesized persona"})
+	if files.PersonaJSON != "" {
+		fileUpdates = append(fileUpdates, fileUpdate{
+			fmt.Sprintf("%s/persona.json", baseFolder), files.PersonaJSON, "Update structured persona"})
+	}
+	if files.Facts != "" {
+		fileUpdates = append(fileUpdates, fileUpdate{
+			fmt.Sprintf("%s/%s", baseFolder, models.FactsFile), files.Facts, "Update fact sheet"})
+	}
+	if files.Disagreements != nil {
+		fileUpdates = append(fileUpdates, fileUpdate{
+			fmt.Sprintf("%s/%s", baseFolder, analysis.File), files.Disagreements.Document(), "Update provider disagreement report"})
+	}
+
+	// Replace the metadata of the earlier synthesis, such as its scores
+	if files.AssetStatus != nil {
+		statusContent, err := p.github.AssetStatusJSON(files.AssetStatus)
+		if err != nil {
+			p.logger.Warnf("Failed to generate asset status JSON: %v", err)
+		} else {
+			fileUpdates = append(fileUpdates, fileUpdate{
+				fmt.Sprintf("%s/.assets_status.json", baseFolder), statusContent, "Update asset generation status"})
+		}
+	}
+
+	// Snapshot the regenerated version into history/ and the changelog
+	version := models.Version{Trigger: feedbackTrigger(*pr.Number, feedback), Provenance: persona.Provenance}
+	if _, err := p.github.RecordVersion(ctx, folderName, branchName, persona.Name, files.FullSynthesis, version); err != nil {
+		p.logger.Warnf("Failed to record version history: %v", err)
+	}
+
+	// Update each file, creating the ones an earlier run did not produce
 	for _, update := range fileUpdates {
 		fileOpts := &github.RepositoryContentFileOptions{
 			Message: github.String(fmt.Sprintf("%s (addressing feedback)", update.message)),
@@ -313,24 +358,50 @@ func (p *Pipeline) updateStructuredPR(ctx context.Context, pr *github.PullReques
 				continue
 			}
 		} else {
-			p.logger.Warnf("File %s not found, skipping update", update.path)
-			continue
+			_, _, err = p.github.GetClient().Repositories.CreateFile(
+				ctx,
+				p.config.GitHub.PersonasOwner,
+				p.config.GitHub.PersonasRepo,
+				update.path,
+				fileOpts)
+			if err != nil {
+				p.logger.Warnf("Failed to create file %s: %v", update.path, err)
+				continue
+			}
 		}
 
-		p.logger.Debugf("Updated file: %s", update.path)
+		p.logger.Debugf("Created/updated file: %s", update.path)
+	}
+
+	// Show reviewers which sections changed and whether their feedback landed
+	validationReport := p.sectionChanges(*pr.Number, previous, files.FullSynthesis, feedback)
+	if files.Validation != nil {
+		validationReport += fmt.Sprintf("## ✅ Validation\n%s\n", files.Validation.Markdown())
+	}
+	if files.Evaluation != nil {
+		validationReport += fmt.Sprintf("## ⚖️ Evaluation\n%s\n", files.Evaluation.Markdown())
+		if files.Evaluation.Flagged {
+			if _, _, err := p.github.GetClient().Issues.AddLabelsToIssue(ctx, p.config.GitHub.PersonasOwner,
+				p.config.GitHub.PersonasRepo, *pr.Number, []string{githubclient.NeedsReviewLabel}); err != nil {
+				p.logger.Warnf("Failed to label PR for review: %v", err)
+			}
+		}
+	}
+	if files.Disagreements != nil {
+		validationReport += fmt.Sprintf("## 🔍 Provider Disagreements\n%s\n", files.Disagreements.Markdown())
 	}
 
 	// Add a comment to the PR indicating the update
 	comment := fmt.Sprintf(`🔄 **Persona Package Updated**
 
 All files in the persona package have been regenerated to address the feedback provided:
-- Raw outputs from all 4 AI providers
+- Raw outputs from %d AI providers (%s)
 - Synthesized persona
 
 The complete package has been updated to incorporate your suggestions.
 
----
-*Updated automatically by [Studio](https://github.com/twin2ai/studio)*`)
+%s---
+*Updated automatically by [Studio](https://github.com/twin2ai/studio)*`, len(providerNames), strings.Join(providerNames, ", "), validationReport)
 
 	_, _, err := p.github.GetClient().Issues.CreateComment(
 		ctx,
@@ -345,6 +416,42 @@ The complete package has been updated to incorporate your suggestions.
 	return nil
 }
 
+// feedbackTrigger describes a feedback regeneration for the changelog,
+// quoting the first line of the first comment
+func feedbackTrigger(prNumber int, feedback []string) string {
+	trigger := fmt.Sprintf("feedback on PR #%d", prNumber)
+	if len(feedback) == 0 {
+		return trigger
+	}
+	quote := strings.TrimSpace(feedback[0])
+	if i := strings.IndexByte(quote, '\n'); i >= 0 {
+		quote = strings.TrimSpace(quote[:i])
+	}
+	if runes := []rune(quote); len(runes) > 80 {
+		quote = string(runes[:77]) + "..."
+	}
+	trigger += fmt.Sprintf(": %q", quote)
+	if len(feedback) > 1 {
+		trigger += fmt.Sprintf(" and %d more", len(feedback)-1)
+	}
+	return trigger
+}
+
+// sectionChanges renders a section-level diff of a feedback regeneration
+// for the update comment, mapping each feedback comment to the sections it
+// targets
+func (p *Pipeline) sectionChanges(prNumber int, before, after string, feedback []string) string {
+	report, err := review.Compare(before, after, feedback)
+	if err != nil {
+		p.logger.Warnf("Failed to diff regenerated persona for PR #%d: %v", prNumber, err)
+		return ""
+	}
+	if unaddressed := report.Unaddressed(); len(unaddressed) > 0 {
+		p.logger.Warnf("PR #%d: %d feedback comments may not be addressed", prNumber, len(unaddressed))
+	}
+	return fmt.Sprintf("## 📝 What Changed\n%s\n", report.Markdown())
+}
+
 // runWithStructure runs the pipeline with structured PR support
 func (p *Pipeline) runWithStructure(ctx context.Context) error {
 	p.logger.Info("Running structured pipeline iteration")
@@ -45,7 +45,9 @@ func (p *Pipeline) ProcessPersonaUpdate(ctx context.Context, request UpdatePerso
 		request.PersonaName,
 		existingPersona,
 		request.UserPersona,
-		synthesizedPersona,
+		synthesizedPersona.Text,
+		synthesizedPersona.Provider,
+		synthesizedPersona.Model,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create update PR: %w", err)
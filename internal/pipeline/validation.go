@@ -0,0 +1,29 @@
+package pipeline
+
+import (
+	"github.com/sirupsen/logrus"
+
+	"github.com/twin2ai/studio/internal/completeness"
+	"github.com/twin2ai/studio/internal/config"
+	"github.com/twin2ai/studio/internal/validation"
+)
+
+// LinterFromConfig loads the persona linter from the persona template. It
+// returns nil, turning validation off, when the template cannot be read.
+func LinterFromConfig(cfg config.PipelineConfig, logger *logrus.Logger) *validation.Linter {
+	linter, err := validation.LoadLinter(validation.DefaultTemplate, validation.Options{MinWords: cfg.ValidationMinWords})
+	if err != nil {
+		logger.Warnf("Persona validation disabled: %v", err)
+		return nil
+	}
+	return linter
+}
+
+// CompletenessFromConfig converts the configured completeness policy for the generator
+func CompletenessFromConfig(cfg config.CompletenessConfig) completeness.Policy {
+	return completeness.Policy{
+		Retries:        cfg.Retries,
+		MinSizePercent: cfg.MinSizePercent,
+		BySection:      cfg.BySection,
+	}
+}
@@ -9,7 +9,9 @@ import (
 	"time"
 
 	"github.com/sirupsen/logrus"
-	"github.com/twin2ai/studio/internal/gemini"
+	"github.com/twin2ai/studio/internal/llm"
+	"github.com/twin2ai/studio/internal/provider"
+	"github.com/twin2ai/studio/internal/usage"
 )
 
 // PromptType represents different types of prompts that can be generated
@@ -34,22 +36,25 @@ type PromptResult struct {
 	Content     string
 	GeneratedAt time.Time
 	PersonaName string
-	Error       error
+	// Provider and Model identify the synthesizer that wrote the prompt
+	Provider string
+	Model    string
+	Error    error
 }
 
-// Generator handles prompt generation using Gemini Flash
+// Generator handles prompt generation using the synthesis chain
 type Generator struct {
-	geminiClient *gemini.Client
-	logger       *logrus.Logger
-	baseDir      string
+	synthesis *provider.SynthesisChain
+	logger    *logrus.Logger
+	baseDir   string
 }
 
 // NewGenerator creates a new prompt generator
-func NewGenerator(geminiClient *gemini.Client, logger *logrus.Logger, baseDir string) *Generator {
+func NewGenerator(synthesis *provider.SynthesisChain, logger *logrus.Logger, baseDir string) *Generator {
 	return &Generator{
-		geminiClient: geminiClient,
-		logger:       logger,
-		baseDir:      baseDir,
+		synthesis: synthesis,
+		logger:    logger,
+		baseDir:   baseDir,
 	}
 }
 
@@ -91,6 +96,7 @@ func (g *Generator) GenerateAllPrompts(ctx context.Context, personaName string,
 // GeneratePrompt generates a specific prompt type for a persona
 func (g *Generator) GeneratePrompt(ctx context.Context, personaName string, synthesizedContent string, promptType PromptType) (*PromptResult, error) {
 	g.logger.Debugf("Generating %s prompt for %s", promptType, personaName)
+	ctx = usage.WithLabels(ctx, usage.Labels{Issue: usage.LabelsFrom(ctx).Issue, Persona: personaName, Stage: usage.StagePrompt})
 
 	// Load the prompt template
 	template, err := g.loadPromptTemplate(promptType)
@@ -101,17 +107,19 @@ func (g *Generator) GeneratePrompt(ctx context.Context, personaName string, synt
 	// Replace placeholder with synthesized content
 	promptInput := strings.ReplaceAll(template, "{{SYNTHESIZED_PERSONA}}", synthesizedContent)
 
-	// Generate using Gemini Flash
-	content, err := g.geminiClient.GeneratePersonaPrompt(ctx, promptInput)
+	// Generate using the synthesis chain's prompt settings
+	generated, err := g.synthesis.Complete(ctx, llm.Prompt(promptInput), llm.StagePrompt)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate prompt with Gemini: %w", err)
+		return nil, fmt.Errorf("failed to generate prompt: %w", err)
 	}
 
 	return &PromptResult{
 		PromptType:  promptType,
-		Content:     content,
+		Content:     generated.Text,
 		GeneratedAt: time.Now(),
 		PersonaName: personaName,
+		Provider:    generated.Provider,
+		Model:       generated.Model,
 		Error:       nil,
 	}, nil
 }
@@ -10,8 +10,8 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"github.com/twin2ai/studio/internal/assets"
-	"github.com/twin2ai/studio/internal/gemini"
 	"github.com/twin2ai/studio/internal/github"
+	"github.com/twin2ai/studio/internal/provider"
 )
 
 // GitHubService handles prompt generation with GitHub PR integration
@@ -22,8 +22,8 @@ type GitHubService struct {
 }
 
 // NewGitHubService creates a new GitHub-integrated prompt service
-func NewGitHubService(geminiClient *gemini.Client, githubClient *github.Client, logger *logrus.Logger, baseDir string) *GitHubService {
-	promptService := NewService(geminiClient, logger, baseDir)
+func NewGitHubService(synthesis *provider.SynthesisChain, githubClient *github.Client, logger *logrus.Logger, baseDir string) *GitHubService {
+	promptService := NewService(synthesis, logger, baseDir)
 
 	return &GitHubService{
 		promptService: promptService,
@@ -555,7 +555,7 @@ func (gs *GitHubService) generateAssetStatus(personaName string, promptResults [
 		AssetGenerationFlags:  assetFlags,
 		Metadata: map[string]string{
 			"generator": "studio-prompt-pipeline",
-			"model":     "gemini-2.5-flash",
+			"model":     promptModel(promptResults),
 			"version":   "1.0",
 		},
 	}
@@ -643,7 +643,7 @@ func (gs *GitHubService) updateAssetStatus(existingContent string, personaName s
 	}
 	status.Metadata["last_prompt_generation"] = now.Format(time.RFC3339)
 	status.Metadata["prompt_generator"] = "studio-prompt-pipeline"
-	status.Metadata["prompt_model"] = "gemini-2.5-flash"
+	status.Metadata["prompt_model"] = promptModel(promptResults)
 
 	// Serialize to JSON
 	data, err := json.MarshalIndent(status, "", "  ")
@@ -653,3 +653,13 @@ func (gs *GitHubService) updateAssetStatus(existingContent string, personaName s
 
 	return string(data), nil
 }
+
+// promptModel returns the model that wrote the first successful prompt
+func promptModel(results []PromptResult) string {
+	for _, result := range results {
+		if result.Error == nil && result.Model != "" {
+			return result.Model
+		}
+	}
+	return ""
+}
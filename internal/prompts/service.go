@@ -9,14 +9,14 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"github.com/twin2ai/studio/internal/assets"
-	"github.com/twin2ai/studio/internal/gemini"
+	"github.com/twin2ai/studio/internal/provider"
 )
 
 // Service handles prompt generation and integration with the asset system
 type Service struct {
 	generator    *Generator
 	repository   *RepositoryManager
-	geminiClient *gemini.Client
+	synthesis    *provider.SynthesisChain
 	githubClient GitHubPRClient // Optional GitHub client for PR creation
 	logger       *logrus.Logger
 	baseDir      string
@@ -29,14 +29,14 @@ type GitHubPRClient interface {
 }
 
 // NewService creates a new prompt generation service without GitHub integration
-func NewService(geminiClient *gemini.Client, logger *logrus.Logger, baseDir string) *Service {
-	generator := NewGenerator(geminiClient, logger, baseDir)
+func NewService(synthesis *provider.SynthesisChain, logger *logrus.Logger, baseDir string) *Service {
+	generator := NewGenerator(synthesis, logger, baseDir)
 	repository := NewRepositoryManager(baseDir, logger)
 
 	return &Service{
 		generator:    generator,
 		repository:   repository,
-		geminiClient: geminiClient,
+		synthesis:    synthesis,
 		githubClient: nil,
 		logger:       logger,
 		baseDir:      baseDir,
@@ -45,14 +45,14 @@ func NewService(geminiClient *gemini.Client, logger *logrus.Logger, baseDir stri
 }
 
 // NewServiceWithGitHub creates a new prompt generation service with GitHub PR integration
-func NewServiceWithGitHub(geminiClient *gemini.Client, githubClient GitHubPRClient, logger *logrus.Logger, baseDir string) *Service {
-	generator := NewGenerator(geminiClient, logger, baseDir)
+func NewServiceWithGitHub(synthesis *provider.SynthesisChain, githubClient GitHubPRClient, logger *logrus.Logger, baseDir string) *Service {
+	generator := NewGenerator(synthesis, logger, baseDir)
 	repository := NewRepositoryManager(baseDir, logger)
 
 	return &Service{
 		generator:    generator,
 		repository:   repository,
-		geminiClient: geminiClient,
+		synthesis:    synthesis,
 		githubClient: githubClient,
 		logger:       logger,
 		baseDir:      baseDir,
@@ -0,0 +1,205 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/twin2ai/studio/internal/claude"
+	"github.com/twin2ai/studio/internal/config"
+	"github.com/twin2ai/studio/internal/gemini"
+	"github.com/twin2ai/studio/internal/llm"
+	"github.com/twin2ai/studio/internal/openai"
+)
+
+// configureSender names a client's sender after the provider and applies
+// the configured retry count and rate limit
+func configureSender(sender *llm.Sender, cfg config.ProviderConfig) {
+	sender.SetName(cfg.Name)
+	llm.SetRateLimit(cfg.Name, cfg.RateLimit)
+
+	policy := sender.RetryPolicy()
+	policy.MaxRetries = cfg.MaxRetries
+	sender.SetRetryPolicy(policy)
+}
+
+// claudeProvider uses Claude's own prompt template handling
+type claudeProvider struct {
+	name   string
+	model  string
+	stream bool
+	client *claude.Client
+}
+
+func newClaudeProvider(cfg config.ProviderConfig, logger *logrus.Logger) (Provider, error) {
+	client := claude.NewClient(cfg.APIKey, cfg.Model, logger)
+	client.SetParams(cfg.Params)
+	configureSender(client.Sender(), cfg)
+
+	return &claudeProvider{
+		name:   cfg.Name,
+		model:  cfg.Model,
+		stream: cfg.Stream,
+		client: client,
+	}, nil
+}
+
+func (p *claudeProvider) Name() string  { return p.name }
+func (p *claudeProvider) Model() string { return p.model }
+
+func (p *claudeProvider) TestConnection(ctx context.Context) error {
+	return p.client.TestConnection(ctx)
+}
+
+func (p *claudeProvider) ListAvailableModels(ctx context.Context) ([]string, error) {
+	return p.client.ListAvailableModels(ctx)
+}
+
+func (p *claudeProvider) Generate(ctx context.Context, req Request) (*llm.Result, error) {
+	prompt, err := p.client.BuildPersonaPrompt(req.IssueContent, req.Template)
+	if err != nil {
+		return nil, err
+	}
+	conv := Conversation(prompt, req)
+	if p.stream {
+		return p.client.SendStream(ctx, conv, req.Stage, req.OnDelta)
+	}
+	return p.client.Send(ctx, conv, req.Stage)
+}
+
+func (p *claudeProvider) Complete(ctx context.Context, req llm.Request, stage llm.Stage) (*llm.Result, error) {
+	return p.client.Send(ctx, req, stage)
+}
+
+// geminiProvider can handle the full prompt including the template
+type geminiProvider struct {
+	name   string
+	model  string
+	stream bool
+	client *gemini.Client
+}
+
+func newGeminiProvider(cfg config.ProviderConfig, logger *logrus.Logger) (Provider, error) {
+	client := gemini.NewClient(cfg.APIKey, cfg.Model, logger)
+	client.SetParams(cfg.Params)
+	configureSender(client.Sender(), cfg)
+
+	return &geminiProvider{
+		name:   cfg.Name,
+		model:  cfg.Model,
+		stream: cfg.Stream,
+		client: client,
+	}, nil
+}
+
+func (p *geminiProvider) Name() string  { return p.name }
+func (p *geminiProvider) Model() string { return p.model }
+
+func (p *geminiProvider) TestConnection(ctx context.Context) error {
+	return p.client.TestConnection(ctx)
+}
+
+func (p *geminiProvider) ListAvailableModels(ctx context.Context) ([]string, error) {
+	return p.client.ListAvailableModels(ctx)
+}
+
+func (p *geminiProvider) Generate(ctx context.Context, req Request) (*llm.Result, error) {
+	conv := Conversation(FullPrompt(req), req)
+	if p.stream {
+		return p.client.SendStream(ctx, conv, req.Stage, req.OnDelta)
+	}
+	return p.client.Send(ctx, conv, req.Stage)
+}
+
+func (p *geminiProvider) Complete(ctx context.Context, req llm.Request, stage llm.Stage) (*llm.Result, error) {
+	if stage == llm.StagePrompt {
+		// Prompt generation keeps Gemini Flash with relaxed safety settings
+		text, err := p.client.GeneratePersonaPrompt(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return &llm.Result{Text: text, StopReason: llm.StopEnd, Model: gemini.PromptModel}, nil
+	}
+	return p.client.Send(ctx, req, stage)
+}
+
+// openaiProvider talks to any OpenAI-compatible chat-completions API
+type openaiProvider struct {
+	name   string
+	model  string
+	client *openai.Client
+	prompt func(Request) string
+}
+
+// openaiPreset holds the defaults that distinguish the grok and gpt types
+// from a plain OpenAI-compatible endpoint
+type openaiPreset struct {
+	baseURL   string
+	maxTokens int
+	prompt    func(Request) string
+}
+
+var openaiPresets = map[string]openaiPreset{
+	// Grok can handle the full prompt including the template
+	"grok": {baseURL: "https://api.x.ai/v1", maxTokens: 20000, prompt: FullPrompt},
+	// GPT uses a shorter prompt to avoid context length issues
+	"gpt":    {baseURL: openai.DefaultBaseURL, maxTokens: 4000, prompt: ShortPrompt},
+	"openai": {baseURL: openai.DefaultBaseURL, maxTokens: 4000, prompt: FullPrompt},
+}
+
+func newOpenAIProvider(cfg config.ProviderConfig, logger *logrus.Logger) (Provider, error) {
+	preset, ok := openaiPresets[cfg.Type]
+	if !ok {
+		preset = openaiPresets["openai"]
+	}
+
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = preset.baseURL
+	}
+
+	if cfg.Model == "" {
+		return nil, fmt.Errorf("no model configured (set %s_MODEL)", config.EnvPrefix(cfg.Name))
+	}
+
+	client := openai.NewClient(openai.Options{
+		Name:        cfg.Name,
+		BaseURL:     baseURL,
+		APIKey:      cfg.APIKey,
+		Model:       cfg.Model,
+		Headers:     cfg.Headers,
+		AuthHeader:  cfg.AuthHeader,
+		AuthScheme:  cfg.AuthScheme,
+		MaxTokens:   preset.maxTokens,
+		Temperature: 0.7,
+	}, logger)
+	client.SetParams(cfg.Params)
+	configureSender(client.Sender(), cfg)
+
+	return &openaiProvider{
+		name:   cfg.Name,
+		model:  cfg.Model,
+		client: client,
+		prompt: preset.prompt,
+	}, nil
+}
+
+func (p *openaiProvider) Name() string  { return p.name }
+func (p *openaiProvider) Model() string { return p.model }
+
+func (p *openaiProvider) TestConnection(ctx context.Context) error {
+	return p.client.TestConnection(ctx)
+}
+
+func (p *openaiProvider) ListAvailableModels(ctx context.Context) ([]string, error) {
+	return p.client.ListAvailableModels(ctx)
+}
+
+func (p *openaiProvider) Generate(ctx context.Context, req Request) (*llm.Result, error) {
+	return p.client.Send(ctx, Conversation(p.prompt(req), req), req.Stage)
+}
+
+func (p *openaiProvider) Complete(ctx context.Context, req llm.Request, stage llm.Stage) (*llm.Result, error) {
+	return p.client.Send(ctx, req, stage)
+}
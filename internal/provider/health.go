@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/twin2ai/studio/internal/llm"
+)
+
+// probeTimeout bounds a connection test so a hanging provider cannot stall a run
+const probeTimeout = 60 * time.Second
+
+// Checker is a provider whose API can be checked without generating a persona
+type Checker interface {
+	// TestConnection sends a minimal request to the configured model
+	TestConnection(ctx context.Context) error
+	// ListAvailableModels returns the models the API key can use
+	ListAvailableModels(ctx context.Context) ([]string, error)
+}
+
+// Health is the result of checking one provider
+type Health struct {
+	Provider string
+	Model    string
+	Latency  time.Duration
+	Err      error    // Connection test error, nil when the provider answered
+	Models   []string // Models reported by the API, when listed
+	Breaker  llm.BreakerStatus
+}
+
+// CheckHealth tests the connection of each provider, optionally listing its
+// models, and reports its breaker state. The tests bypass the breakers, so
+// a provider whose breaker is open is still tested and its breaker is left
+// as the pipeline recorded it.
+func CheckHealth(ctx context.Context, providers []Provider, listModels bool, logger *logrus.Logger) []Health {
+	var results []Health
+	for _, p := range providers {
+		health := Health{Provider: p.Name(), Model: p.Model()}
+
+		checker, ok := p.(Checker)
+		if !ok {
+			health.Err = fmt.Errorf("provider does not support health checks")
+			results = append(results, health)
+			continue
+		}
+
+		checkCtx, cancel := context.WithTimeout(llm.WithoutBreaker(ctx), probeTimeout)
+		started := time.Now()
+		health.Err = checker.TestConnection(checkCtx)
+		health.Latency = time.Since(started)
+		if listModels && health.Err == nil {
+			models, err := checker.ListAvailableModels(checkCtx)
+			if err != nil {
+				logger.Warnf("Failed to list models for %s: %v", p.Name(), err)
+			}
+			health.Models = models
+		}
+		cancel()
+
+		health.Breaker = llm.BreakerFor(p.Name(), logger).Status()
+		results = append(results, health)
+	}
+	return results
+}
+
+// ProbeOpen tests the connection of every provider whose breaker has
+// finished its cool-down, closing the breaker if the provider answers
+func (r *Registry) ProbeOpen(ctx context.Context, logger *logrus.Logger) {
+	for _, p := range r.providers {
+		checker, ok := p.(Checker)
+		if !ok || !llm.BreakerFor(p.Name(), logger).ProbeDue() {
+			continue
+		}
+
+		probeCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+		if err := checker.TestConnection(probeCtx); err != nil {
+			logger.Warnf("[%s] Probe failed, provider stays unavailable: %v", p.Name(), err)
+		}
+		cancel()
+	}
+}
+
+// WriteHealthReport prints one line per provider with its connection and breaker state
+func WriteHealthReport(w io.Writer, results []Health) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PROVIDER\tMODEL\tSTATUS\tLATENCY\tBREAKER\tDETAILS")
+	for _, health := range results {
+		status, details := "ok", ""
+		if health.Err != nil {
+			status, details = "failed", health.Err.Error()
+		}
+
+		breaker := string(health.Breaker.State)
+		switch {
+		case health.Breaker.State == llm.BreakerOpen && time.Now().Before(health.Breaker.RetryAt):
+			breaker += " until " + health.Breaker.RetryAt.Format(time.Kitchen)
+		case health.Breaker.State == llm.BreakerOpen:
+			breaker += ", probe due"
+		case health.Breaker.Failures > 0:
+			breaker += fmt.Sprintf(" (%d failed)", health.Breaker.Failures)
+		}
+		if details == "" && health.Breaker.State != llm.BreakerClosed && health.Breaker.LastError != "" {
+			details = "last error: " + health.Breaker.LastError
+		}
+		if len(health.Models) > 0 {
+			details = fmt.Sprintf("%d models: %s", len(health.Models), strings.Join(health.Models, ", "))
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", health.Provider, health.Model, status,
+			health.Latency.Round(time.Millisecond), breaker, truncate(details, 120))
+	}
+	tw.Flush()
+}
+
+// truncate shortens s to at most n characters
+func truncate(s string, n int) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+	if len(s) <= n {
+		return s
+	}
+	return s[:n-3] + "..."
+}
@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/twin2ai/studio/internal/llm"
+	"github.com/twin2ai/studio/pkg/schema"
+)
+
+// CompleteJSON runs req along the chain and decodes the JSON object in the
+// reply into v after validating it against req.Schema. check, when not nil,
+// runs after decoding for rules the schema cannot express. Invalid replies
+// are sent back with the problems found so the model can correct them, up
+// to attempts requests in all. The returned Synthesis is the last reply,
+// also when it was invalid; it is nil only when the chain itself failed.
+func (c *SynthesisChain) CompleteJSON(ctx context.Context, req llm.Request, stage llm.Stage, attempts int, v interface{}, check func() error) (*Synthesis, error) {
+	parsed, err := schema.Parse(req.Schema)
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		synthesis, err := c.Complete(ctx, req, stage)
+		if err != nil {
+			return nil, err
+		}
+
+		err = parsed.Decode(synthesis.Text, v)
+		if err == nil && check != nil {
+			err = check()
+		}
+		if err == nil {
+			return synthesis, nil
+		}
+		if attempt >= attempts {
+			return synthesis, err
+		}
+		c.logger.Warnf("%s returned invalid JSON (attempt %d/%d): %v", synthesis.Provider, attempt, attempts, err)
+		req = req.Assistant(synthesis.Text).User(correctionPrompt(err))
+	}
+}
+
+// correctionPrompt asks the model to fix the problems found in its reply
+func correctionPrompt(err error) string {
+	problems := []string{err.Error()}
+	var verr *schema.ValidationError
+	if errors.As(err, &verr) {
+		problems = verr.Problems
+	}
+	return fmt.Sprintf("That reply is invalid:\n- %s\n\nReply with the corrected JSON object only.", strings.Join(problems, "\n- "))
+}
@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/twin2ai/studio/internal/llm"
+)
+
+// Provider generates a persona draft from an issue. Each configured
+// provider contributes one raw output that is later synthesized.
+type Provider interface {
+	// Name returns the unique provider name used for artifacts and raw/<name>.md
+	Name() string
+	// Model returns the model the provider is configured to use
+	Model() string
+	// Generate produces a persona draft for the request
+	Generate(ctx context.Context, req Request) (*llm.Result, error)
+}
+
+// Request carries the issue context shared by all providers
+type Request struct {
+	IssueContent string
+	Template     string
+
+	// System holds the generation instructions sent as the system prompt;
+	// empty sends none
+	System string
+
+	// Turns continue the conversation after the generation prompt, such as
+	// the previous persona and the feedback on it. They alternate between
+	// assistant and user and end with a user message.
+	Turns []llm.Message
+
+	// Stage selects the configured generation settings (generation or
+	// feedback); empty uses the generation settings
+	Stage llm.Stage
+
+	// Partial holds the output so far when a truncated response is being
+	// continued; empty for the initial request
+	Partial string
+
+	// OnDelta, if set, receives text as it is streamed by providers that
+	// support streaming
+	OnDelta func(text string)
+}
+
+// FullPrompt combines the issue content with the persona template
+func FullPrompt(req Request) string {
+	if req.Template == "" {
+		return req.IssueContent
+	}
+	return fmt.Sprintf("%s\n\nUse this template as a guide:\n%s", req.IssueContent, req.Template)
+}
+
+// continuationPrompt asks for the rest of a response cut off at the output limit
+const continuationPrompt = `Your previous response was cut off because it reached the output limit. Continue exactly where it stopped. Do not repeat any earlier content, do not add a preamble or commentary, and keep the same formatting.`
+
+// Conversation builds the messages for a request: the generation prompt,
+// any follow-up turns and, when a truncated response is being continued,
+// the partial output followed by a request to carry on
+func Conversation(prompt string, req Request) llm.Request {
+	conv := llm.Request{System: req.System}.User(prompt)
+	conv.Messages = append(conv.Messages, req.Turns...)
+	if req.Partial != "" {
+		conv = conv.Assistant(req.Partial).User(continuationPrompt)
+	}
+	return conv
+}
+
+// ShortPrompt builds a compact prompt without the template for providers
+// with smaller context windows
+func ShortPrompt(req Request) string {
+	return fmt.Sprintf(`%s
+
+Create a detailed user persona based on the above information. Include:
+1. Name and demographics
+2. Background and goals
+3. Pain points and challenges
+4. Technical proficiency
+5. Behavioral patterns
+6. Success criteria
+
+Format as a well-structured Markdown document.`, req.IssueContent)
+}
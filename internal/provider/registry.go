@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/twin2ai/studio/internal/config"
+)
+
+// Factory builds a Provider from its configuration
+type Factory func(cfg config.ProviderConfig, logger *logrus.Logger) (Provider, error)
+
+var factories = map[string]Factory{
+	"claude": newClaudeProvider,
+	"gemini": newGeminiProvider,
+	"grok":   newOpenAIProvider,
+	"gpt":    newOpenAIProvider,
+	"openai": newOpenAIProvider,
+}
+
+// RegisterFactory makes a provider type available to the registry
+func RegisterFactory(providerType string, factory Factory) {
+	factories[providerType] = factory
+}
+
+// Registry holds the configured providers in query order
+type Registry struct {
+	providers []Provider
+	byName    map[string]Provider
+}
+
+// NewRegistry builds every configured provider using the registered factories
+func NewRegistry(cfgs []config.ProviderConfig, logger *logrus.Logger) (*Registry, error) {
+	r := &Registry{
+		byName: make(map[string]Provider),
+	}
+
+	for _, cfg := range cfgs {
+		if _, exists := r.byName[cfg.Name]; exists {
+			return nil, fmt.Errorf("duplicate provider name: %s", cfg.Name)
+		}
+
+		factory, ok := factories[cfg.Type]
+		if !ok {
+			return nil, fmt.Errorf("unknown provider type %q for provider %s", cfg.Type, cfg.Name)
+		}
+
+		p, err := factory(cfg, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create provider %s: %w", cfg.Name, err)
+		}
+
+		r.providers = append(r.providers, p)
+		r.byName[cfg.Name] = p
+		logger.Debugf("Registered provider %s (type: %s, model: %s)", cfg.Name, cfg.Type, cfg.Model)
+	}
+
+	if len(r.providers) == 0 {
+		return nil, fmt.Errorf("no providers configured")
+	}
+
+	return r, nil
+}
+
+// Providers returns all providers in query order
+func (r *Registry) Providers() []Provider {
+	return r.providers
+}
+
+// Names returns the provider names in query order
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.providers))
+	for _, p := range r.providers {
+		names = append(names, p.Name())
+	}
+	return names
+}
+
+// Get returns the provider registered under name
+func (r *Registry) Get(name string) (Provider, bool) {
+	p, ok := r.byName[name]
+	return p, ok
+}
@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/twin2ai/studio/internal/cassette"
+	"github.com/twin2ai/studio/internal/config"
+	"github.com/twin2ai/studio/internal/llm"
+	"github.com/twin2ai/studio/internal/usage"
+)
+
+// Setup prepares the process for AI provider calls: the breaker policy, the
+// breaker and rate limit state shared through the data directory, the usage
+// ledger and the record/replay transport. Every binary that builds providers
+// calls it first so their calls are paced, guarded and recorded the same way.
+// In a dry run only replay is used, serving recordings in place of the fake
+// providers.
+func Setup(cfg *config.Config, logger *logrus.Logger, dryRun bool) {
+	llm.SetBreakerPolicy(cfg.AI.Breaker)
+	llm.SetBreakerStateFile(cfg.AI.BreakerStateFile)
+	llm.SetRateLimitStateFile(cfg.AI.RateLimitStateFile)
+	setupUsageLedger(cfg, logger)
+	setupCassette(cfg, logger, dryRun)
+}
+
+// setupUsageLedger records every AI provider call in the usage ledger
+func setupUsageLedger(cfg *config.Config, logger *logrus.Logger) {
+	prices, err := usage.ParsePrices(cfg.Usage.Prices)
+	if err != nil {
+		logger.Warnf("Invalid MODEL_PRICES, using default prices: %v", err)
+		prices = usage.DefaultPrices()
+	}
+
+	ledger := usage.NewLedger(cfg.Usage.LedgerPath, prices, logger)
+	llm.AddObserver(ledger.Observe)
+}
+
+// setupCassette routes AI provider calls through the record/replay transport.
+// A dry run has nothing worth recording, so it only honours replay.
+func setupCassette(cfg *config.Config, logger *logrus.Logger, dryRun bool) {
+	mode, err := cassette.ParseMode(cfg.Cassette.Mode)
+	if err != nil {
+		logger.Fatalf("Invalid CASSETTE_MODE: %v", err)
+	}
+	if mode == cassette.ModeOff {
+		return
+	}
+	if dryRun && mode != cassette.ModeReplay {
+		logger.Infof("Cassette mode %s ignored in a dry run; only replay is used", mode)
+		return
+	}
+
+	logger.Infof("Cassette mode %s: AI provider calls use recordings in %s", mode, cfg.Cassette.Dir)
+	llm.SetTransport(cassette.NewTransport(mode, cfg.Cassette.Dir, http.DefaultTransport, logger))
+}
@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/twin2ai/studio/internal/config"
+	"github.com/twin2ai/studio/internal/llm"
+)
+
+// Completer is a provider that can also run a prepared conversation
+// unchanged, as synthesis and prompt generation need
+type Completer interface {
+	Provider
+	// Complete sends req as is with the settings for stage
+	Complete(ctx context.Context, req llm.Request, stage llm.Stage) (*llm.Result, error)
+}
+
+// Synthesis is the text produced by a synthesis chain and the member that produced it
+type Synthesis struct {
+	Text       string
+	Provider   string
+	Model      string
+	StopReason llm.StopReason
+}
+
+// SynthesisChain runs prompts on an ordered list of providers, falling back
+// to the next one when a provider fails, is blocked by a safety filter or
+// returns nothing
+type SynthesisChain struct {
+	members []Completer
+	logger  *logrus.Logger
+}
+
+// NewSynthesisChain creates the configured providers in fallback order
+func NewSynthesisChain(cfgs []config.ProviderConfig, logger *logrus.Logger) (*SynthesisChain, error) {
+	registry, err := NewRegistry(cfgs, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	chain := &SynthesisChain{logger: logger}
+	for _, p := range registry.Providers() {
+		member, ok := p.(Completer)
+		if !ok {
+			return nil, fmt.Errorf("provider %s cannot be used for synthesis", p.Name())
+		}
+		chain.members = append(chain.members, member)
+	}
+	if len(chain.members) == 0 {
+		return nil, fmt.Errorf("no synthesis providers configured (set SYNTHESIS_PROVIDERS)")
+	}
+
+	return chain, nil
+}
+
+// Names returns the chain members in fallback order
+func (c *SynthesisChain) Names() []string {
+	names := make([]string, 0, len(c.members))
+	for _, member := range c.members {
+		names = append(names, member.Name())
+	}
+	return names
+}
+
+// Complete runs req on each member in turn and returns the first usable result
+func (c *SynthesisChain) Complete(ctx context.Context, req llm.Request, stage llm.Stage) (*Synthesis, error) {
+	var failures []string
+	for i, member := range c.members {
+		result, err := member.Complete(ctx, req, stage)
+		if err == nil {
+			err = unusable(result)
+		}
+		if err == nil {
+			if i > 0 {
+				c.logger.Warnf("Used fallback synthesizer %s after: %s", member.Name(), strings.Join(failures, "; "))
+			}
+			model := result.Model
+			if model == "" {
+				model = member.Model()
+			}
+			return &Synthesis{Text: result.Text, Provider: member.Name(), Model: model, StopReason: result.StopReason}, nil
+		}
+
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		c.logger.Warnf("Synthesizer %s failed: %v", member.Name(), err)
+		failures = append(failures, fmt.Sprintf("%s: %v", member.Name(), err))
+	}
+
+	return nil, fmt.Errorf("all synthesizers failed (%s)", strings.Join(failures, "; "))
+}
+
+// unusable reports why a successful response cannot be used
+func unusable(result *llm.Result) error {
+	if result.StopReason == llm.StopSafety {
+		return &llm.SafetyError{Reason: "response stopped by safety filter"}
+	}
+	if strings.TrimSpace(result.Text) == "" {
+		return errors.New("empty response")
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+// Package review explains a feedback regeneration to reviewers: which
+// persona sections changed and whether each feedback comment's sections
+// were among them.
+package review
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/twin2ai/studio/pkg/models"
+)
+
+var (
+	sectionReference = regexp.MustCompile(`(?i)(?:\bsection|§)\s*(\d+(?:\.\d+)?)\b`)
+	titleWord        = regexp.MustCompile(`[a-z]+`)
+)
+
+// genericWords are title words too common in feedback to point at a section
+var genericWords = map[string]bool{
+	"analysis": true, "and": true, "over": true, "time": true, "with": true,
+	"more": true, "from": true, "this": true, "that": true, "section": true,
+}
+
+// FeedbackResult is how the sections one feedback comment points at changed
+type FeedbackResult struct {
+	Feedback string
+	// Targets are the sections the comment names, by number or title; empty
+	// when it names none and applies to the whole persona
+	Targets []models.SectionChange
+}
+
+// Addressed reports whether any section the comment points at changed.
+// Feedback without targets counts as addressed when anything changed.
+func (f FeedbackResult) Addressed(changes []models.SectionChange) bool {
+	targets := f.Targets
+	if len(targets) == 0 {
+		targets = changes
+	}
+	for _, t := range targets {
+		if t.Changed() {
+			return true
+		}
+	}
+	return false
+}
+
+// Unchanged returns the target sections that stayed the same
+func (f FeedbackResult) Unchanged() []models.SectionChange {
+	var unchanged []models.SectionChange
+	for _, t := range f.Targets {
+		if !t.Changed() {
+			unchanged = append(unchanged, t)
+		}
+	}
+	return unchanged
+}
+
+// Report is a section-aware diff of a regenerated persona, with each
+// feedback comment mapped to the sections it targets
+type Report struct {
+	Changes  []models.SectionChange
+	Feedback []FeedbackResult
+}
+
+// Compare diffs the persona before and after a feedback regeneration and
+// maps each feedback comment to the sections it names
+func Compare(before, after string, feedback []string) (*Report, error) {
+	previous, err := models.Parse(before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse previous persona: %w", err)
+	}
+	regenerated, err := models.Parse(after)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse regenerated persona: %w", err)
+	}
+
+	report := &Report{Changes: models.Diff(previous, regenerated)}
+	keywords := titleKeywords(report.Changes)
+	for _, comment := range feedback {
+		result := FeedbackResult{Feedback: comment}
+		for i, change := range report.Changes {
+			if targets(comment, change, keywords[i]) {
+				result.Targets = append(result.Targets, change)
+			}
+		}
+		report.Feedback = append(report.Feedback, result)
+	}
+	return report, nil
+}
+
+// Unaddressed returns the feedback whose target sections did not change
+func (r *Report) Unaddressed() []FeedbackResult {
+	var unaddressed []FeedbackResult
+	for _, f := range r.Feedback {
+		if len(f.Unchanged()) > 0 || !f.Addressed(r.Changes) {
+			unaddressed = append(unaddressed, f)
+		}
+	}
+	return unaddressed
+}
+
+// targets reports whether a feedback comment names a section, by number
+// ("section 2", "§10.5"), by its full title, or by a word of its title that
+// no other section title uses
+func targets(comment string, change models.SectionChange, keywords []string) bool {
+	if change.Number != "" {
+		for _, m := range sectionReference.FindAllStringSubmatch(comment, -1) {
+			if m[1] == change.Number {
+				return true
+			}
+		}
+	}
+
+	lower := strings.ToLower(comment)
+	if strings.Contains(lower, strings.ToLower(change.Title)) {
+		return true
+	}
+	words := make(map[string]bool)
+	for _, w := range titleWord.FindAllString(lower, -1) {
+		words[w] = true
+	}
+	for _, keyword := range keywords {
+		if words[keyword] {
+			return true
+		}
+	}
+	return false
+}
+
+// titleKeywords returns, for each section, the words of its title that are
+// specific enough to identify it: at least four letters, not generic, and
+// used by no other section title
+func titleKeywords(changes []models.SectionChange) [][]string {
+	uses := make(map[string]int)
+	perSection := make([][]string, len(changes))
+	for i, change := range changes {
+		seen := make(map[string]bool)
+		for _, w := range titleWord.FindAllString(strings.ToLower(change.Title), -1) {
+			if len(w) < 4 || genericWords[w] || seen[w] {
+				continue
+			}
+			seen[w] = true
+			uses[w]++
+			perSection[i] = append(perSection[i], w)
+		}
+	}
+
+	keywords := make([][]string, len(changes))
+	for i, words := range perSection {
+		for _, w := range words {
+			if uses[w] == 1 {
+				keywords[i] = append(keywords[i], w)
+			}
+		}
+	}
+	return keywords
+}
+
+// Markdown renders the report for the PR update comment
+func (r *Report) Markdown() string {
+	var b strings.Builder
+	var unchanged []string
+	changed := 0
+	b.WriteString("| Section | Change | Lines |\n|---------|--------|-------|\n")
+	for _, c := range r.Changes {
+		if !c.Changed() {
+			unchanged = append(unchanged, c.Heading())
+			continue
+		}
+		changed++
+		fmt.Fprintf(&b, "| %s | %s | %s |\n", c.Heading(), describe(c), lines(c))
+	}
+	if changed == 0 {
+		b.WriteString("| *No section changed* | | |\n")
+	}
+	if len(unchanged) > 0 {
+		fmt.Fprintf(&b, "\nUnchanged: %s\n", strings.Join(unchanged, ", "))
+	}
+
+	if len(r.Feedback) > 0 {
+		b.WriteString("\n### Feedback\n\n")
+		for i, f := range r.Feedback {
+			fmt.Fprintf(&b, "%d. > %s\n", i+1, excerpt(f.Feedback))
+			if len(f.Targets) == 0 {
+				fmt.Fprintf(&b, "   - No section named; %d of %d sections changed\n", changed, len(r.Changes))
+				continue
+			}
+			for _, t := range f.Targets {
+				fmt.Fprintf(&b, "   - %s: %s\n", t.Heading(), strings.TrimSpace(describe(t)+" "+lines(t)))
+			}
+		}
+	}
+
+	if unaddressed := r.Unaddressed(); len(unaddressed) > 0 {
+		b.WriteString("\n### ⚠️ Possibly Not Addressed\n\n")
+		for _, f := range unaddressed {
+			var headings []string
+			for _, t := range f.Unchanged() {
+				headings = append(headings, t.Heading())
+			}
+			if len(headings) == 0 {
+				fmt.Fprintf(&b, "- \"%s\": nothing in the persona changed\n", excerpt(f.Feedback))
+			} else {
+				fmt.Fprintf(&b, "- \"%s\": %s unchanged\n", excerpt(f.Feedback), strings.Join(headings, ", "))
+			}
+		}
+	}
+	return b.String()
+}
+
+// describe labels a change for the table
+func describe(c models.SectionChange) string {
+	switch c.Kind {
+	case models.ChangeAdded:
+		return "➕ added"
+	case models.ChangeRemoved:
+		return "➖ removed"
+	case models.ChangeModified:
+		return "✏️ modified"
+	default:
+		return "unchanged"
+	}
+}
+
+// lines formats the line counts of a change, e.g. "+12 −4"
+func lines(c models.SectionChange) string {
+	if !c.Changed() {
+		return ""
+	}
+	return fmt.Sprintf("+%d −%d", c.Added, c.Removed)
+}
+
+// excerpt returns the first line of a comment, shortened for quoting
+func excerpt(comment string) string {
+	line := strings.TrimSpace(comment)
+	if i := strings.IndexByte(line, '\n'); i >= 0 {
+		line = strings.TrimSpace(line[:i]) + " …"
+	}
+	if runes := []rune(line); len(runes) > 100 {
+		line = string(runes[:97]) + "..."
+	}
+	return line
+}
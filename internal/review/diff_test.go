@@ -0,0 +1,122 @@
+package review
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/twin2ai/studio/pkg/models"
+)
+
+const before = `# Ada Lovelace
+
+## 1. Core Essence
+
+Mathematician and writer.
+
+## 2. Communication Style
+
+Formal and precise.
+
+## 3. Relationships
+
+Friend of Charles Babbage.
+`
+
+const after = `# Ada Lovelace
+
+## 1. Core Essence
+
+Mathematician and writer.
+
+## 2. Communication Style
+
+Formal and precise, with flashes of poetry.
+
+## 3. Relationships
+
+Friend of Charles Babbage.
+
+## 4. Legacy
+
+First published algorithm.
+`
+
+func TestCompareChanges(t *testing.T) {
+	report, err := Compare(before, after, nil)
+	if err != nil {
+		t.Fatalf("Compare() error = %v", err)
+	}
+
+	var got []string
+	for _, c := range report.Changes {
+		got = append(got, c.Heading()+": "+c.Kind)
+	}
+	want := []string{
+		"1. Core Essence: " + models.ChangeUnchanged,
+		"2. Communication Style: " + models.ChangeModified,
+		"3. Relationships: " + models.ChangeUnchanged,
+		"4. Legacy: " + models.ChangeAdded,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Compare() changes = %q, want %q", got, want)
+	}
+}
+
+func TestCompareFeedback(t *testing.T) {
+	tests := []struct {
+		feedback  string
+		targets   []string
+		addressed bool
+	}{
+		{"Section 2 reads too stiff", []string{"2. Communication Style"}, true},
+		{"Expand §3 please", []string{"3. Relationships"}, false},
+		{"The communication style needs work", []string{"2. Communication Style"}, true},
+		{"Say more about her relationships", []string{"3. Relationships"}, false},
+		{"Mention her legacy", []string{"4. Legacy"}, true},
+		{"Core essence and relationships are thin", []string{"1. Core Essence", "3. Relationships"}, false},
+		{"Make it more vivid overall", nil, true},
+	}
+
+	var feedback []string
+	for _, tt := range tests {
+		feedback = append(feedback, tt.feedback)
+	}
+	report, err := Compare(before, after, feedback)
+	if err != nil {
+		t.Fatalf("Compare() error = %v", err)
+	}
+
+	unaddressed := make(map[string]bool)
+	for _, f := range report.Unaddressed() {
+		unaddressed[f.Feedback] = true
+	}
+	for i, tt := range tests {
+		t.Run(tt.feedback, func(t *testing.T) {
+			result := report.Feedback[i]
+			var targets []string
+			for _, target := range result.Targets {
+				targets = append(targets, target.Heading())
+			}
+			if !reflect.DeepEqual(targets, tt.targets) {
+				t.Errorf("targets = %q, want %q", targets, tt.targets)
+			}
+			if unaddressed[tt.feedback] == tt.addressed {
+				t.Errorf("addressed = %v, want %v", !unaddressed[tt.feedback], tt.addressed)
+			}
+		})
+	}
+}
+
+func TestCompareNothingChanged(t *testing.T) {
+	report, err := Compare(before, before, []string{"Make it more vivid overall"})
+	if err != nil {
+		t.Fatalf("Compare() error = %v", err)
+	}
+	if len(report.Unaddressed()) != 1 {
+		t.Errorf("Unaddressed() = %d comments, want 1 when nothing changed", len(report.Unaddressed()))
+	}
+	if md := report.Markdown(); !strings.Contains(md, "nothing in the persona changed") {
+		t.Errorf("Markdown() does not say nothing changed:\n%s", md)
+	}
+}
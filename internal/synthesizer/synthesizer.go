@@ -8,21 +8,28 @@ import (
 	"strings"
 
 	"github.com/sirupsen/logrus"
+	"github.com/twin2ai/studio/internal/completeness"
 	"github.com/twin2ai/studio/internal/config"
-	"github.com/twin2ai/studio/internal/gemini"
+	"github.com/twin2ai/studio/internal/facts"
 	"github.com/twin2ai/studio/internal/github"
+	"github.com/twin2ai/studio/internal/provider"
+	"github.com/twin2ai/studio/internal/usage"
+	"github.com/twin2ai/studio/internal/validation"
+	"github.com/twin2ai/studio/pkg/models"
 )
 
 // Synthesizer handles regenerating synthesized.md from raw AI outputs
 type Synthesizer struct {
 	config       *config.Config
 	githubClient *github.Client
-	geminiClient *gemini.Client
+	synthesis    *provider.SynthesisChain
+	linter       *validation.Linter
+	completeness completeness.Policy
 	logger       *logrus.Logger
 }
 
 // New creates a new synthesizer instance
-func New(cfg *config.Config, logger *logrus.Logger) *Synthesizer {
+func New(cfg *config.Config, logger *logrus.Logger) (*Synthesizer, error) {
 	// Create GitHub client
 	githubClient := github.NewClient(
 		cfg.GitHub.Token,
@@ -34,15 +41,30 @@ func New(cfg *config.Config, logger *logrus.Logger) *Synthesizer {
 		logger,
 	)
 
-	// Create Gemini client for synthesis
-	geminiClient := gemini.NewClient(cfg.AI.Gemini.APIKey, cfg.AI.Gemini.Model, logger)
+	// Create the synthesis providers in fallback order
+	synthesis, err := provider.NewSynthesisChain(cfg.AI.Synthesizers, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create synthesis chain: %w", err)
+	}
 
 	return &Synthesizer{
 		config:       cfg,
 		githubClient: githubClient,
-		geminiClient: geminiClient,
+		synthesis:    synthesis,
+		completeness: completeness.DefaultPolicy,
 		logger:       logger,
-	}
+	}, nil
+}
+
+// SetLinter checks syntheses for the template's required sections and
+// enables the section-by-section fallback; nil turns both off
+func (s *Synthesizer) SetLinter(linter *validation.Linter) {
+	s.linter = linter
+}
+
+// SetCompleteness sets how incomplete syntheses are detected and repaired
+func (s *Synthesizer) SetCompleteness(policy completeness.Policy) {
+	s.completeness = policy
 }
 
 // SynthesizeAll regenerates synthesized.md for all personas
@@ -74,6 +96,8 @@ func (s *Synthesizer) SynthesizeAll(ctx context.Context) error {
 
 // SynthesizeOne regenerates synthesized.md for a specific persona
 func (s *Synthesizer) SynthesizeOne(ctx context.Context, personaName string) error {
+	ctx = usage.WithLabels(ctx, usage.Labels{Persona: personaName, Stage: usage.StageSynthesis})
+
 	// Normalize persona name to folder name
 	folderName := s.personaToFolderName(personaName)
 
@@ -96,20 +120,44 @@ func (s *Synthesizer) SynthesizeOne(ctx context.Context, personaName string) err
 		return fmt.Errorf("failed to load combination prompt: %w", err)
 	}
 
+	// Add the persona's fact sheet, when it has one, so the synthesis
+	// favours the facts most providers agree on
+	sheet := s.fetchFactSheet(ctx, folderName)
+	combinationPrompt = facts.Apply(combinationPrompt, sheet)
+
 	// Prepare the prompt with all raw outputs
 	fullPrompt := s.prepareCombinationPrompt(combinationPrompt, rawOutputs, personaName)
 
-	// Generate new synthesis using Gemini with lower temperature for consistency
-	s.logger.Info("Generating new synthesis with Gemini...")
-	synthesized, err := s.geminiClient.GenerateSynthesis(ctx, fullPrompt)
+	// Record which raw files contributed to the synthesis
+	var sources []string
+	var raw []completeness.Source
+	for _, name := range s.providerNames() {
+		if content, exists := rawOutputs[name]; exists {
+			sources = append(sources, name)
+			raw = append(raw, completeness.Source{Name: name, Content: content})
+		}
+	}
+	if _, exists := rawOutputs["User"]; exists {
+		sources = append(sources, "user_supplied")
+	}
+
+	// Generate new synthesis, falling back along the synthesis chain, and
+	// repair an incomplete result before the PR is opened
+	s.logger.Infof("Generating new synthesis with %s...", strings.Join(s.synthesis.Names(), " → "))
+	synthesizer := completeness.NewSynthesizer(s.synthesis, s.linter, s.completeness, s.logger)
+	result, err := synthesizer.Synthesize(ctx, fullPrompt, raw, completeness.Context{UserPersona: rawOutputs["User"], Facts: sheet})
 	if err != nil {
 		return fmt.Errorf("failed to generate synthesis: %w", err)
 	}
+	synthesized := &result.Synthesis
+
+	s.logger.Infof("Generated synthesis with %d characters using %s", len(synthesized.Text), synthesized.Provider)
 
-	s.logger.Infof("Generated synthesis with %d characters", len(synthesized))
+	// Lint the synthesis for the PR body
+	report := s.validate(personaName, synthesized.Text)
 
 	// Create a pull request with the updated synthesis
-	if err := s.createUpdatePR(ctx, personaName, folderName, synthesized); err != nil {
+	if err := s.createUpdatePR(ctx, personaName, folderName, synthesized, sources, report); err != nil {
 		return fmt.Errorf("failed to create update PR: %w", err)
 	}
 
@@ -120,17 +168,16 @@ func (s *Synthesizer) SynthesizeOne(ctx context.Context, personaName string) err
 func (s *Synthesizer) fetchRawOutputs(ctx context.Context, folderName string) (map[string]string, error) {
 	outputs := make(map[string]string)
 
-	// List of raw files to fetch
-	rawFiles := []struct {
+	// List of raw files to fetch: one per configured provider plus the optional user file
+	type rawFile struct {
 		name string
 		path string
-	}{
-		{"Claude", fmt.Sprintf("personas/%s/raw/claude.md", folderName)},
-		{"Gemini", fmt.Sprintf("personas/%s/raw/gemini.md", folderName)},
-		{"Grok", fmt.Sprintf("personas/%s/raw/grok.md", folderName)},
-		{"GPT-4", fmt.Sprintf("personas/%s/raw/gpt.md", folderName)},
-		{"User", fmt.Sprintf("personas/%s/raw/user_supplied.md", folderName)},
 	}
+	var rawFiles []rawFile
+	for _, name := range s.providerNames() {
+		rawFiles = append(rawFiles, rawFile{name, fmt.Sprintf("personas/%s/raw/%s.md", folderName, name)})
+	}
+	rawFiles = append(rawFiles, rawFile{"User", fmt.Sprintf("personas/%s/raw/user_supplied.md", folderName)})
 
 	for _, file := range rawFiles {
 		content, err := s.githubClient.GetFileContent(ctx, file.path)
@@ -150,6 +197,22 @@ func (s *Synthesizer) fetchRawOutputs(ctx context.Context, folderName string) (m
 	return outputs, nil
 }
 
+// fetchFactSheet fetches a persona's facts.json from GitHub, or returns nil
+// when it has none
+func (s *Synthesizer) fetchFactSheet(ctx context.Context, folderName string) *facts.Sheet {
+	content, err := s.githubClient.GetFileContent(ctx, fmt.Sprintf("personas/%s/%s", folderName, models.FactsFile))
+	if err != nil {
+		s.logger.Debugf("No fact sheet found for %s", folderName)
+		return nil
+	}
+	sheet, err := facts.Parse([]byte(content))
+	if err != nil {
+		s.logger.Warnf("Ignoring fact sheet for %s: %v", folderName, err)
+		return nil
+	}
+	return sheet
+}
+
 // loadCombinationPrompt loads the persona combination prompt template
 func (s *Synthesizer) loadCombinationPrompt() (string, error) {
 	// Try to load from the prompts directory
@@ -178,7 +241,7 @@ func (s *Synthesizer) formatPersonasForPrompt(rawOutputs map[string]string) stri
 	var output strings.Builder
 
 	// Order matters for consistency
-	providers := []string{"Claude", "Gemini", "Grok", "GPT-4"}
+	providers := s.providerNames()
 
 	for i, provider := range providers {
 		if content, exists := rawOutputs[provider]; exists {
@@ -203,9 +266,33 @@ func (s *Synthesizer) formatPersonasForPrompt(rawOutputs map[string]string) stri
 	return output.String()
 }
 
+// providerNames returns the configured provider names in synthesis order
+func (s *Synthesizer) providerNames() []string {
+	names := make([]string, 0, len(s.config.AI.Providers))
+	for _, p := range s.config.AI.Providers {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// validate lints a synthesized persona. It returns nil when no linter is set.
+func (s *Synthesizer) validate(personaName, persona string) *validation.Report {
+	if s.linter == nil {
+		return nil
+	}
+
+	report := s.linter.Lint(personaName, persona)
+	if report.Passed {
+		s.logger.Infof("Validated %s: %s", personaName, report.Summary())
+	} else {
+		s.logger.Warnf("Validation of %s: %s", personaName, report.Summary())
+	}
+	return report
+}
+
 // createUpdatePR creates a pull request with the updated synthesized.md
-func (s *Synthesizer) createUpdatePR(ctx context.Context, personaName, folderName string, synthesized string) error {
-	pr, err := s.githubClient.CreateSynthesisUpdatePR(ctx, personaName, folderName, synthesized)
+func (s *Synthesizer) createUpdatePR(ctx context.Context, personaName, folderName string, synthesized *provider.Synthesis, sources []string, report *validation.Report) error {
+	pr, err := s.githubClient.CreateSynthesisUpdatePR(ctx, personaName, folderName, synthesized.Text, synthesized.Provider, synthesized.Model, sources, report)
 	if err != nil {
 		return fmt.Errorf("failed to create synthesis update PR: %w", err)
 	}
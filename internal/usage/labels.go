@@ -0,0 +1,46 @@
+package usage
+
+import (
+	"context"
+
+	"github.com/twin2ai/studio/internal/llm"
+)
+
+// Stage identifies the pipeline step a provider call belongs to
+type Stage = llm.Stage
+
+const (
+	StageGeneration = llm.StageGeneration
+	StageSynthesis  = llm.StageSynthesis
+	StageFeedback   = llm.StageFeedback
+	StagePrompt     = llm.StagePrompt
+	StageEvaluation = llm.StageEvaluation
+	StageAnalysis   = llm.StageAnalysis
+)
+
+// Labels attribute provider calls to an issue, persona and stage
+type Labels struct {
+	Issue   int
+	Persona string
+	Stage   Stage
+}
+
+type labelsKey struct{}
+
+// WithLabels attaches labels to a context; calls made with it are recorded under them
+func WithLabels(ctx context.Context, labels Labels) context.Context {
+	return context.WithValue(ctx, labelsKey{}, labels)
+}
+
+// WithStage keeps the existing labels but switches the stage
+func WithStage(ctx context.Context, stage Stage) context.Context {
+	labels := LabelsFrom(ctx)
+	labels.Stage = stage
+	return WithLabels(ctx, labels)
+}
+
+// LabelsFrom returns the labels attached to a context, if any
+func LabelsFrom(ctx context.Context) Labels {
+	labels, _ := ctx.Value(labelsKey{}).(Labels)
+	return labels
+}
@@ -0,0 +1,129 @@
+package usage
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/twin2ai/studio/internal/llm"
+)
+
+// Record is one provider call in the ledger
+type Record struct {
+	Time         time.Time `json:"time"`
+	Provider     string    `json:"provider"`
+	Model        string    `json:"model"`
+	Stage        Stage     `json:"stage,omitempty"`
+	Issue        int       `json:"issue,omitempty"`
+	Persona      string    `json:"persona,omitempty"`
+	InputTokens  int       `json:"input_tokens"`
+	OutputTokens int       `json:"output_tokens"`
+	LatencyMS    int64     `json:"latency_ms"`
+	Cost         float64   `json:"cost_usd"`
+	Error        string    `json:"error,omitempty"`
+}
+
+// Ledger appends usage records to a JSON Lines file
+type Ledger struct {
+	path   string
+	prices PriceTable
+	logger *logrus.Logger
+	mu     sync.Mutex
+}
+
+// NewLedger creates a ledger writing to path
+func NewLedger(path string, prices PriceTable, logger *logrus.Logger) *Ledger {
+	return &Ledger{
+		path:   path,
+		prices: prices,
+		logger: logger,
+	}
+}
+
+// Observe records a provider call; register it with llm.AddObserver
+func (l *Ledger) Observe(ctx context.Context, call llm.Call) {
+	labels := LabelsFrom(ctx)
+	record := Record{
+		Time:         time.Now().UTC(),
+		Provider:     call.Provider,
+		Model:        call.Model,
+		Stage:        labels.Stage,
+		Issue:        labels.Issue,
+		Persona:      labels.Persona,
+		InputTokens:  call.Usage.InputTokens,
+		OutputTokens: call.Usage.OutputTokens,
+		LatencyMS:    call.Latency.Milliseconds(),
+		Cost:         l.prices.Cost(call.Model, call.Usage.InputTokens, call.Usage.OutputTokens),
+	}
+	if call.Err != nil {
+		record.Error = call.Err.Error()
+	}
+
+	if err := l.Append(record); err != nil {
+		l.logger.Warnf("Failed to record usage: %v", err)
+	}
+}
+
+// Append writes a record to the ledger file
+func (l *Ledger) Append(record Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal usage record: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return fmt.Errorf("failed to create ledger directory: %w", err)
+	}
+
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open usage ledger: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write usage record: %w", err)
+	}
+	return nil
+}
+
+// Load reads all records from a ledger file
+func Load(path string) ([]Record, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open usage ledger: %w", err)
+	}
+	defer file.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			// Skip a partially written line rather than failing the whole report
+			continue
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read usage ledger: %w", err)
+	}
+	return records, nil
+}
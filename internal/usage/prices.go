@@ -0,0 +1,94 @@
+package usage
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Price is the cost in USD per million tokens
+type Price struct {
+	Input  float64
+	Output float64
+}
+
+// PriceTable maps model names (or model name prefixes) to prices
+type PriceTable map[string]Price
+
+// DefaultPrices are list prices at the time of writing; override or extend
+// them with MODEL_PRICES
+func DefaultPrices() PriceTable {
+	return PriceTable{
+		"claude-opus-4":    {Input: 15, Output: 75},
+		"claude-sonnet-4":  {Input: 3, Output: 15},
+		"gemini-2.5-pro":   {Input: 1.25, Output: 10},
+		"gemini-2.5-flash": {Input: 0.30, Output: 2.50},
+		"gemini-2.0-flash": {Input: 0.10, Output: 0.40},
+		"grok-2":           {Input: 2, Output: 10},
+		"gpt-4":            {Input: 30, Output: 60},
+		"gpt-4-turbo":      {Input: 10, Output: 30},
+		"gpt-4o":           {Input: 2.50, Output: 10},
+	}
+}
+
+// ParsePrices reads a comma-separated list of model=input/output entries
+// (USD per million tokens) and merges it over the default table, e.g.
+// "claude-opus-4=15/75,llama3.1=0/0"
+func ParsePrices(value string) (PriceTable, error) {
+	prices := DefaultPrices()
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		model, rates, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid price entry %q (want model=input/output)", entry)
+		}
+		input, output, ok := strings.Cut(rates, "/")
+		if !ok {
+			return nil, fmt.Errorf("invalid price entry %q (want model=input/output)", entry)
+		}
+
+		in, err := strconv.ParseFloat(strings.TrimSpace(input), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid input price in %q: %w", entry, err)
+		}
+		out, err := strconv.ParseFloat(strings.TrimSpace(output), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid output price in %q: %w", entry, err)
+		}
+
+		prices[strings.TrimSpace(model)] = Price{Input: in, Output: out}
+	}
+	return prices, nil
+}
+
+// Lookup finds the price for a model: an exact match first, then the longest
+// table entry the model name starts with (so dated model versions match)
+func (t PriceTable) Lookup(model string) (Price, bool) {
+	if price, ok := t[model]; ok {
+		return price, true
+	}
+
+	best := ""
+	for name := range t {
+		if strings.HasPrefix(model, name) && len(name) > len(best) {
+			best = name
+		}
+	}
+	if best == "" {
+		return Price{}, false
+	}
+	return t[best], true
+}
+
+// Cost estimates the USD cost of a call; unknown models cost zero
+func (t PriceTable) Cost(model string, inputTokens, outputTokens int) float64 {
+	price, ok := t.Lookup(model)
+	if !ok {
+		return 0
+	}
+	return (float64(inputTokens)*price.Input + float64(outputTokens)*price.Output) / 1_000_000
+}
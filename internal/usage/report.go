@@ -0,0 +1,107 @@
+package usage
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Totals aggregates a group of records
+type Totals struct {
+	Key          string
+	Calls        int
+	Errors       int
+	InputTokens  int
+	OutputTokens int
+	Cost         float64
+}
+
+// GroupBy aggregates records by the key returned for each record, sorted by key
+func GroupBy(records []Record, key func(Record) string) []Totals {
+	groups := make(map[string]*Totals)
+	for _, record := range records {
+		k := key(record)
+		totals, ok := groups[k]
+		if !ok {
+			totals = &Totals{Key: k}
+			groups[k] = totals
+		}
+		totals.Calls++
+		if record.Error != "" {
+			totals.Errors++
+		}
+		totals.InputTokens += record.InputTokens
+		totals.OutputTokens += record.OutputTokens
+		totals.Cost += record.Cost
+	}
+
+	result := make([]Totals, 0, len(groups))
+	for _, totals := range groups {
+		result = append(result, *totals)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result
+}
+
+// ByPersona groups by persona name; calls without one are grouped as "(none)"
+func ByPersona(r Record) string {
+	if r.Persona == "" {
+		return "(none)"
+	}
+	return r.Persona
+}
+
+// ByProvider groups by provider name and model
+func ByProvider(r Record) string {
+	return fmt.Sprintf("%s (%s)", r.Provider, r.Model)
+}
+
+// ByDay groups by UTC day
+func ByDay(r Record) string {
+	return r.Time.UTC().Format("2006-01-02")
+}
+
+// ByStage groups by pipeline stage
+func ByStage(r Record) string {
+	if r.Stage == "" {
+		return "(none)"
+	}
+	return string(r.Stage)
+}
+
+// Since filters records to those at or after t
+func Since(records []Record, t time.Time) []Record {
+	var filtered []Record
+	for _, record := range records {
+		if !record.Time.Before(t) {
+			filtered = append(filtered, record)
+		}
+	}
+	return filtered
+}
+
+// WriteReport prints one table per grouping
+func WriteReport(w io.Writer, records []Record, groupings map[string]func(Record) string, order []string) {
+	for _, title := range order {
+		totals := GroupBy(records, groupings[title])
+
+		fmt.Fprintf(w, "\n%s\n%s\n", title, strings.Repeat("-", len(title)))
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "\tCalls\tErrors\tInput tokens\tOutput tokens\tCost (USD)")
+
+		var sum Totals
+		for _, t := range totals {
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%.4f\n", t.Key, t.Calls, t.Errors, t.InputTokens, t.OutputTokens, t.Cost)
+			sum.Calls += t.Calls
+			sum.Errors += t.Errors
+			sum.InputTokens += t.InputTokens
+			sum.OutputTokens += t.OutputTokens
+			sum.Cost += t.Cost
+		}
+		fmt.Fprintf(tw, "Total\t%d\t%d\t%d\t%d\t%.4f\n", sum.Calls, sum.Errors, sum.InputTokens, sum.OutputTokens, sum.Cost)
+		tw.Flush()
+	}
+}
@@ -0,0 +1,349 @@
+// Package validation lints persona documents against the section structure
+// of templates/persona_template.md and scores how complete they are.
+package validation
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/twin2ai/studio/pkg/models"
+)
+
+// DefaultTemplate is the persona template personas are checked against
+const DefaultTemplate = "templates/persona_template.md"
+
+// DefaultMinWords is the word count below which a section is reported as short
+const DefaultMinWords = 100
+
+// Rules reported by the linter
+const (
+	RuleMissingSection = "missing-section"
+	RuleShortSection   = "short-section"
+	RulePlaceholder    = "placeholder"
+	RuleMarkdown       = "unbalanced-markdown"
+	RulePreamble       = "preamble"
+)
+
+var (
+	templatePlaceholder = regexp.MustCompile(`\[([^\[\]\n]{3,80})\]`)
+	placeholderPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`\{\{[^{}\n]*\}\}`),
+		regexp.MustCompile(`(?i)\[(?:insert|add|capture|describe|provide|placeholder|your|tbd|todo)\b[^\[\]\n]*\]`),
+		regexp.MustCompile(`\b(?:TODO|TBD|FIXME)\b`),
+		regexp.MustCompile(`(?i)lorem ipsum`),
+	}
+	preamblePattern  = regexp.MustCompile(`(?i)^(?:here is|here's|here are|sure\b|certainly\b|of course\b|absolutely\b|okay\b|great\b|below is|as requested|i've (?:created|compiled|written|prepared)|i have (?:created|compiled|written|prepared)|based on (?:the|your) (?:research|request|information))`)
+	postamblePattern = regexp.MustCompile(`(?i)^(?:let me know|please let me know|i hope (?:this|that)|feel free to|would you like|if you(?:'d| would) like|is there anything)`)
+	titleNoise       = regexp.MustCompile(`\([^)]*\)|[^a-z0-9 ]+`)
+)
+
+// Options tunes the linter
+type Options struct {
+	// MinWords is the word count below which a section is short; zero uses DefaultMinWords
+	MinWords int
+}
+
+// Requirement is a section every persona must have
+type Requirement struct {
+	Number string `json:"number"`
+	Title  string `json:"title"`
+}
+
+// Heading returns the section heading as written in the template
+func (r Requirement) Heading() string {
+	return models.Section{Number: r.Number, Title: r.Title}.Heading()
+}
+
+// Linter checks persona documents against a template
+type Linter struct {
+	required     []Requirement
+	placeholders []string
+	minWords     int
+}
+
+// NewLinter builds a linter from a persona template. The required sections
+// are the numbered sections of the template's profile structure part.
+func NewLinter(template string, opts Options) (*Linter, error) {
+	doc, err := models.Parse(template)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	var required []Requirement
+	for _, part := range doc.Sections {
+		if strings.Contains(strings.ToLower(part.Title), "profile structure") {
+			required = numbered(part.Subsections)
+		}
+	}
+	if len(required) == 0 {
+		required = allNumbered(doc.Sections)
+	}
+	if len(required) == 0 {
+		return nil, fmt.Errorf("template has no numbered sections")
+	}
+
+	l := &Linter{required: required, minWords: opts.MinWords}
+	if l.minWords <= 0 {
+		l.minWords = DefaultMinWords
+	}
+
+	// Bracketed instructions in the template, such as [specific Hz values],
+	// are placeholders when they show up in a persona
+	seen := make(map[string]bool)
+	for _, m := range templatePlaceholder.FindAllStringSubmatchIndex(template, -1) {
+		if m[1] < len(template) && template[m[1]] == '(' {
+			continue // A markdown link
+		}
+		placeholder := template[m[0]:m[1]]
+		if key := strings.ToLower(placeholder); !seen[key] {
+			seen[key] = true
+			l.placeholders = append(l.placeholders, placeholder)
+		}
+	}
+	return l, nil
+}
+
+// LoadLinter builds a linter from the template file at path
+func LoadLinter(path string, opts Options) (*Linter, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+	return NewLinter(string(data), opts)
+}
+
+// Required returns the sections every persona must have
+func (l *Linter) Required() []Requirement {
+	return l.required
+}
+
+// Lint checks a persona document. name labels the report; when empty the
+// name is taken from the document's title.
+func (l *Linter) Lint(name, markdown string) *Report {
+	report := &Report{Persona: name}
+
+	doc, err := models.Parse(markdown)
+	if err != nil {
+		report.add(Issue{Rule: RuleMissingSection, Severity: SeverityError, Message: err.Error()})
+		for _, req := range l.required {
+			report.Sections = append(report.Sections, SectionResult{Number: req.Number, Title: req.Title, Status: StatusMissing})
+		}
+		report.finish()
+		return report
+	}
+	if report.Persona == "" {
+		report.Persona = doc.Name
+	}
+
+	l.checkSections(report, doc)
+	lines := strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")
+	l.checkLines(report, lines)
+	checkPreamble(report, lines)
+
+	report.finish()
+	return report
+}
+
+// checkSections reports missing and short sections and computes the score
+func (l *Linter) checkSections(report *Report, doc *models.Persona) {
+	var complete float64
+	for _, req := range l.required {
+		result := SectionResult{Number: req.Number, Title: req.Title}
+		section := findRequired(doc.Sections, req)
+		switch {
+		case section == nil:
+			result.Status = StatusMissing
+			report.add(Issue{Rule: RuleMissingSection, Severity: SeverityError, Section: req.Heading(),
+				Message: fmt.Sprintf("section %q is missing", req.Heading())})
+		default:
+			result.Heading = section.Heading()
+			result.Words = words(*section)
+			if result.Words < l.minWords {
+				result.Status = StatusShort
+				complete += 0.5
+				report.add(Issue{Rule: RuleShortSection, Severity: SeverityWarning, Section: req.Heading(),
+					Message: fmt.Sprintf("section has %d words, expected at least %d", result.Words, l.minWords)})
+			} else {
+				result.Status = StatusOK
+				complete++
+			}
+		}
+		report.Sections = append(report.Sections, result)
+	}
+	report.Score = int(math.Round(100 * complete / float64(len(l.required))))
+}
+
+// checkLines reports leftover placeholders and unbalanced markdown
+func (l *Linter) checkLines(report *Report, lines []string) {
+	section := ""
+	fenceLine := 0
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			if fenceLine == 0 {
+				fenceLine = i + 1
+			} else {
+				fenceLine = 0
+			}
+			continue
+		}
+		if fenceLine != 0 {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "#") {
+			section = strings.Trim(trimmed, "# *")
+		}
+
+		for _, placeholder := range l.findPlaceholders(line) {
+			report.add(Issue{Rule: RulePlaceholder, Severity: SeverityError, Section: section, Line: i + 1,
+				Message: fmt.Sprintf("leftover placeholder %q", placeholder)})
+		}
+		if strings.Count(strings.ReplaceAll(line, "***", ""), "**")%2 != 0 {
+			report.add(Issue{Rule: RuleMarkdown, Severity: SeverityWarning, Section: section, Line: i + 1,
+				Message: "unbalanced ** emphasis"})
+		}
+	}
+	if fenceLine != 0 {
+		report.add(Issue{Rule: RuleMarkdown, Severity: SeverityWarning, Line: fenceLine,
+			Message: "code fence is never closed"})
+	}
+}
+
+// findPlaceholders returns the placeholders left in a line
+func (l *Linter) findPlaceholders(line string) []string {
+	var found []string
+	lower := strings.ToLower(line)
+	for _, placeholder := range l.placeholders {
+		if strings.Contains(lower, strings.ToLower(placeholder)) {
+			found = append(found, placeholder)
+		}
+	}
+	for _, pattern := range placeholderPatterns {
+		for _, match := range pattern.FindAllString(line, -1) {
+			if !containsFold(found, match) {
+				found = append(found, match)
+			}
+		}
+	}
+	return found
+}
+
+// checkPreamble reports chat text before the first heading or after the
+// last paragraph, such as "Here is the persona..." or "Let me know if..."
+func checkPreamble(report *Report, lines []string) {
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") {
+			break
+		}
+		if preamblePattern.MatchString(strings.TrimLeft(trimmed, "*_ ")) {
+			report.add(Issue{Rule: RulePreamble, Severity: SeverityError, Line: i + 1,
+				Message: fmt.Sprintf("document starts with a preamble: %q", truncate(trimmed, 60))})
+			break
+		}
+	}
+
+	// Only the last few non-empty lines can be a sign-off
+	checked := 0
+	for i := len(lines) - 1; i >= 0 && checked < 3; i-- {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" || trimmed == "---" {
+			continue
+		}
+		checked++
+		if strings.HasPrefix(trimmed, "#") {
+			break
+		}
+		if postamblePattern.MatchString(strings.TrimLeft(trimmed, "*_ ")) {
+			report.add(Issue{Rule: RulePreamble, Severity: SeverityError, Line: i + 1,
+				Message: fmt.Sprintf("document ends with a sign-off: %q", truncate(trimmed, 60))})
+			break
+		}
+	}
+}
+
+// FindSection returns the section of a parsed persona that covers a
+// required section, or nil when it has none
+func FindSection(doc *models.Persona, req Requirement) *models.Section {
+	return findRequired(doc.Sections, req)
+}
+
+// findRequired finds a required section by title, then by number
+func findRequired(sections []models.Section, req Requirement) *models.Section {
+	title := normalizeTitle(req.Title)
+	if found := walk(sections, func(s *models.Section) bool { return normalizeTitle(s.Title) == title }); found != nil {
+		return found
+	}
+	return walk(sections, func(s *models.Section) bool { return s.Number == req.Number })
+}
+
+// walk returns the first section, in document order, that matches
+func walk(sections []models.Section, match func(*models.Section) bool) *models.Section {
+	for i := range sections {
+		if match(&sections[i]) {
+			return &sections[i]
+		}
+		if found := walk(sections[i].Subsections, match); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// numbered returns the numbered sections as requirements
+func numbered(sections []models.Section) []Requirement {
+	var required []Requirement
+	for _, s := range sections {
+		if s.Number != "" {
+			required = append(required, Requirement{Number: s.Number, Title: s.Title})
+		}
+	}
+	return required
+}
+
+// allNumbered returns every numbered section at any depth
+func allNumbered(sections []models.Section) []Requirement {
+	var required []Requirement
+	for _, s := range sections {
+		if s.Number != "" {
+			required = append(required, Requirement{Number: s.Number, Title: s.Title})
+		}
+		required = append(required, allNumbered(s.Subsections)...)
+	}
+	return required
+}
+
+// words counts the words in a section and its subsections
+func words(s models.Section) int {
+	n := len(strings.Fields(s.Body))
+	for _, sub := range s.Subsections {
+		n += len(strings.Fields(sub.Heading())) + words(sub)
+	}
+	return n
+}
+
+// normalizeTitle drops parentheticals, punctuation and case so that
+// "Voice/Communication Analysis" matches "Voice / Communication analysis"
+func normalizeTitle(title string) string {
+	return strings.Join(strings.Fields(titleNoise.ReplaceAllString(strings.ToLower(title), " ")), " ")
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// truncate shortens s to at most n characters
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n-3] + "..."
+}
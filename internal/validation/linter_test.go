@@ -0,0 +1,122 @@
+package validation
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testTemplate = `# Persona Template
+
+## Instructions
+
+Fill in every section.
+
+## Profile Structure
+
+### 1. Core Essence
+
+[one-paragraph summary of who they are]
+
+### 2. Voice
+
+[specific Hz values] and pacing.
+
+### 3. Values
+`
+
+// persona builds a persona with the given sections, each body long enough
+// to pass the word minimum used in the tests
+func persona(sections ...string) string {
+	var b strings.Builder
+	b.WriteString("# Ada Lovelace\n\nMathematician.\n\n")
+	for _, s := range sections {
+		b.WriteString("## " + s + "\n\nShe wrote the first published algorithm for a machine.\n\n")
+	}
+	return b.String()
+}
+
+func newTestLinter(t *testing.T) *Linter {
+	t.Helper()
+	l, err := NewLinter(testTemplate, Options{MinWords: 5})
+	if err != nil {
+		t.Fatalf("NewLinter() error = %v", err)
+	}
+	return l
+}
+
+func TestLinterRequired(t *testing.T) {
+	want := []Requirement{{"1", "Core Essence"}, {"2", "Voice"}, {"3", "Values"}}
+	if got := newTestLinter(t).Required(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Required() = %v, want %v", got, want)
+	}
+}
+
+func TestLint(t *testing.T) {
+	complete := persona("1. Core Essence", "2. Voice", "3. Values")
+
+	tests := []struct {
+		name   string
+		doc    string
+		rules  []string
+		score  int
+		passed bool
+	}{
+		{name: "complete", doc: complete, score: 100, passed: true},
+		{name: "sections matched by title", doc: persona("Core Essence", "Voice", "Values"), score: 100, passed: true},
+		{name: "sections matched by number", doc: persona("1. Essence", "2. Speaking", "3. Beliefs"), score: 100, passed: true},
+		{name: "missing section", doc: persona("1. Core Essence", "3. Values"), rules: []string{RuleMissingSection}, score: 67},
+		{name: "preamble", doc: "Here is the persona you asked for:\n\n" + complete, rules: []string{RulePreamble}, score: 100},
+		{name: "bold preamble", doc: "**Sure!** Below is the profile.\n\n" + complete, rules: []string{RulePreamble}, score: 100},
+		{name: "sign-off", doc: complete + "Let me know if you would like any changes.\n", rules: []string{RulePreamble}, score: 100},
+		{name: "template placeholder", doc: complete + "She speaks at [specific Hz values].\n", rules: []string{RulePlaceholder}, score: 100},
+		{name: "generic placeholder", doc: complete + "Hobbies: [insert hobbies here]. TODO\n", rules: []string{RulePlaceholder, RulePlaceholder}, score: 100},
+		{name: "moustache placeholder", doc: complete + "Born in {{BIRTH_YEAR}}.\n", rules: []string{RulePlaceholder}, score: 100},
+		{name: "placeholder in a code fence", doc: complete + "```\n[insert example]\n```\n", score: 100, passed: true},
+		{name: "markdown link is not a placeholder", doc: complete + "See [her notes](https://example.com).\n", score: 100, passed: true},
+		{name: "unclosed fence", doc: complete + "```\ncode\n", rules: []string{RuleMarkdown}, score: 100, passed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := newTestLinter(t).Lint("", tt.doc)
+			var rules []string
+			for _, issue := range report.Issues {
+				rules = append(rules, issue.Rule)
+			}
+			if !reflect.DeepEqual(rules, tt.rules) {
+				t.Errorf("Lint() rules = %v, want %v (issues %+v)", rules, tt.rules, report.Issues)
+			}
+			if report.Score != tt.score || report.Passed != tt.passed {
+				t.Errorf("Lint() = %s, want score %d, passed %v", report.Summary(), tt.score, tt.passed)
+			}
+		})
+	}
+}
+
+func TestLintShortSection(t *testing.T) {
+	doc := persona("1. Core Essence", "2. Voice") + "## 3. Values\n\nCuriosity.\n"
+	report := newTestLinter(t).Lint("Ada Lovelace", doc)
+
+	if report.Score != 83 {
+		t.Errorf("Lint() score = %d, want 83 with one short section", report.Score)
+	}
+	if got := report.Sections[2].Status; got != StatusShort {
+		t.Errorf("section 3 status = %q, want %q", got, StatusShort)
+	}
+	if !report.Passed || report.Warnings != 1 {
+		t.Errorf("Lint() = %s, want passed with 1 warning", report.Summary())
+	}
+}
+
+func TestLintUnparseable(t *testing.T) {
+	report := newTestLinter(t).Lint("Ada Lovelace", "")
+	if report.Passed || report.Score != 0 {
+		t.Errorf("Lint() = %s, want a failure scoring 0", report.Summary())
+	}
+	for _, s := range report.Sections {
+		if s.Status != StatusMissing {
+			t.Errorf("section %s status = %q, want %q", s.Number, s.Status, StatusMissing)
+		}
+	}
+}
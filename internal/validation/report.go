@@ -0,0 +1,172 @@
+package validation
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// Severity says whether an issue fails validation
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Section statuses
+const (
+	StatusOK      = "ok"
+	StatusShort   = "short"
+	StatusMissing = "missing"
+)
+
+// Issue is one problem found in a persona
+type Issue struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Section  string   `json:"section,omitempty"`
+	Line     int      `json:"line,omitempty"`
+	Message  string   `json:"message"`
+}
+
+// SectionResult is how a persona covers one required section
+type SectionResult struct {
+	Number  string `json:"number"`
+	Title   string `json:"title"`
+	Heading string `json:"heading,omitempty"` // As written in the persona; empty when missing
+	Words   int    `json:"words"`
+	Status  string `json:"status"`
+}
+
+// Report is the result of linting a persona. Score is the completeness
+// score out of 100: each required section counts fully when present and
+// long enough and half when short. Passed means no error was found.
+type Report struct {
+	Persona  string          `json:"persona"`
+	Score    int             `json:"score"`
+	Passed   bool            `json:"passed"`
+	Errors   int             `json:"errors"`
+	Warnings int             `json:"warnings"`
+	Sections []SectionResult `json:"sections"`
+	Issues   []Issue         `json:"issues"`
+}
+
+func (r *Report) add(issue Issue) {
+	r.Issues = append(r.Issues, issue)
+}
+
+// finish orders the issues by line, section issues first, and counts them
+// by severity
+func (r *Report) finish() {
+	sort.SliceStable(r.Issues, func(i, j int) bool { return r.Issues[i].Line < r.Issues[j].Line })
+	r.Errors, r.Warnings = 0, 0
+	for _, issue := range r.Issues {
+		if issue.Severity == SeverityError {
+			r.Errors++
+		} else {
+			r.Warnings++
+		}
+	}
+	r.Passed = r.Errors == 0
+	if r.Issues == nil {
+		r.Issues = []Issue{}
+	}
+}
+
+// Summary returns a one-line result, e.g. "83/100, passed with 2 warnings"
+func (r *Report) Summary() string {
+	result := "passed"
+	if !r.Passed {
+		result = fmt.Sprintf("failed with %d %s", r.Errors, plural(r.Errors, "error"))
+		if r.Warnings > 0 {
+			result += fmt.Sprintf(" and %d %s", r.Warnings, plural(r.Warnings, "warning"))
+		}
+	} else if r.Warnings > 0 {
+		result += fmt.Sprintf(" with %d %s", r.Warnings, plural(r.Warnings, "warning"))
+	}
+	return fmt.Sprintf("%d/100, %s", r.Score, result)
+}
+
+// Markdown renders the report for a pull request body
+func (r *Report) Markdown() string {
+	var b strings.Builder
+	icon := "✅"
+	if !r.Passed {
+		icon = "❌"
+	}
+	fmt.Fprintf(&b, "%s **Completeness %s**\n\n", icon, r.Summary())
+
+	b.WriteString("| Section | Words | Status |\n|---------|-------|--------|\n")
+	for _, s := range r.Sections {
+		status := "✅"
+		switch s.Status {
+		case StatusShort:
+			status = "⚠️ short"
+		case StatusMissing:
+			status = "❌ missing"
+		}
+		fmt.Fprintf(&b, "| %s | %d | %s |\n", Requirement{Number: s.Number, Title: s.Title}.Heading(), s.Words, status)
+	}
+
+	if len(r.Issues) > 0 {
+		b.WriteString("\n<details><summary>Issues</summary>\n\n")
+		for _, issue := range r.Issues {
+			icon := "⚠️"
+			if issue.Severity == SeverityError {
+				icon = "❌"
+			}
+			fmt.Fprintf(&b, "- %s `%s` %s%s\n", icon, issue.Rule, issue.Message, issue.location())
+		}
+		b.WriteString("\n</details>\n")
+	}
+	return b.String()
+}
+
+// location describes where an issue is, e.g. " (line 12, 2. Voice Analysis)"
+func (i Issue) location() string {
+	var parts []string
+	if i.Line > 0 {
+		parts = append(parts, fmt.Sprintf("line %d", i.Line))
+	}
+	if i.Section != "" && i.Rule != RuleMissingSection { // Already named in the message
+		parts = append(parts, i.Section)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
+}
+
+// WriteReport prints a table of reports followed by each persona's issues
+func WriteReport(w io.Writer, reports []*Report) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PERSONA\tSCORE\tRESULT\tERRORS\tWARNINGS")
+	for _, r := range reports {
+		result := "passed"
+		if !r.Passed {
+			result = "failed"
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%d\t%d\n", r.Persona, r.Score, result, r.Errors, r.Warnings)
+	}
+	tw.Flush()
+
+	for _, r := range reports {
+		if len(r.Issues) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\n%s:\n", r.Persona)
+		for _, issue := range r.Issues {
+			fmt.Fprintf(w, "  %-7s %-19s %s%s\n", issue.Severity, issue.Rule, issue.Message, issue.location())
+		}
+	}
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return word
+	}
+	return word + "s"
+}
@@ -0,0 +1,92 @@
+package export
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/twin2ai/studio/pkg/models"
+)
+
+// Limits of the OpenAI Assistants API
+const (
+	assistantNameLimit        = 256
+	assistantDescriptionLimit = 512
+	assistantMetadataLimit    = 512
+)
+
+// Assistant is the body of an OpenAI Assistants API create request
+type Assistant struct {
+	Model        string            `json:"model"`
+	Name         string            `json:"name"`
+	Description  string            `json:"description"`
+	Instructions string            `json:"instructions"`
+	Tools        []interface{}     `json:"tools"`
+	Metadata     map[string]string `json:"metadata"`
+}
+
+// OpenAIAssistant builds an assistant that plays the persona, on
+// opts.Model or DefaultAssistantModel
+func OpenAIAssistant(p *models.Persona, opts Options) *Assistant {
+	model := opts.Model
+	if model == "" {
+		model = DefaultAssistantModel
+	}
+
+	metadata := map[string]string{"source": "twin2ai/studio"}
+	if p.Provenance.Synthesizer != "" {
+		metadata["synthesizer"] = truncate(p.Provenance.Synthesizer, assistantMetadataLimit)
+	}
+	if len(p.Provenance.Providers) > 0 {
+		var providers []string
+		for _, o := range p.Provenance.Providers {
+			providers = append(providers, o.Provider)
+		}
+		metadata["providers"] = truncate(strings.Join(providers, ","), assistantMetadataLimit)
+	}
+	if v := CurrentVersion(p); v > 0 {
+		metadata["version"] = strconv.Itoa(v)
+	}
+
+	return &Assistant{
+		Model:        model,
+		Name:         truncate(displayName(p), assistantNameLimit),
+		Description:  truncate(Description(p), assistantDescriptionLimit),
+		Instructions: Instructions(p),
+		Tools:        []interface{}{},
+		Metadata:     metadata,
+	}
+}
+
+// JSON encodes the assistant as a request body
+func (a *Assistant) JSON() ([]byte, error) {
+	return encodeJSON(a)
+}
+
+// Modelfile builds an Ollama Modelfile that runs the persona on opts.Model
+// or DefaultOllamaModel
+func Modelfile(p *models.Persona, opts Options) string {
+	model := opts.Model
+	if model == "" {
+		model = DefaultOllamaModel
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", displayName(p))
+	fmt.Fprintf(&b, "# %s\n", sources(p))
+	b.WriteString("# Create it with: ollama create <name> -f <this file>\n\n")
+	fmt.Fprintf(&b, "FROM %s\n\n", model)
+	// A triple quote would end the SYSTEM block early
+	fmt.Fprintf(&b, "SYSTEM \"\"\"%s\"\"\"\n", strings.ReplaceAll(Instructions(p), `"""`, `"”"`))
+
+	// Sample quotes seed the conversation so the model starts in voice
+	if p.Profile != nil && len(p.Profile.SpeechStyle.SampleQuotes) > 0 {
+		b.WriteString("\n")
+		for _, q := range p.Profile.SpeechStyle.SampleQuotes {
+			if q = strings.TrimSpace(q); q != "" && !strings.Contains(q, "\n") {
+				fmt.Fprintf(&b, "MESSAGE assistant %s\n", q)
+			}
+		}
+	}
+	return b.String()
+}
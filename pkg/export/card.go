@@ -0,0 +1,258 @@
+package export
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+
+	"github.com/twin2ai/studio/pkg/models"
+)
+
+// Character Card V2 identifiers, from the spec at
+// https://github.com/malfoyslastname/character-card-spec-v2
+const (
+	CardSpec        = "chara_card_v2"
+	CardSpecVersion = "2.0"
+	// CardChunkKeyword is the PNG tEXt keyword holding the base64 card JSON
+	CardChunkKeyword = "chara"
+)
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// Card is a Character Card V2
+type Card struct {
+	Spec        string   `json:"spec"`
+	SpecVersion string   `json:"spec_version"`
+	Data        CardData `json:"data"`
+}
+
+// CardData is the character a card describes
+type CardData struct {
+	Name                    string                 `json:"name"`
+	Description             string                 `json:"description"`
+	Personality             string                 `json:"personality"`
+	Scenario                string                 `json:"scenario"`
+	FirstMes                string                 `json:"first_mes"`
+	MesExample              string                 `json:"mes_example"`
+	CreatorNotes            string                 `json:"creator_notes"`
+	SystemPrompt            string                 `json:"system_prompt"`
+	PostHistoryInstructions string                 `json:"post_history_instructions"`
+	AlternateGreetings      []string               `json:"alternate_greetings"`
+	Tags                    []string               `json:"tags"`
+	Creator                 string                 `json:"creator"`
+	CharacterVersion        string                 `json:"character_version"`
+	Extensions              map[string]interface{} `json:"extensions"`
+}
+
+// CharacterCard builds a card from the persona. The persona document is the
+// card's description; persona.json, when present, supplies the personality,
+// greeting and example messages.
+func CharacterCard(p *models.Persona, opts Options) *Card {
+	data := CardData{
+		Name:               displayName(p),
+		Description:        strings.TrimSpace(p.Content),
+		CreatorNotes:       Description(p) + "\n\n" + sources(p),
+		AlternateGreetings: []string{},
+		Tags:               []string{"persona"},
+		Creator:            opts.Creator,
+		Extensions:         map[string]interface{}{},
+	}
+	if v := CurrentVersion(p); v > 0 {
+		data.CharacterVersion = fmt.Sprintf("v%d", v)
+	}
+
+	if p.Profile != nil {
+		var traits []string
+		for _, t := range p.Profile.PersonalityTraits {
+			if t.Description != "" {
+				traits = append(traits, fmt.Sprintf("%s: %s", t.Trait, t.Description))
+			} else {
+				traits = append(traits, t.Trait)
+			}
+		}
+		data.Personality = strings.Join(traits, "\n")
+
+		quotes := p.Profile.SpeechStyle.SampleQuotes
+		if len(quotes) > 0 {
+			data.FirstMes = quotes[0]
+			var examples []string
+			for _, q := range quotes[1:] {
+				examples = append(examples, "<START>\n{{char}}: "+q)
+			}
+			data.MesExample = strings.Join(examples, "\n")
+		}
+		if occupation := p.Profile.Identity.Occupation; occupation != "" {
+			data.Tags = append(data.Tags, occupation)
+		}
+	}
+	if data.MesExample == "" {
+		if s := p.Section("Dialogue Examples Bank"); s != nil && s.Body != "" {
+			data.MesExample = "<START>\n" + s.Body
+		}
+	}
+
+	return &Card{Spec: CardSpec, SpecVersion: CardSpecVersion, Data: data}
+}
+
+// JSON encodes the card
+func (c *Card) JSON() ([]byte, error) {
+	return encodeJSON(c)
+}
+
+// PNG embeds the card in avatar, a PNG image, as a base64 "chara" tEXt
+// chunk. Any card already in the image is replaced. A plain portrait is
+// used when avatar is empty.
+func (c *Card) PNG(avatar []byte) ([]byte, error) {
+	if len(avatar) == 0 {
+		var err error
+		if avatar, err = placeholderAvatar(); err != nil {
+			return nil, err
+		}
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode character card: %w", err)
+	}
+	return embedText(avatar, CardChunkKeyword, base64.StdEncoding.EncodeToString(data))
+}
+
+// ReadCardPNG extracts the card embedded in a PNG
+func ReadCardPNG(data []byte) (*Card, error) {
+	chunks, err := pngChunks(data)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range chunks {
+		keyword, text, ok := textChunk(c)
+		if !ok || keyword != CardChunkKeyword {
+			continue
+		}
+		decoded, err := base64.StdEncoding.DecodeString(text)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode character card: %w", err)
+		}
+		var card Card
+		if err := json.Unmarshal(decoded, &card); err != nil {
+			return nil, fmt.Errorf("failed to parse character card: %w", err)
+		}
+		return &card, nil
+	}
+	return nil, errors.New("no character card in image")
+}
+
+// chunk is a PNG chunk with its type and data
+type chunk struct {
+	kind string
+	data []byte
+}
+
+// embedText inserts a tEXt chunk before the image's IEND chunk, dropping
+// existing tEXt chunks with the same keyword
+func embedText(img []byte, keyword, text string) ([]byte, error) {
+	chunks, err := pngChunks(img)
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	out.Write(pngSignature)
+	for _, c := range chunks {
+		if k, _, ok := textChunk(c); ok && k == keyword {
+			continue
+		}
+		if c.kind == "IEND" {
+			writeChunk(&out, chunk{kind: "tEXt", data: []byte(keyword + "\x00" + text)})
+		}
+		writeChunk(&out, c)
+	}
+	return out.Bytes(), nil
+}
+
+// pngChunks splits a PNG into its chunks, checking each one's CRC
+func pngChunks(data []byte) ([]chunk, error) {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, errors.New("avatar is not a PNG image")
+	}
+	var chunks []chunk
+	rest := data[len(pngSignature):]
+	for len(rest) > 0 {
+		if len(rest) < 12 {
+			return nil, errors.New("truncated PNG chunk")
+		}
+		length := binary.BigEndian.Uint32(rest[:4])
+		if uint64(length) > uint64(len(rest)-12) {
+			return nil, errors.New("truncated PNG chunk")
+		}
+		body := rest[4 : 8+length]
+		if crc32.ChecksumIEEE(body) != binary.BigEndian.Uint32(rest[8+length:12+length]) {
+			return nil, fmt.Errorf("corrupt PNG chunk %q", body[:4])
+		}
+		chunks = append(chunks, chunk{kind: string(body[:4]), data: body[4:]})
+		rest = rest[12+length:]
+		if string(body[:4]) == "IEND" {
+			break
+		}
+	}
+	if len(chunks) == 0 || chunks[len(chunks)-1].kind != "IEND" {
+		return nil, errors.New("PNG image has no IEND chunk")
+	}
+	return chunks, nil
+}
+
+// writeChunk appends a chunk with its length and CRC
+func writeChunk(out *bytes.Buffer, c chunk) {
+	var header [4]byte
+	binary.BigEndian.PutUint32(header[:], uint32(len(c.data)))
+	out.Write(header[:])
+
+	body := append([]byte(c.kind), c.data...)
+	out.Write(body)
+	binary.BigEndian.PutUint32(header[:], crc32.ChecksumIEEE(body))
+	out.Write(header[:])
+}
+
+// textChunk returns the keyword and text of a tEXt chunk
+func textChunk(c chunk) (string, string, bool) {
+	if c.kind != "tEXt" {
+		return "", "", false
+	}
+	keyword, text, ok := bytes.Cut(c.data, []byte{0})
+	return string(keyword), string(text), ok
+}
+
+// placeholderAvatar draws a plain 400x600 portrait for cards without an avatar
+func placeholderAvatar() ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, 400, 600))
+	fill := color.RGBA{R: 0x3b, G: 0x4a, B: 0x6b, A: 0xff}
+	for y := 0; y < 600; y++ {
+		for x := 0; x < 400; x++ {
+			img.SetRGBA(x, y, fill)
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode avatar: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeJSON encodes v as indented JSON without escaping HTML characters,
+// which persona documents are full of
+func encodeJSON(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return nil, fmt.Errorf("failed to encode export: %w", err)
+	}
+	return buf.Bytes(), nil
+}
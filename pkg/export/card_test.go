@@ -0,0 +1,103 @@
+package export
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/twin2ai/studio/pkg/models"
+)
+
+const testPersona = `# Grace Hopper
+
+## Core Identity
+
+Computer scientist and United States Navy rear admiral.
+
+## Dialogue Examples Bank
+
+"It's easier to ask forgiveness than it is to get permission."
+`
+
+// charaChunks returns the text of every "chara" tEXt chunk in a PNG
+func charaChunks(t *testing.T, data []byte) []string {
+	t.Helper()
+	chunks, err := pngChunks(data)
+	if err != nil {
+		t.Fatalf("pngChunks() error = %v", err)
+	}
+	var texts []string
+	for _, c := range chunks {
+		if keyword, text, ok := textChunk(c); ok && keyword == CardChunkKeyword {
+			texts = append(texts, text)
+		}
+	}
+	return texts
+}
+
+func TestCardPNG(t *testing.T) {
+	p, err := models.Parse(testPersona)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	opts := Options{Creator: "studio"}
+
+	data, err := Export(p, FormatCardPNG, opts)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if !bytes.HasPrefix(data, pngSignature) {
+		t.Fatal("Export() did not return a PNG")
+	}
+
+	texts := charaChunks(t, data)
+	if len(texts) != 1 {
+		t.Fatalf("found %d chara chunks, want 1", len(texts))
+	}
+	decoded, err := base64.StdEncoding.DecodeString(texts[0])
+	if err != nil {
+		t.Fatalf("chara chunk is not base64: %v", err)
+	}
+	want, err := json.Marshal(CharacterCard(p, opts))
+	if err != nil {
+		t.Fatalf("failed to encode card: %v", err)
+	}
+	if !bytes.Equal(decoded, want) {
+		t.Errorf("chara chunk =\n%s\nwant the card JSON\n%s", decoded, want)
+	}
+
+	card, err := ReadCardPNG(data)
+	if err != nil {
+		t.Fatalf("ReadCardPNG() error = %v", err)
+	}
+	if !reflect.DeepEqual(card, CharacterCard(p, opts)) {
+		t.Errorf("ReadCardPNG() = %+v, want %+v", card, CharacterCard(p, opts))
+	}
+	if card.Spec != CardSpec || card.Data.Name != "Grace Hopper" || card.Data.Creator != "studio" {
+		t.Errorf("ReadCardPNG() spec, name, creator = %q, %q, %q", card.Spec, card.Data.Name, card.Data.Creator)
+	}
+
+	// Embedding a card in an image that has one replaces it
+	card.Data.Name = "Amazing Grace"
+	again, err := card.PNG(data)
+	if err != nil {
+		t.Fatalf("PNG() error = %v", err)
+	}
+	if n := len(charaChunks(t, again)); n != 1 {
+		t.Errorf("found %d chara chunks after embedding twice, want 1", n)
+	}
+	if reread, err := ReadCardPNG(again); err != nil || reread.Data.Name != "Amazing Grace" {
+		t.Errorf("ReadCardPNG() after embedding twice = %v, %v, want the new card", reread, err)
+	}
+}
+
+func TestCardPNGRejectsNonPNG(t *testing.T) {
+	if _, err := CharacterCard(&models.Persona{Content: "x"}, Options{}).PNG([]byte("GIF89a")); err == nil {
+		t.Error("PNG() with a GIF avatar succeeded, want an error")
+	}
+	if _, err := ReadCardPNG([]byte("not an image")); err == nil {
+		t.Error("ReadCardPNG() of non-PNG data succeeded, want an error")
+	}
+}
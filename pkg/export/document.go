@@ -0,0 +1,101 @@
+package export
+
+import (
+	"time"
+
+	"github.com/twin2ai/studio/pkg/models"
+)
+
+// DocumentVersion identifies the layout of Document
+const DocumentVersion = "persona-export.v1"
+
+// Document is the plain JSON and YAML export: the parsed persona with its
+// structured profile and provenance
+type Document struct {
+	SchemaVersion string          `json:"schema_version"`
+	Name          string          `json:"name"`
+	Title         string          `json:"title,omitempty"`
+	Aliases       []string        `json:"aliases,omitempty"`
+	Version       int             `json:"version,omitempty"`
+	Summary       string          `json:"summary,omitempty"`
+	Sections      []Section       `json:"sections"`
+	Profile       *models.Profile `json:"profile,omitempty"`
+	Provenance    *Provenance     `json:"provenance,omitempty"`
+}
+
+// Section is a persona section with its subsections
+type Section struct {
+	Number      string    `json:"number,omitempty"`
+	Title       string    `json:"title"`
+	Body        string    `json:"body,omitempty"`
+	Subsections []Section `json:"subsections,omitempty"`
+}
+
+// Provenance records the providers, models and prompts behind the persona
+type Provenance struct {
+	Providers        []ProviderOutput  `json:"providers,omitempty"`
+	Synthesizer      string            `json:"synthesizer,omitempty"`
+	SynthesizerModel string            `json:"synthesizer_model,omitempty"`
+	Method           string            `json:"method,omitempty"`
+	PromptVersions   map[string]string `json:"prompt_versions,omitempty"`
+	GeneratedAt      string            `json:"generated_at,omitempty"`
+}
+
+// ProviderOutput names a contributing provider and its model
+type ProviderOutput struct {
+	Provider string `json:"provider"`
+	Model    string `json:"model,omitempty"`
+}
+
+// NewDocument builds the plain export of a persona
+func NewDocument(p *models.Persona) *Document {
+	d := &Document{
+		SchemaVersion: DocumentVersion,
+		Name:          displayName(p),
+		Title:         p.Title,
+		Aliases:       p.Aliases,
+		Version:       CurrentVersion(p),
+		Summary:       p.Summary,
+		Sections:      sections(p.Sections),
+		Profile:       p.Profile,
+	}
+
+	prov := p.Provenance
+	if len(prov.Providers) > 0 || prov.Synthesizer != "" {
+		d.Provenance = &Provenance{
+			Synthesizer:      prov.Synthesizer,
+			SynthesizerModel: prov.SynthesizerModel,
+			Method:           prov.Method,
+			PromptVersions:   prov.PromptVersions,
+		}
+		for _, o := range prov.Providers {
+			d.Provenance.Providers = append(d.Provenance.Providers, ProviderOutput{Provider: o.Provider, Model: o.Model})
+		}
+		if !prov.GeneratedAt.IsZero() {
+			d.Provenance.GeneratedAt = prov.GeneratedAt.UTC().Format(time.RFC3339)
+		}
+	}
+	return d
+}
+
+// JSON encodes the document
+func (d *Document) JSON() ([]byte, error) {
+	return encodeJSON(d)
+}
+
+// YAML encodes the document, with the same fields as JSON
+func (d *Document) YAML() ([]byte, error) {
+	data, err := encodeJSON(d)
+	if err != nil {
+		return nil, err
+	}
+	return jsonToYAML(data)
+}
+
+func sections(in []models.Section) []Section {
+	out := make([]Section, 0, len(in))
+	for _, s := range in {
+		out = append(out, Section{Number: s.Number, Title: s.Title, Body: s.Body, Subsections: sections(s.Subsections)})
+	}
+	return out
+}
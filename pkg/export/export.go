@@ -0,0 +1,195 @@
+// Package export turns a persona, as loaded by models.LoadFolder, into the
+// formats other runtimes import: Character Card V2 (JSON or embedded in a
+// PNG, as used by SillyTavern), OpenAI Assistants definitions, Ollama
+// Modelfiles, and plain JSON or YAML.
+package export
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/twin2ai/studio/pkg/models"
+)
+
+// Format is an export format
+type Format string
+
+const (
+	FormatCard      Format = "card"      // Character Card V2 JSON
+	FormatCardPNG   Format = "card-png"  // Character Card V2 embedded in a PNG
+	FormatAssistant Format = "assistant" // OpenAI Assistants API definition
+	FormatOllama    Format = "ollama"    // Ollama Modelfile
+	FormatJSON      Format = "json"
+	FormatYAML      Format = "yaml"
+)
+
+// Formats lists every export format
+var Formats = []Format{FormatCard, FormatCardPNG, FormatAssistant, FormatOllama, FormatJSON, FormatYAML}
+
+// Default base models, used when Options.Model is empty
+const (
+	DefaultAssistantModel = "gpt-4o"
+	DefaultOllamaModel    = "llama3.1"
+)
+
+// Options adjust an export
+type Options struct {
+	// Model is the base model of an assistant or Modelfile
+	Model string
+	// Creator is credited in character cards
+	Creator string
+	// Avatar is the PNG a card is embedded in; a plain image is used when empty
+	Avatar []byte
+}
+
+// ParseFormat returns the format named s
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats {
+		if strings.EqualFold(s, string(f)) {
+			return f, nil
+		}
+	}
+	names := make([]string, len(Formats))
+	for i, f := range Formats {
+		names[i] = string(f)
+	}
+	return "", fmt.Errorf("unknown export format %q (expected one of %s)", s, strings.Join(names, ", "))
+}
+
+// Filename returns the conventional file name of a persona exported to
+// format, e.g. "ada_lovelace.card.png"
+func Filename(folderName string, format Format) string {
+	switch format {
+	case FormatCard:
+		return folderName + ".card.json"
+	case FormatCardPNG:
+		return folderName + ".card.png"
+	case FormatAssistant:
+		return folderName + ".assistant.json"
+	case FormatOllama:
+		return folderName + ".Modelfile"
+	default:
+		return folderName + "." + string(format)
+	}
+}
+
+// Export renders the persona in format
+func Export(p *models.Persona, format Format, opts Options) ([]byte, error) {
+	if p == nil || strings.TrimSpace(p.Content) == "" {
+		return nil, fmt.Errorf("persona has no content to export")
+	}
+
+	switch format {
+	case FormatCard:
+		return CharacterCard(p, opts).JSON()
+	case FormatCardPNG:
+		return CharacterCard(p, opts).PNG(opts.Avatar)
+	case FormatAssistant:
+		return OpenAIAssistant(p, opts).JSON()
+	case FormatOllama:
+		return []byte(Modelfile(p, opts)), nil
+	case FormatJSON:
+		return NewDocument(p).JSON()
+	case FormatYAML:
+		return NewDocument(p).YAML()
+	default:
+		return nil, fmt.Errorf("unknown export format %q", format)
+	}
+}
+
+// Instructions is the system prompt that has a model play the persona: a
+// short role instruction followed by the persona document
+func Instructions(p *models.Persona) string {
+	name := displayName(p)
+	var b strings.Builder
+	fmt.Fprintf(&b, "You are %s.", name)
+	if len(p.Aliases) > 0 {
+		fmt.Fprintf(&b, " You are also known as %s.", strings.Join(p.Aliases, ", "))
+	}
+	fmt.Fprintf(&b, " Stay in character as %s in every reply: speak in their voice and answer from their knowledge, values and experience as the persona profile below describes them. Do not say you are an AI or step out of the role unless the user asks you to.\n\n", name)
+	b.WriteString("PERSONA PROFILE:\n\n")
+	b.WriteString(strings.TrimSpace(p.Content))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// Description returns a one-paragraph description of the persona: the
+// profile's summary, else the document's, else its title
+func Description(p *models.Persona) string {
+	if p.Profile != nil && strings.TrimSpace(p.Profile.Identity.Summary) != "" {
+		return strings.TrimSpace(p.Profile.Identity.Summary)
+	}
+	if summary := strings.TrimSpace(p.Summary); summary != "" {
+		paragraph, _, _ := strings.Cut(summary, "\n\n")
+		return strings.TrimSpace(paragraph)
+	}
+	return p.Title
+}
+
+// CurrentVersion returns the number of the persona's latest recorded
+// version, or 0 when it has no history
+func CurrentVersion(p *models.Persona) int {
+	if len(p.History) == 0 {
+		return 0
+	}
+	return p.History[len(p.History)-1].Number
+}
+
+// displayName returns the persona's name, falling back to its title
+func displayName(p *models.Persona) string {
+	if p.Name != "" {
+		return p.Name
+	}
+	if p.Title != "" {
+		return p.Title
+	}
+	return "the persona"
+}
+
+// sources describes where the persona came from, e.g. "Synthesized by
+// gemini (gemini-2.0-flash) from claude, gemini and gpt."
+func sources(p *models.Persona) string {
+	var providers []string
+	for _, o := range p.Provenance.Providers {
+		providers = append(providers, o.Provider)
+	}
+	var b strings.Builder
+	b.WriteString("Generated by twin2ai studio")
+	if p.Provenance.Synthesizer != "" {
+		b.WriteString(", synthesized by " + p.Provenance.Synthesizer)
+		if p.Provenance.SynthesizerModel != "" {
+			b.WriteString(" (" + p.Provenance.SynthesizerModel + ")")
+		}
+	}
+	if len(providers) > 0 {
+		b.WriteString(" from " + joinList(providers))
+	}
+	if v := CurrentVersion(p); v > 0 {
+		fmt.Fprintf(&b, "; version %d", v)
+	}
+	b.WriteString(".")
+	return b.String()
+}
+
+// joinList joins items as "a, b and c"
+func joinList(items []string) string {
+	if len(items) < 2 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
+
+// truncate shortens text to at most limit characters, cutting at a word
+// boundary and ending with an ellipsis
+func truncate(text string, limit int) string {
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	runes := []rune(text)
+	cut := string(runes[:limit-1])
+	if i := strings.LastIndexAny(cut, " \n"); i > len(cut)/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " \n,;:") + "…"
+}
@@ -0,0 +1,192 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// node is a decoded JSON value that keeps object keys in document order
+type node struct {
+	keys   []string // Object keys; nil for arrays and scalars
+	values []*node  // Object values or array items
+	object bool
+	array  bool
+	scalar string // YAML text of a scalar, or the raw string for strings
+	str    bool
+}
+
+// jsonToYAML converts a JSON document to block-style YAML, keeping key
+// order. Multi-line strings become literal blocks; other strings are
+// double-quoted, which YAML reads like JSON strings.
+func jsonToYAML(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	root, err := decodeNode(decoder)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert export to YAML: %w", err)
+	}
+
+	var b strings.Builder
+	switch {
+	case root.object && len(root.keys) > 0:
+		writeMapping(&b, root, 0)
+	case root.array && len(root.values) > 0:
+		writeSequence(&b, root, 0)
+	default:
+		b.WriteString(inline(root) + "\n")
+	}
+	return []byte(b.String()), nil
+}
+
+func decodeNode(decoder *json.Decoder) (*node, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := token.(type) {
+	case json.Delim:
+		n := &node{object: t == '{', array: t == '['}
+		for decoder.More() {
+			if n.object {
+				key, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+				n.keys = append(n.keys, key.(string))
+			}
+			value, err := decodeNode(decoder)
+			if err != nil {
+				return nil, err
+			}
+			n.values = append(n.values, value)
+		}
+		if _, err := decoder.Token(); err != nil { // Closing delimiter
+			return nil, err
+		}
+		return n, nil
+	case string:
+		return &node{scalar: t, str: true}, nil
+	case json.Number:
+		return &node{scalar: t.String()}, nil
+	case bool:
+		return &node{scalar: fmt.Sprint(t)}, nil
+	default:
+		return &node{scalar: "null"}, nil
+	}
+}
+
+func writeMapping(b *strings.Builder, n *node, indent int) {
+	pad := strings.Repeat(" ", indent)
+	for i, key := range n.keys {
+		b.WriteString(pad + yamlKey(key) + ":")
+		writeValue(b, n.values[i], indent+2)
+	}
+}
+
+func writeSequence(b *strings.Builder, n *node, indent int) {
+	pad := strings.Repeat(" ", indent)
+	for _, item := range n.values {
+		if (item.object || item.array) && len(item.values) > 0 {
+			// Render the item one level deeper, then put the dash in place
+			// of the first line's indentation
+			var nested strings.Builder
+			if item.object {
+				writeMapping(&nested, item, indent+2)
+			} else {
+				writeSequence(&nested, item, indent+2)
+			}
+			b.WriteString(pad + "- " + strings.TrimPrefix(nested.String(), pad+"  "))
+			continue
+		}
+		b.WriteString(pad + "-")
+		writeValue(b, item, indent+2)
+	}
+}
+
+// writeValue writes a value after its key or dash, at indent for nested lines
+func writeValue(b *strings.Builder, n *node, indent int) {
+	switch {
+	case n.object && len(n.values) > 0:
+		b.WriteString("\n")
+		writeMapping(b, n, indent)
+	case n.array && len(n.values) > 0:
+		b.WriteString("\n")
+		writeSequence(b, n, indent)
+	case n.str && literalBlock(n.scalar):
+		pad := strings.Repeat(" ", indent)
+		text := n.scalar
+		if strings.HasSuffix(text, "\n") {
+			b.WriteString(" |\n")
+			text = strings.TrimSuffix(text, "\n")
+		} else {
+			b.WriteString(" |-\n")
+		}
+		for _, line := range strings.Split(text, "\n") {
+			if line == "" {
+				b.WriteString("\n")
+			} else {
+				b.WriteString(pad + line + "\n")
+			}
+		}
+	default:
+		b.WriteString(" " + inline(n) + "\n")
+	}
+}
+
+// inline renders a scalar or an empty collection on one line
+func inline(n *node) string {
+	switch {
+	case n.object:
+		return "{}"
+	case n.array:
+		return "[]"
+	case n.str:
+		return quote(n.scalar)
+	default:
+		return n.scalar
+	}
+}
+
+// quote double-quotes a string; JSON escapes are valid in YAML
+func quote(s string) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// literalBlock reports whether a string can be written as a literal block
+// and read back unchanged: it spans lines, starts with neither a space nor a
+// line break, ends with at most one line break and no trailing spaces, and
+// has no control characters or other line breaks
+func literalBlock(s string) bool {
+	if !strings.Contains(s, "\n") || strings.HasPrefix(s, " ") || strings.HasPrefix(s, "\n") ||
+		strings.HasSuffix(s, "\n\n") || strings.HasSuffix(strings.TrimSuffix(s, "\n"), " ") {
+		return false
+	}
+	for _, r := range s {
+		if r < 0x20 && r != '\n' || r == 0x7f || r == 0x85 || r == 0x2028 || r == 0x2029 {
+			return false
+		}
+	}
+	return true
+}
+
+// yamlKey quotes a key unless it is a plain identifier that YAML would not
+// read as a boolean or null
+func yamlKey(key string) string {
+	switch strings.ToLower(key) {
+	case "", "true", "false", "yes", "no", "on", "off", "y", "n", "null", "~":
+		return quote(key)
+	}
+	for i, r := range key {
+		letter := r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+		if !letter && (i == 0 || !(r == '-' || r >= '0' && r <= '9')) {
+			return quote(key)
+		}
+	}
+	return key
+}
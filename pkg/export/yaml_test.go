@@ -0,0 +1,111 @@
+package export
+
+import (
+	"testing"
+)
+
+func TestJSONToYAML(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want string
+	}{
+		{
+			name: "literal block with trailing newline",
+			json: `{"text":"line one\nline two\n"}`,
+			want: "text: |\n  line one\n  line two\n",
+		},
+		{
+			name: "literal block without trailing newline",
+			json: `{"text":"line one\nline two"}`,
+			want: "text: |-\n  line one\n  line two\n",
+		},
+		{
+			name: "blank line inside block",
+			json: `{"text":"para one\n\npara two"}`,
+			want: "text: |-\n  para one\n\n  para two\n",
+		},
+		{
+			name: "two trailing newlines are quoted",
+			json: `{"text":"trailing\n\n"}`,
+			want: "text: \"trailing\\n\\n\"\n",
+		},
+		{
+			name: "leading space is quoted",
+			json: `{"text":" leading space\nx"}`,
+			want: "text: \" leading space\\nx\"\n",
+		},
+		{
+			name: "quotes and HTML are not escaped",
+			json: `{"quote":"She said \"hi\" <b>"}`,
+			want: "quote: \"She said \\\"hi\\\" <b>\"\n",
+		},
+		{
+			name: "reserved and unusual keys",
+			json: `{"yes":1,"on":true,"null":null,"No":"n","":"e","ok-key":1,"9lives":2,"a b":3}`,
+			want: `"yes": 1
+"on": true
+"null": null
+"No": "n"
+"": "e"
+ok-key: 1
+"9lives": 2
+"a b": 3
+`,
+		},
+		{
+			name: "nested sequences of mappings",
+			json: `{"sections":[{"title":"Voice","subsections":[{"title":"Tone","body":"Dry"}]},{"title":"Values","subsections":[]}]}`,
+			want: `sections:
+  - title: "Voice"
+    subsections:
+      - title: "Tone"
+        body: "Dry"
+  - title: "Values"
+    subsections: []
+`,
+		},
+		{
+			name: "top-level sequence of mappings",
+			json: `[{"a":1,"b":{"c":[1]}}]`,
+			want: `- a: 1
+  b:
+    c:
+      - 1
+`,
+		},
+		{
+			name: "empty collections",
+			json: `{"list":[[1,2],[],{}],"empty":{},"none":[]}`,
+			want: `list:
+  - - 1
+    - 2
+  - []
+  - {}
+empty: {}
+none: []
+`,
+		},
+		{name: "empty object", json: `{}`, want: "{}\n"},
+		{name: "empty array", json: `[]`, want: "[]\n"},
+		{name: "top-level string", json: `"x"`, want: "\"x\"\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonToYAML([]byte(tt.json))
+			if err != nil {
+				t.Fatalf("jsonToYAML() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("jsonToYAML(%s) =\n%s\nwant\n%s", tt.json, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONToYAMLInvalid(t *testing.T) {
+	if _, err := jsonToYAML([]byte(`{"a":`)); err == nil {
+		t.Error("jsonToYAML() of truncated JSON succeeded, want an error")
+	}
+}
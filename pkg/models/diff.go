@@ -0,0 +1,116 @@
+package models
+
+import "strings"
+
+// Kinds of section change reported by Diff
+const (
+	ChangeAdded     = "added"
+	ChangeRemoved   = "removed"
+	ChangeModified  = "modified"
+	ChangeUnchanged = "unchanged"
+)
+
+// SectionChange is how one top-level section differs between two versions
+// of a persona
+type SectionChange struct {
+	Number  string
+	Title   string
+	Kind    string
+	Added   int // Lines only in the new version, including subsections
+	Removed int // Lines only in the old version, including subsections
+}
+
+// Heading returns the section heading, with its number
+func (c SectionChange) Heading() string {
+	return Section{Number: c.Number, Title: c.Title}.Heading()
+}
+
+// Changed reports whether the section differs between the versions
+func (c SectionChange) Changed() bool {
+	return c.Kind != ChangeUnchanged
+}
+
+// Diff compares the top-level sections of two versions of a persona.
+// Sections are matched by title, then by number, so renumbered sections
+// still line up. Changes follow the new version's order, with removed
+// sections last. Blank lines and surrounding whitespace are ignored.
+func Diff(before, after *Persona) []SectionChange {
+	matched := make([]bool, len(before.Sections))
+	var changes []SectionChange
+
+	for _, s := range after.Sections {
+		change := SectionChange{Number: s.Number, Title: s.Title, Kind: ChangeAdded}
+		if i := matchSection(before.Sections, matched, s); i >= 0 {
+			matched[i] = true
+			change.Added, change.Removed = lineDiff(sectionLines(before.Sections[i]), sectionLines(s))
+			change.Kind = ChangeModified
+			if change.Added == 0 && change.Removed == 0 {
+				change.Kind = ChangeUnchanged
+			}
+		} else {
+			change.Added = len(sectionLines(s))
+		}
+		changes = append(changes, change)
+	}
+
+	for i, s := range before.Sections {
+		if !matched[i] {
+			changes = append(changes, SectionChange{Number: s.Number, Title: s.Title, Kind: ChangeRemoved, Removed: len(sectionLines(s))})
+		}
+	}
+	return changes
+}
+
+// matchSection returns the index of the unmatched section with the same
+// title as s, or else the same number, or -1
+func matchSection(sections []Section, matched []bool, s Section) int {
+	for i, candidate := range sections {
+		if !matched[i] && strings.EqualFold(candidate.Title, s.Title) {
+			return i
+		}
+	}
+	if s.Number == "" {
+		return -1
+	}
+	for i, candidate := range sections {
+		if !matched[i] && candidate.Number == s.Number {
+			return i
+		}
+	}
+	return -1
+}
+
+// sectionLines returns the non-blank lines of a section and its
+// subsections, headings included, trimmed of surrounding whitespace
+func sectionLines(s Section) []string {
+	var b strings.Builder
+	writeSection(&b, s, 2)
+	var lines []string
+	for _, line := range strings.Split(b.String(), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines[1:] // The heading itself is compared by matchSection
+}
+
+// lineDiff counts the lines added and removed between two versions using
+// their longest common subsequence
+func lineDiff(before, after []string) (added, removed int) {
+	// lcs[i][j] is the common subsequence length of before[i:] and after[j:]
+	lcs := make([][]int, len(before)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(after)+1)
+	}
+	for i := len(before) - 1; i >= 0; i-- {
+		for j := len(after) - 1; j >= 0; j-- {
+			if before[i] == after[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	common := lcs[0][0]
+	return len(after) - common, len(before) - common
+}
@@ -0,0 +1,108 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Files in a persona folder of the personas repository
+const (
+	SynthesizedFile = "synthesized.md"
+	ProfileFile     = "persona.json"
+	AssetStatusFile = ".assets_status.json"
+	FactsFile       = "facts.json"
+	RawDir          = "raw"
+)
+
+// FolderName returns the folder of a persona in the personas repository,
+// e.g. "Ada Lovelace" -> "ada_lovelace"
+func FolderName(personaName string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.ReplaceAll(personaName, " ", "_")), "/", "_")
+}
+
+// LoadFolder reads a persona folder, such as personas/ada_lovelace in the
+// personas repository, and parses it into a Persona. synthesized.md is
+// required; persona.json, .assets_status.json and CHANGELOG.md are read
+// when present.
+// Use os.DirFS for a local checkout.
+func LoadFolder(fsys fs.FS) (*Persona, error) {
+	content, err := fs.ReadFile(fsys, SynthesizedFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", SynthesizedFile, err)
+	}
+	p, err := Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", SynthesizedFile, err)
+	}
+
+	if data, err := fs.ReadFile(fsys, ProfileFile); err == nil {
+		var profile Profile
+		if err := json.Unmarshal(data, &profile); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", ProfileFile, err)
+		}
+		p.Profile = &profile
+		if p.Name == "" {
+			p.Name = profile.Identity.Name
+		}
+		if len(p.Aliases) == 0 {
+			p.Aliases = profile.Identity.Aliases
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read %s: %w", ProfileFile, err)
+	}
+
+	if data, err := fs.ReadFile(fsys, AssetStatusFile); err == nil {
+		var status AssetStatus
+		if err := json.Unmarshal(data, &status); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", AssetStatusFile, err)
+		}
+		p.Assets = &status
+		p.Metadata = status.Metadata
+		p.Provenance = ProvenanceFromMetadata(status.Metadata)
+		if p.Name == "" {
+			p.Name = status.PersonaName
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read %s: %w", AssetStatusFile, err)
+	}
+
+	if data, err := fs.ReadFile(fsys, ChangelogFile); err == nil {
+		history, err := ParseChangelog(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", ChangelogFile, err)
+		}
+		p.History = history
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read %s: %w", ChangelogFile, err)
+	}
+
+	// Older folders have no recorded providers; fall back to the raw outputs
+	if len(p.Provenance.Providers) == 0 {
+		p.Provenance.Providers = rawProviders(fsys)
+	}
+
+	return p, nil
+}
+
+// rawProviders lists the providers with an output in raw/
+func rawProviders(fsys fs.FS) []ProviderOutput {
+	entries, err := fs.ReadDir(fsys, RawDir)
+	if err != nil {
+		return nil
+	}
+	var providers []ProviderOutput
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || path.Ext(name) != ".md" || strings.HasSuffix(name, "_thinking.md") || name == "user_supplied.md" {
+			continue
+		}
+		providers = append(providers, ProviderOutput{Provider: strings.TrimSuffix(name, ".md")})
+	}
+	sort.Slice(providers, func(i, j int) bool { return providers[i].Provider < providers[j].Provider })
+	return providers
+}
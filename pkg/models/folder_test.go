@@ -0,0 +1,17 @@
+package models
+
+import "testing"
+
+func TestFolderName(t *testing.T) {
+	tests := map[string]string{
+		"Ada Lovelace":          "ada_lovelace",
+		"ada_lovelace":          "ada_lovelace",
+		"AC/DC":                 "ac_dc",
+		"Martin Luther King Jr": "martin_luther_king_jr",
+	}
+	for name, want := range tests {
+		if got := FolderName(name); got != want {
+			t.Errorf("FolderName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
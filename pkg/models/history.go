@@ -0,0 +1,213 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Files that record a persona's earlier versions
+const (
+	ChangelogFile = "CHANGELOG.md"
+	HistoryDir    = "history"
+)
+
+// Triggers recorded in the changelog
+const (
+	TriggerResynthesis = "resynthesis"
+	TriggerUpdate      = "update request"
+	TriggerUntracked   = "untracked" // A version written before history was kept
+)
+
+var (
+	versionHeading  = regexp.MustCompile(`^## v(\d+)(?:\s+[—–-]\s+(\S+))?\s*$`)
+	changelogField  = regexp.MustCompile(`^- \*\*([^*]+):\*\*\s*(.*)$`)
+	providerPattern = regexp.MustCompile(`^(.+?)(?:\s+\((.+)\))?$`)
+	snapshotLink    = regexp.MustCompile(`\(([^()]+)\)\s*$`)
+)
+
+// SnapshotPath returns where version number is stored, relative to the
+// persona folder, e.g. history/v3.md
+func SnapshotPath(number int) string {
+	return fmt.Sprintf("%s/v%d.md", HistoryDir, number)
+}
+
+// NextVersion returns the number of the version after history
+func NextVersion(history []Version) int {
+	next := 1
+	for _, v := range history {
+		if v.Number >= next {
+			next = v.Number + 1
+		}
+	}
+	return next
+}
+
+// maxListedChanges is how many changed sections ChangeSummary names before
+// summing up the rest
+const maxListedChanges = 3
+
+// ChangeSummary describes how a persona changed between two versions,
+// e.g. "Modified 2. Voice/Communication Analysis (+12 −4); 11 sections
+// unchanged". before is nil for a new persona.
+func ChangeSummary(before, after *Persona) string {
+	if before == nil {
+		return fmt.Sprintf("Initial version with %d sections", len(after.Sections))
+	}
+
+	var parts []string
+	unchanged, more, added, removed := 0, 0, 0, 0
+	for _, c := range Diff(before, after) {
+		switch {
+		case c.Kind == ChangeUnchanged:
+			unchanged++
+		case len(parts) == maxListedChanges:
+			more++
+			added += c.Added
+			removed += c.Removed
+		case c.Kind == ChangeAdded:
+			parts = append(parts, fmt.Sprintf("Added %s", c.Heading()))
+		case c.Kind == ChangeRemoved:
+			parts = append(parts, fmt.Sprintf("Removed %s", c.Heading()))
+		default:
+			parts = append(parts, fmt.Sprintf("Modified %s (+%d −%d)", c.Heading(), c.Added, c.Removed))
+		}
+	}
+	if len(parts) == 0 {
+		return "No section changed"
+	}
+	if more > 0 {
+		parts = append(parts, fmt.Sprintf("%d more %s changed (+%d −%d)", more, pluralize(more, "section"), added, removed))
+	}
+	if unchanged > 0 {
+		parts = append(parts, fmt.Sprintf("%d %s unchanged", unchanged, pluralize(unchanged, "section")))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// RenderChangelog writes CHANGELOG.md with the newest version first
+func RenderChangelog(personaName string, history []Version) string {
+	versions := append([]Version(nil), history...)
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Number > versions[j].Number })
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Changelog: %s\n\n", personaName)
+	b.WriteString("Every version of synthesized.md, newest first. Snapshots are kept in history/.\n")
+	for _, v := range versions {
+		b.WriteString("\n")
+		b.WriteString(v.Entry())
+	}
+	return b.String()
+}
+
+// Entry renders the version's changelog entry
+func (v Version) Entry() string {
+	var b strings.Builder
+	if v.Date.IsZero() {
+		fmt.Fprintf(&b, "## v%d\n\n", v.Number)
+	} else {
+		fmt.Fprintf(&b, "## v%d — %s\n\n", v.Number, v.Date.UTC().Format("2006-01-02"))
+	}
+	if v.Trigger != "" {
+		fmt.Fprintf(&b, "- **Trigger:** %s\n", v.Trigger)
+	}
+	if len(v.Provenance.Providers) > 0 {
+		providers := make([]string, len(v.Provenance.Providers))
+		for i, p := range v.Provenance.Providers {
+			providers[i] = withModel(p.Provider, p.Model)
+		}
+		fmt.Fprintf(&b, "- **Providers:** %s\n", strings.Join(providers, ", "))
+	}
+	if v.Provenance.Synthesizer != "" {
+		fmt.Fprintf(&b, "- **Synthesizer:** %s\n", withModel(v.Provenance.Synthesizer, v.Provenance.SynthesizerModel))
+	}
+	if v.Provenance.Method != "" {
+		fmt.Fprintf(&b, "- **Method:** %s\n", v.Provenance.Method)
+	}
+	if v.Path != "" {
+		fmt.Fprintf(&b, "- **Snapshot:** [%s](%s)\n", v.Path, v.Path)
+	}
+	if v.Summary != "" {
+		fmt.Fprintf(&b, "- **Changes:** %s\n", v.Summary)
+	}
+	return b.String()
+}
+
+// ParseChangelog reads the versions in a CHANGELOG.md written by
+// RenderChangelog, oldest first
+func ParseChangelog(content string) ([]Version, error) {
+	var versions []Version
+	var current *Version
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		if m := versionHeading.FindStringSubmatch(line); m != nil {
+			number, _ := strconv.Atoi(m[1])
+			versions = append(versions, Version{Number: number})
+			current = &versions[len(versions)-1]
+			if m[2] != "" {
+				date, err := time.Parse("2006-01-02", m[2])
+				if err != nil {
+					return nil, fmt.Errorf("invalid date for v%d: %w", number, err)
+				}
+				current.Date = date
+			}
+			continue
+		}
+
+		m := changelogField.FindStringSubmatch(line)
+		if current == nil || m == nil {
+			continue
+		}
+		switch value := strings.TrimSpace(m[2]); m[1] {
+		case "Trigger":
+			current.Trigger = value
+		case "Providers":
+			for _, item := range strings.Split(value, ", ") {
+				name, model := splitModel(item)
+				current.Provenance.Providers = append(current.Provenance.Providers, ProviderOutput{Provider: name, Model: model})
+			}
+		case "Synthesizer":
+			current.Provenance.Synthesizer, current.Provenance.SynthesizerModel = splitModel(value)
+		case "Method":
+			current.Provenance.Method = value
+		case "Snapshot":
+			if link := snapshotLink.FindStringSubmatch(value); link != nil {
+				current.Path = link[1]
+			} else {
+				current.Path = value
+			}
+		case "Changes":
+			current.Summary = value
+		}
+	}
+
+	sort.SliceStable(versions, func(i, j int) bool { return versions[i].Number < versions[j].Number })
+	return versions, nil
+}
+
+// withModel formats a provider and its model, e.g. "claude (claude-opus-4)"
+func withModel(provider, model string) string {
+	if model == "" {
+		return provider
+	}
+	return fmt.Sprintf("%s (%s)", provider, model)
+}
+
+// splitModel parses a value written by withModel
+func splitModel(value string) (string, string) {
+	m := providerPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if m == nil {
+		return value, ""
+	}
+	return m[1], m[2]
+}
+
+func pluralize(n int, word string) string {
+	if n == 1 {
+		return word
+	}
+	return word + "s"
+}
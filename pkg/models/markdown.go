@@ -0,0 +1,182 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	headingPattern  = regexp.MustCompile(`^(#{1,6})\s+(.+?)\s*#*\s*$`)
+	numberPattern   = regexp.MustCompile(`^(?:Section\s+(\d+(?:\.\d+)*)[.:)]?|(\d+(?:\.\d+)*)[.:)])\s+(.+)$`)
+	aliasPattern    = regexp.MustCompile(`(?i)^[-*\s]*\**(?:aliases|also known as|nicknames?|known as)\**\s*:\**\s*(.+)$`)
+	titleSuffixes   = []string{"persona profile", "persona", "profile"} // Also stripped as prefixes
+	titleSeparators = " —–-:|"
+)
+
+// Parse turns a persona markdown document, such as synthesized.md, into a
+// Persona. The level-one heading gives the title and name; the shallowest
+// level below it gives the top-level sections, and deeper headings nest
+// inside them. Headings inside fenced code blocks are ignored.
+func Parse(markdown string) (*Persona, error) {
+	if strings.TrimSpace(markdown) == "" {
+		return nil, fmt.Errorf("empty persona document")
+	}
+
+	p := &Persona{Content: markdown}
+	lines := strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")
+
+	type heading struct {
+		line  int
+		level int
+		text  string
+	}
+	var headings []heading
+	inFence := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		if m := headingPattern.FindStringSubmatch(line); m != nil {
+			headings = append(headings, heading{line: i, level: len(m[1]), text: strings.Trim(m[2], "* ")})
+		}
+		if m := aliasPattern.FindStringSubmatch(trimmed); m != nil && p.Aliases == nil {
+			p.Aliases = splitAliases(m[1])
+		}
+	}
+
+	// The title is the first level-one heading before any section
+	start := 0
+	if len(headings) > 0 && headings[0].level == 1 {
+		p.Title = headings[0].text
+		p.Name = nameFromTitle(p.Title)
+		start = headings[0].line + 1
+		headings = headings[1:]
+	}
+
+	end := len(lines)
+	if len(headings) > 0 {
+		end = headings[0].line
+	}
+	p.Summary = body(lines[start:end])
+
+	// Build the section tree with a stack of open sections by level
+	var stack []*Section
+	var roots []*Section
+	for i, h := range headings {
+		next := len(lines)
+		if i+1 < len(headings) {
+			next = headings[i+1].line
+		}
+		section := &Section{Level: h.level, Body: body(lines[h.line+1 : next])}
+		section.Number, section.Title = splitHeading(h.text)
+
+		for len(stack) > 0 && stack[len(stack)-1].Level >= h.level {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			roots = append(roots, section)
+		} else {
+			parent := stack[len(stack)-1]
+			parent.Subsections = append(parent.Subsections, *section)
+			section = &parent.Subsections[len(parent.Subsections)-1]
+		}
+		stack = append(stack, section)
+	}
+
+	p.Sections = make([]Section, len(roots))
+	for i, root := range roots {
+		p.Sections[i] = *root
+	}
+	return p, nil
+}
+
+// Markdown renders the persona back into a markdown document. Parsing the
+// result gives the same title, summary and sections.
+func (p *Persona) Markdown() string {
+	var b strings.Builder
+	title := p.Title
+	if title == "" {
+		title = p.Name
+	}
+	if title != "" {
+		fmt.Fprintf(&b, "# %s\n\n", title)
+	}
+	if p.Summary != "" {
+		b.WriteString(p.Summary)
+		b.WriteString("\n\n")
+	}
+	for _, section := range p.Sections {
+		writeSection(&b, section, 2)
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+// writeSection renders a section and its subsections; level is used when
+// the section was built without one
+func writeSection(b *strings.Builder, s Section, level int) {
+	if s.Level > 0 {
+		level = s.Level
+	}
+	fmt.Fprintf(b, "%s %s\n\n", strings.Repeat("#", level), s.Heading())
+	if s.Body != "" {
+		b.WriteString(s.Body)
+		b.WriteString("\n\n")
+	}
+	for _, sub := range s.Subsections {
+		writeSection(b, sub, level+1)
+	}
+}
+
+// body joins section lines, dropping surrounding blank lines
+func body(lines []string) string {
+	return strings.Trim(strings.Join(lines, "\n"), "\n \t")
+}
+
+// splitHeading separates a leading section number from the heading text.
+// A bare number needs a separator ("3. Voice", "3) Voice") so headings such
+// as "1969 Moon Landing" keep their number; "Section 3 Voice" does not.
+func splitHeading(text string) (string, string) {
+	if m := numberPattern.FindStringSubmatch(text); m != nil {
+		return m[1] + m[2], strings.TrimSpace(m[3])
+	}
+	return "", text
+}
+
+// nameFromTitle strips affixes such as "— Persona Profile" from a title
+func nameFromTitle(title string) string {
+	name := title
+	for _, prefix := range titleSuffixes {
+		if len(name) > len(prefix) && strings.EqualFold(name[:len(prefix)], prefix) && strings.ContainsRune(titleSeparators, rune(name[len(prefix)])) {
+			name = strings.TrimLeft(name[len(prefix):], titleSeparators)
+			break
+		}
+	}
+	for _, suffix := range titleSuffixes {
+		cut := len(name) - len(suffix)
+		if cut > 0 && strings.EqualFold(name[cut:], suffix) && strings.ContainsRune(titleSeparators, rune(name[cut-1])) {
+			name = strings.TrimRight(name[:cut], titleSeparators)
+			break
+		}
+	}
+	if name == "" {
+		return title
+	}
+	return name
+}
+
+// splitAliases parses a comma- or semicolon-separated alias list
+func splitAliases(value string) []string {
+	var aliases []string
+	for _, alias := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' }) {
+		if alias = strings.Trim(alias, " *_\"'."); alias != "" {
+			aliases = append(aliases, alias)
+		}
+	}
+	return aliases
+}
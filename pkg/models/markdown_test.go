@@ -0,0 +1,145 @@
+package models
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseHeadings(t *testing.T) {
+	tests := []struct {
+		heading string
+		number  string
+		title   string
+	}{
+		{"## 1. Voice", "1", "Voice"},
+		{"## 2) Style", "2", "Style"},
+		{"## 3: Values", "3", "Values"},
+		{"### 10.5. Platform Adaptation Bank", "10.5", "Platform Adaptation Bank"},
+		{"## Section 4 Background", "4", "Background"},
+		{"## Section 4: Background", "4", "Background"},
+		{"## 1969 Moon Landing", "", "1969 Moon Landing"},
+		{"## 10.5 Platform Adaptation Bank", "", "10.5 Platform Adaptation Bank"},
+		{"## Core Essence", "", "Core Essence"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.heading, func(t *testing.T) {
+			p, err := Parse("# Ada Lovelace\n\n" + tt.heading + "\n\nBody text.\n")
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if len(p.Sections) != 1 {
+				t.Fatalf("Parse() gave %d sections, want 1", len(p.Sections))
+			}
+			s := p.Sections[0]
+			if s.Number != tt.number || s.Title != tt.title {
+				t.Errorf("Parse() section = (%q, %q), want (%q, %q)", s.Number, s.Title, tt.number, tt.title)
+			}
+			if s.Body != "Body text." {
+				t.Errorf("Parse() body = %q, want %q", s.Body, "Body text.")
+			}
+		})
+	}
+}
+
+func TestMarkdownRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+	}{
+		{
+			name: "template sections",
+			doc: `# Ada Lovelace — Persona Profile
+
+Mathematician and writer.
+
+## 0. Core Essence
+
+Curious and exacting.
+
+### 0.1. Drives
+
+Poetical science.
+
+## 10. Platform Adaptation
+
+### 10.5. Platform Adaptation Bank
+
+Short posts.
+`,
+		},
+		{
+			name: "unnumbered year heading",
+			doc: `# Neil Armstrong
+
+## 1969 Moon Landing
+
+One small step.
+
+## 1. Voice
+
+Measured.
+`,
+		},
+		{
+			name: "section prefix and parenthesis",
+			doc: `# Grace Hopper
+
+## Section 1: Background
+
+Navy.
+
+## 2) Voice
+
+Direct.
+`,
+		},
+		{
+			name: "heading inside code fence",
+			doc:  "# Alan Turing\n\n## 1. Examples\n\n```markdown\n## 2. Not a section\n```\n\n## 3. Voice\n\nPlain.\n",
+		},
+		{
+			name: "summary without sections",
+			doc:  "# Marie Curie\n\nPhysicist and chemist.\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, err := Parse(tt.doc)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			rendered := first.Markdown()
+			second, err := Parse(rendered)
+			if err != nil {
+				t.Fatalf("Parse(Markdown()) error = %v", err)
+			}
+
+			if second.Title != first.Title || second.Name != first.Name || second.Summary != first.Summary {
+				t.Errorf("round trip changed header: got (%q, %q, %q), want (%q, %q, %q)",
+					second.Title, second.Name, second.Summary, first.Title, first.Name, first.Summary)
+			}
+			if !reflect.DeepEqual(second.Sections, first.Sections) {
+				t.Errorf("round trip changed sections:\ngot  %+v\nwant %+v", second.Sections, first.Sections)
+			}
+			if again := second.Markdown(); again != rendered {
+				t.Errorf("Markdown() is not stable:\nfirst:\n%s\nsecond:\n%s", rendered, again)
+			}
+		})
+	}
+}
+
+func TestParseIgnoresFencedHeadings(t *testing.T) {
+	p, err := Parse("# Alan Turing\n\n## 1. Examples\n\n```\n## 2. Not a section\n```\n")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(p.Sections) != 1 {
+		t.Fatalf("Parse() gave %d sections, want 1", len(p.Sections))
+	}
+	if !strings.Contains(p.Sections[0].Body, "## 2. Not a section") {
+		t.Errorf("fenced heading missing from body: %q", p.Sections[0].Body)
+	}
+}
@@ -1,8 +1,70 @@
 package models
 
-// Persona represents a generated user persona
+import "strings"
+
+// Persona represents a generated user persona. Content holds the markdown
+// as written; Parse fills the structural fields from it, and the pipeline
+// and LoadFolder add provenance, asset status and history.
 type Persona struct {
 	Name        string
 	Content     string
 	IssueNumber int
+	// Metadata records how the persona was produced, such as the synthesizer used
+	Metadata map[string]string
+
+	// Title is the document's top-level heading, e.g. "Ada Lovelace — Persona Profile"
+	Title string
+	// Aliases lists other names the persona is known by
+	Aliases []string
+	// Summary is the text between the title and the first section
+	Summary string
+	// Sections are the top-level sections in document order
+	Sections []Section
+
+	// Profile is the structured persona.json, when one was generated
+	Profile *Profile
+	// Provenance records the providers, models and prompts behind the persona
+	Provenance Provenance
+	// Assets is the asset generation status from .assets_status.json
+	Assets *AssetStatus
+	// History lists the versions recorded in CHANGELOG.md, oldest first;
+	// the last one is the current synthesized.md
+	History []Version
+}
+
+// Section is a heading and the markdown below it up to the next heading of
+// the same or a higher level
+type Section struct {
+	Number      string // e.g. "1" or "10.5"; empty for unnumbered headings
+	Title       string
+	Level       int // Markdown heading level, 2 for "##"
+	Body        string
+	Subsections []Section
+}
+
+// Heading returns the heading text as rendered, with its number
+func (s Section) Heading() string {
+	if s.Number == "" {
+		return s.Title
+	}
+	return s.Number + ". " + s.Title
+}
+
+// Section finds a section or subsection by number or case-insensitive
+// title; it returns nil if there is none
+func (p *Persona) Section(key string) *Section {
+	return findSection(p.Sections, strings.TrimSpace(key))
+}
+
+func findSection(sections []Section, key string) *Section {
+	for i := range sections {
+		s := &sections[i]
+		if s.Number == key || strings.EqualFold(s.Title, key) {
+			return s
+		}
+		if found := findSection(s.Subsections, key); found != nil {
+			return found
+		}
+	}
+	return nil
 }
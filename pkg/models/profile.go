@@ -0,0 +1,69 @@
+package models
+
+// Profile is the structured form of a persona, stored as persona.json and
+// described by the persona.v1 JSON Schema in pkg/schema
+type Profile struct {
+	SchemaVersion     string         `json:"schema_version"`
+	Identity          Identity       `json:"identity"`
+	Background        Background     `json:"background"`
+	PersonalityTraits []Trait        `json:"personality_traits"`
+	SpeechStyle       SpeechStyle    `json:"speech_style"`
+	Values            []Value        `json:"values"`
+	Relationships     []Relationship `json:"relationships"`
+	KeyFacts          []KeyFact      `json:"key_facts"`
+}
+
+// Identity says who the persona is
+type Identity struct {
+	Name        string   `json:"name"`
+	Aliases     []string `json:"aliases,omitempty"`
+	Occupation  string   `json:"occupation,omitempty"`
+	Nationality string   `json:"nationality,omitempty"`
+	Born        string   `json:"born,omitempty"`
+	Died        string   `json:"died,omitempty"`
+	Summary     string   `json:"summary"`
+}
+
+// Background covers the persona's history
+type Background struct {
+	Summary         string   `json:"summary"`
+	Education       []string `json:"education,omitempty"`
+	Career          []string `json:"career,omitempty"`
+	FormativeEvents []string `json:"formative_events,omitempty"`
+}
+
+// Trait is one personality trait with the evidence for it
+type Trait struct {
+	Trait       string `json:"trait"`
+	Description string `json:"description"`
+	Evidence    string `json:"evidence,omitempty"`
+}
+
+// SpeechStyle describes how the persona talks and writes
+type SpeechStyle struct {
+	Tone         string   `json:"tone"`
+	Vocabulary   string   `json:"vocabulary,omitempty"`
+	Patterns     []string `json:"patterns,omitempty"`
+	Catchphrases []string `json:"catchphrases,omitempty"`
+	SampleQuotes []string `json:"sample_quotes,omitempty"`
+}
+
+// Value is something the persona cares about
+type Value struct {
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+}
+
+// Relationship is a person who matters to the persona
+type Relationship struct {
+	Name        string `json:"name"`
+	Relation    string `json:"relation"`
+	Description string `json:"description,omitempty"`
+}
+
+// KeyFact is a verifiable fact about the persona
+type KeyFact struct {
+	Fact   string `json:"fact"`
+	Year   string `json:"year,omitempty"`
+	Source string `json:"source,omitempty"`
+}
@@ -0,0 +1,115 @@
+package models
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Metadata keys used to persist provenance in .assets_status.json
+const (
+	MetadataProviders        = "providers"
+	MetadataSynthesizer      = "synthesizer"
+	MetadataSynthesizerModel = "synthesizer_model"
+	MetadataSynthesis        = "synthesis"
+	MetadataPromptVersions   = "prompt_versions"
+	MetadataGeneratedAt      = "generated_at"
+)
+
+// Provenance records how a persona was produced
+type Provenance struct {
+	// Providers lists the providers whose raw output went into the synthesis
+	Providers []ProviderOutput
+	// Synthesizer and SynthesizerModel name the provider that combined them
+	Synthesizer      string
+	SynthesizerModel string
+	// Method is how the outputs were combined: synthesized,
+	// section_synthesis, single_response or best_response
+	Method string
+	// PromptVersions maps each prompt or template file to a short hash of its contents
+	PromptVersions map[string]string
+	GeneratedAt    time.Time
+}
+
+// ProviderOutput names a contributing provider and the model it used
+type ProviderOutput struct {
+	Provider string
+	Model    string
+}
+
+// Version is a revision of a persona's synthesized.md, as recorded in CHANGELOG.md
+type Version struct {
+	Number     int
+	Date       time.Time
+	Trigger    string // What caused the revision, e.g. "issue #12" or "feedback"
+	Provenance Provenance
+	Summary    string // What changed
+	Path       string // Where the snapshot is stored, relative to the persona folder
+}
+
+// Record writes the provenance into metadata
+func (p Provenance) Record(metadata map[string]string) {
+	if len(p.Providers) > 0 {
+		pairs := make([]string, len(p.Providers))
+		for i, output := range p.Providers {
+			pairs[i] = output.Provider + "=" + output.Model
+		}
+		metadata[MetadataProviders] = strings.Join(pairs, ",")
+	}
+	if p.Synthesizer != "" {
+		metadata[MetadataSynthesizer] = p.Synthesizer
+		metadata[MetadataSynthesizerModel] = p.SynthesizerModel
+	}
+	if p.Method != "" {
+		metadata[MetadataSynthesis] = p.Method
+	}
+	if len(p.PromptVersions) > 0 {
+		names := make([]string, 0, len(p.PromptVersions))
+		for name := range p.PromptVersions {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		pairs := make([]string, len(names))
+		for i, name := range names {
+			pairs[i] = name + "=" + p.PromptVersions[name]
+		}
+		metadata[MetadataPromptVersions] = strings.Join(pairs, ",")
+	}
+	if !p.GeneratedAt.IsZero() {
+		metadata[MetadataGeneratedAt] = p.GeneratedAt.UTC().Format(time.RFC3339)
+	}
+}
+
+// ProvenanceFromMetadata reads the provenance written by Record
+func ProvenanceFromMetadata(metadata map[string]string) Provenance {
+	p := Provenance{
+		Synthesizer:      metadata[MetadataSynthesizer],
+		SynthesizerModel: metadata[MetadataSynthesizerModel],
+		Method:           metadata[MetadataSynthesis],
+	}
+	for _, pair := range splitPairs(metadata[MetadataProviders]) {
+		p.Providers = append(p.Providers, ProviderOutput{Provider: pair[0], Model: pair[1]})
+	}
+	for _, pair := range splitPairs(metadata[MetadataPromptVersions]) {
+		if p.PromptVersions == nil {
+			p.PromptVersions = make(map[string]string)
+		}
+		p.PromptVersions[pair[0]] = pair[1]
+	}
+	if at, err := time.Parse(time.RFC3339, metadata[MetadataGeneratedAt]); err == nil {
+		p.GeneratedAt = at
+	}
+	return p
+}
+
+// splitPairs parses a comma-separated list of name=value pairs
+func splitPairs(value string) [][2]string {
+	var pairs [][2]string
+	for _, item := range strings.Split(value, ",") {
+		name, val, _ := strings.Cut(strings.TrimSpace(item), "=")
+		if name != "" {
+			pairs = append(pairs, [2]string{name, val})
+		}
+	}
+	return pairs
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// AssetStatus represents the status of asset generation for a persona,
+// stored as .assets_status.json in the persona folder
+type AssetStatus struct {
+	PersonaName           string            `json:"persona_name"`
+	LastSynthesizedUpdate time.Time         `json:"last_synthesized_update"`
+	LastAssetsGeneration  time.Time         `json:"last_assets_generation"`
+	PendingAssets         []string          `json:"pending_assets"`
+	GeneratedAssets       []string          `json:"generated_assets"`
+	AssetGenerationFlags  map[string]bool   `json:"asset_generation_flags"`
+	Metadata              map[string]string `json:"metadata,omitempty"`
+}
+
+// MetadataCompleteness is the metadata key for the linter's completeness
+// score out of 100
+const MetadataCompleteness = "completeness"
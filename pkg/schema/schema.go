@@ -0,0 +1,232 @@
+// Package schema holds the versioned JSON Schemas for Studio's structured
+// output and a validator for the subset of JSON Schema they use.
+package schema
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// PersonaVersion is the schema_version written to persona.json
+const PersonaVersion = "persona.v1"
+
+// PersonaV1 is the JSON Schema for persona.json
+//
+//go:embed persona.v1.json
+var PersonaV1 []byte
+
+// Schema is a JSON Schema node. Only the keywords used by Studio's schemas
+// are supported: type, const, enum, properties, required,
+// additionalProperties, items, minItems, maxItems, minLength and maxLength.
+type Schema struct {
+	Type                 typeList           `json:"type,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Const                interface{}        `json:"const,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+}
+
+// typeList accepts "type" as a single name or a list of names
+type typeList []string
+
+func (t *typeList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = typeList{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("type must be a string or a list of strings: %w", err)
+	}
+	*t = list
+	return nil
+}
+
+// Parse parses a JSON Schema document
+func Parse(data []byte) (*Schema, error) {
+	var s Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse schema: %w", err)
+	}
+	return &s, nil
+}
+
+// ValidationError lists every place a document breaks its schema
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("document does not match the schema: %s", strings.Join(e.Problems, "; "))
+}
+
+// Validate checks a JSON document against the schema. It returns a
+// *ValidationError describing every problem, or an error if the document is
+// not valid JSON.
+func (s *Schema) Validate(data []byte) error {
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	var problems []string
+	s.validate("$", doc, &problems)
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// Extract returns the JSON object in a model reply, dropping code fences or
+// text around it
+func Extract(text string) ([]byte, error) {
+	start, end := strings.Index(text, "{"), strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return nil, errors.New("no JSON object in response")
+	}
+	return []byte(text[start : end+1]), nil
+}
+
+// Decode extracts the JSON object in a model reply, validates it against
+// the schema and unmarshals it into v
+func (s *Schema) Decode(text string, v interface{}) error {
+	document, err := Extract(text)
+	if err != nil {
+		return err
+	}
+	if err := s.Validate(document); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(document, v); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	return nil
+}
+
+// ValidatePersona checks a persona.json document against PersonaV1
+func ValidatePersona(data []byte) error {
+	s, err := Parse(PersonaV1)
+	if err != nil {
+		return err
+	}
+	return s.Validate(data)
+}
+
+// validate appends the problems with value at path
+func (s *Schema) validate(path string, value interface{}, problems *[]string) {
+	report := func(format string, args ...interface{}) {
+		*problems = append(*problems, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	if len(s.Type) > 0 && !s.Type.matches(value) {
+		report("expected %s, got %s", strings.Join(s.Type, " or "), typeOf(value))
+		return
+	}
+	if s.Const != nil && !reflect.DeepEqual(s.Const, value) {
+		report("must be %v", s.Const)
+	}
+	if len(s.Enum) > 0 && !contains(s.Enum, value) {
+		report("must be one of %v", s.Enum)
+	}
+
+	switch v := value.(type) {
+	case string:
+		length := utf8.RuneCountInString(v)
+		if s.MinLength != nil && length < *s.MinLength {
+			report("must be at least %d characters", *s.MinLength)
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			report("must be at most %d characters", *s.MaxLength)
+		}
+	case []interface{}:
+		if s.MinItems != nil && len(v) < *s.MinItems {
+			report("must have at least %d items", *s.MinItems)
+		}
+		if s.MaxItems != nil && len(v) > *s.MaxItems {
+			report("must have at most %d items", *s.MaxItems)
+		}
+		if s.Items != nil {
+			for i, item := range v {
+				s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, problems)
+			}
+		}
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				report("missing required property %q", name)
+			}
+		}
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			child, ok := s.Properties[name]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					report("unexpected property %q", name)
+				}
+				continue
+			}
+			child.validate(path+"."+name, v[name], problems)
+		}
+	}
+}
+
+// matches reports whether value has one of the listed types
+func (t typeList) matches(value interface{}) bool {
+	actual := typeOf(value)
+	for _, name := range t {
+		if name == actual || (name == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// typeOf returns the JSON Schema type name of a decoded JSON value
+func typeOf(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == float64(int64(v)) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func contains(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if reflect.DeepEqual(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,186 @@
+package schema
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const testSchema = `{
+	"type": "object",
+	"additionalProperties": false,
+	"required": ["name", "scores"],
+	"properties": {
+		"version": {"const": "v1"},
+		"name": {"type": "string", "minLength": 1, "maxLength": 5},
+		"nickname": {"type": ["string", "null"]},
+		"ratio": {"type": "number"},
+		"scores": {
+			"type": "array",
+			"minItems": 1,
+			"maxItems": 2,
+			"items": {
+				"type": "object",
+				"required": ["score"],
+				"properties": {
+					"score": {"type": "integer", "enum": [1, 2, 3]}
+				}
+			}
+		}
+	}
+}`
+
+func TestValidate(t *testing.T) {
+	s, err := Parse([]byte(testSchema))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		document string
+		problems []string
+	}{
+		{
+			name:     "valid",
+			document: `{"version": "v1", "name": "Ada", "nickname": null, "ratio": 1, "scores": [{"score": 2}]}`,
+		},
+		{
+			name:     "number accepts fractions",
+			document: `{"name": "Ada", "ratio": 0.5, "scores": [{"score": 1}]}`,
+		},
+		{
+			name:     "missing required",
+			document: `{"scores": [{"score": 1}]}`,
+			problems: []string{`$: missing required property "name"`},
+		},
+		{
+			name:     "unexpected property",
+			document: `{"name": "Ada", "scores": [{"score": 1}], "extra": true}`,
+			problems: []string{`$: unexpected property "extra"`},
+		},
+		{
+			name:     "wrong type",
+			document: `{"name": 7, "scores": [{"score": 1}]}`,
+			problems: []string{"$.name: expected string, got integer"},
+		},
+		{
+			name:     "integer rejects fractions",
+			document: `{"name": "Ada", "scores": [{"score": 1.5}]}`,
+			problems: []string{"$.scores[0].score: expected integer, got number"},
+		},
+		{
+			name:     "const and enum",
+			document: `{"version": "v2", "name": "Ada", "scores": [{"score": 4}]}`,
+			problems: []string{"$.scores[0].score: must be one of [1 2 3]", "$.version: must be v1"},
+		},
+		{
+			name:     "string length",
+			document: `{"name": "Augusta", "scores": [{"score": 1}]}`,
+			problems: []string{"$.name: must be at most 5 characters"},
+		},
+		{
+			name:     "array length",
+			document: `{"name": "", "scores": []}`,
+			problems: []string{"$.name: must be at least 1 characters", "$.scores: must have at least 1 items"},
+		},
+		{
+			name:     "nested items",
+			document: `{"name": "Ada", "scores": [{"score": 1}, {}, {"score": 2}]}`,
+			problems: []string{"$.scores: must have at most 2 items", `$.scores[1]: missing required property "score"`},
+		},
+		{
+			name:     "wrong root type",
+			document: `[]`,
+			problems: []string{"$: expected object, got array"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.Validate([]byte(tt.document))
+			if tt.problems == nil {
+				if err != nil {
+					t.Fatalf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("Validate() error = %v, want *ValidationError", err)
+			}
+			if !reflect.DeepEqual(verr.Problems, tt.problems) {
+				t.Errorf("Validate() problems = %q, want %q", verr.Problems, tt.problems)
+			}
+		})
+	}
+}
+
+func TestValidateInvalidJSON(t *testing.T) {
+	s, err := Parse([]byte(testSchema))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	err = s.Validate([]byte(`{"name": `))
+	var verr *ValidationError
+	if err == nil || errors.As(err, &verr) {
+		t.Errorf("Validate() error = %v, want a JSON syntax error", err)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	s, err := Parse([]byte(testSchema))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		reply string
+		want  string
+		valid bool
+	}{
+		{"bare object", `{"name": "Ada", "scores": [{"score": 3}]}`, "Ada", true},
+		{"code fence", "```json\n{\"name\": \"Ada\", \"scores\": [{\"score\": 3}]}\n```", "Ada", true},
+		{"surrounding text", "Here it is: {\"name\": \"Ada\", \"scores\": [{\"score\": 3}]} Done.", "Ada", true},
+		{"no object", "I cannot help with that.", "", false},
+		{"invalid document", `{"name": "Ada"}`, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v struct {
+				Name string `json:"name"`
+			}
+			err := s.Decode(tt.reply, &v)
+			if (err == nil) != tt.valid {
+				t.Fatalf("Decode() error = %v, want valid %v", err, tt.valid)
+			}
+			if v.Name != tt.want {
+				t.Errorf("Decode() name = %q, want %q", v.Name, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSchemaTypes(t *testing.T) {
+	s, err := Parse([]byte(`{"type": ["string", "null"]}`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if !reflect.DeepEqual(s.Type, typeList{"string", "null"}) {
+		t.Errorf("Parse() type = %v", s.Type)
+	}
+	if _, err := Parse([]byte(`{"type": 3}`)); err == nil {
+		t.Error("Parse() accepted a numeric type")
+	}
+}
+
+func TestPersonaV1(t *testing.T) {
+	if _, err := Parse(PersonaV1); err != nil {
+		t.Fatalf("Parse(PersonaV1) error = %v", err)
+	}
+	if err := ValidatePersona([]byte(`{}`)); err == nil {
+		t.Error("ValidatePersona() accepted an empty document")
+	}
+}